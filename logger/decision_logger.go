@@ -7,6 +7,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -313,6 +314,77 @@ type SymbolPerformance struct {
 	AvgPnL        float64 `json:"avg_pn_l"`       // 平均盈亏
 }
 
+// tradeMatcher 按 symbol_side 配对开平仓动作（区分多空持仓），AnalyzePerformance 和盈亏报表共用
+type tradeMatcher struct {
+	openActions map[string]DecisionAction
+}
+
+func newTradeMatcher() *tradeMatcher {
+	return &tradeMatcher{openActions: make(map[string]DecisionAction)}
+}
+
+// apply 处理一个决策动作：成功的开仓记为持仓（覆盖之前的开仓），成功的平仓与同方向持仓配对，
+// 配对成功时返回交易结果
+func (m *tradeMatcher) apply(action DecisionAction) (TradeOutcome, bool) {
+	if !action.Success {
+		return TradeOutcome{}, false
+	}
+
+	switch action.Action {
+	case "open_long", "open_short":
+		side := strings.TrimPrefix(action.Action, "open_")
+		m.openActions[action.Symbol+"_"+side] = action
+
+	case "close_long", "close_short":
+		side := strings.TrimPrefix(action.Action, "close_")
+		posKey := action.Symbol + "_" + side
+		open, exists := m.openActions[posKey]
+		if !exists {
+			return TradeOutcome{}, false
+		}
+		delete(m.openActions, posKey)
+		return newTradeOutcome(open, action, side), true
+	}
+	return TradeOutcome{}, false
+}
+
+// newTradeOutcome 由开仓和平仓动作计算交易结果
+func newTradeOutcome(openAction, closeAction DecisionAction, side string) TradeOutcome {
+	// 合约交易 PnL 计算：quantity × 价格差
+	// 注意：杠杆不影响绝对盈亏，只影响保证金需求
+	pnl := openAction.Quantity * (closeAction.Price - openAction.Price)
+	if side == "short" {
+		pnl = -pnl
+	}
+
+	// 计算盈亏百分比（相对保证金）
+	positionValue := openAction.Quantity * openAction.Price
+	marginUsed := 0.0
+	pnlPct := 0.0
+	if openAction.Leverage > 0 {
+		marginUsed = positionValue / float64(openAction.Leverage)
+	}
+	if marginUsed > 0 {
+		pnlPct = (pnl / marginUsed) * 100
+	}
+
+	return TradeOutcome{
+		Symbol:        closeAction.Symbol,
+		Side:          side,
+		Quantity:      openAction.Quantity,
+		Leverage:      openAction.Leverage,
+		OpenPrice:     openAction.Price,
+		ClosePrice:    closeAction.Price,
+		PositionValue: positionValue,
+		MarginUsed:    marginUsed,
+		PnL:           pnl,
+		PnLPct:        pnlPct,
+		Duration:      closeAction.Timestamp.Sub(openAction.Timestamp).String(),
+		OpenTime:      openAction.Timestamp,
+		CloseTime:     closeAction.Timestamp,
+	}
+}
+
 // AnalyzePerformance 分析最近N个周期的交易表现
 func (l *DecisionLogger) AnalyzePerformance(lookbackCycles int) (*PerformanceAnalysis, error) {
 	records, err := l.GetLatestRecords(lookbackCycles)
@@ -332,43 +404,13 @@ func (l *DecisionLogger) AnalyzePerformance(lookbackCycles int) (*PerformanceAna
 		SymbolStats:  make(map[string]*SymbolPerformance),
 	}
 
-	// 追踪持仓状态：symbol_side -> {side, openPrice, openTime, quantity, leverage}
-	openPositions := make(map[string]map[string]interface{})
-
-	// 为了避免开仓记录在窗口外导致匹配失败，需要先从所有历史记录中找出未平仓的持仓
-	// 获取更多历史记录来构建完整的持仓状态（使用更大的窗口）
+	// 为了避免开仓记录在窗口外导致匹配失败，先用窗口之前的记录（扩大3倍窗口）还原未平仓的持仓
+	matcher := newTradeMatcher()
 	allRecords, err := l.GetLatestRecords(lookbackCycles * 3) // 扩大3倍窗口
 	if err == nil && len(allRecords) > len(records) {
-		// 先从扩大的窗口中收集所有开仓记录
-		for _, record := range allRecords {
+		for _, record := range allRecords[:len(allRecords)-len(records)] {
 			for _, action := range record.Decisions {
-				if !action.Success {
-					continue
-				}
-
-				symbol := action.Symbol
-				side := ""
-				if action.Action == "open_long" || action.Action == "close_long" {
-					side = "long"
-				} else if action.Action == "open_short" || action.Action == "close_short" {
-					side = "short"
-				}
-				posKey := symbol + "_" + side
-
-				switch action.Action {
-				case "open_long", "open_short":
-					// 记录开仓
-					openPositions[posKey] = map[string]interface{}{
-						"side":      side,
-						"openPrice": action.Price,
-						"openTime":  action.Timestamp,
-						"quantity":  action.Quantity,
-						"leverage":  action.Leverage,
-					}
-				case "close_long", "close_short":
-					// 移除已平仓记录
-					delete(openPositions, posKey)
-				}
+				matcher.apply(action)
 			}
 		}
 	}
@@ -376,106 +418,39 @@ func (l *DecisionLogger) AnalyzePerformance(lookbackCycles int) (*PerformanceAna
 	// 遍历分析窗口内的记录，生成交易结果
 	for _, record := range records {
 		for _, action := range record.Decisions {
-			if !action.Success {
+			outcome, closed := matcher.apply(action)
+			if !closed {
 				continue
 			}
-
-			symbol := action.Symbol
-			side := ""
-			if action.Action == "open_long" || action.Action == "close_long" {
-				side = "long"
-			} else if action.Action == "open_short" || action.Action == "close_short" {
-				side = "short"
+			pnl := outcome.PnL
+
+			analysis.RecentTrades = append(analysis.RecentTrades, outcome)
+			analysis.TotalTrades++
+
+			// 分类交易：盈利、亏损、持平（避免将pnl=0算入亏损）
+			if pnl > 0 {
+				analysis.WinningTrades++
+				analysis.AvgWin += pnl
+			} else if pnl < 0 {
+				analysis.LosingTrades++
+				analysis.AvgLoss += pnl
 			}
-			posKey := symbol + "_" + side // 使用symbol_side作为key，区分多空持仓
-
-			switch action.Action {
-			case "open_long", "open_short":
-				// 更新开仓记录（可能已经在预填充时记录过了）
-				openPositions[posKey] = map[string]interface{}{
-					"side":      side,
-					"openPrice": action.Price,
-					"openTime":  action.Timestamp,
-					"quantity":  action.Quantity,
-					"leverage":  action.Leverage,
-				}
+			// pnl == 0 的交易不计入盈利也不计入亏损，但计入总交易数
 
-			case "close_long", "close_short":
-				// 查找对应的开仓记录（可能来自预填充或当前窗口）
-				if openPos, exists := openPositions[posKey]; exists {
-					openPrice := openPos["openPrice"].(float64)
-					openTime := openPos["openTime"].(time.Time)
-					side := openPos["side"].(string)
-					quantity := openPos["quantity"].(float64)
-					leverage := openPos["leverage"].(int)
-
-					// 计算实际盈亏（USDT）
-					// 合约交易 PnL 计算：quantity × 价格差
-					// 注意：杠杆不影响绝对盈亏，只影响保证金需求
-					var pnl float64
-					if side == "long" {
-						pnl = quantity * (action.Price - openPrice)
-					} else {
-						pnl = quantity * (openPrice - action.Price)
-					}
-
-					// 计算盈亏百分比（相对保证金）
-					positionValue := quantity * openPrice
-					marginUsed := positionValue / float64(leverage)
-					pnlPct := 0.0
-					if marginUsed > 0 {
-						pnlPct = (pnl / marginUsed) * 100
-					}
-
-					// 记录交易结果
-					outcome := TradeOutcome{
-						Symbol:        symbol,
-						Side:          side,
-						Quantity:      quantity,
-						Leverage:      leverage,
-						OpenPrice:     openPrice,
-						ClosePrice:    action.Price,
-						PositionValue: positionValue,
-						MarginUsed:    marginUsed,
-						PnL:           pnl,
-						PnLPct:        pnlPct,
-						Duration:      action.Timestamp.Sub(openTime).String(),
-						OpenTime:      openTime,
-						CloseTime:     action.Timestamp,
-					}
-
-					analysis.RecentTrades = append(analysis.RecentTrades, outcome)
-					analysis.TotalTrades++
-
-					// 分类交易：盈利、亏损、持平（避免将pnl=0算入亏损）
-					if pnl > 0 {
-						analysis.WinningTrades++
-						analysis.AvgWin += pnl
-					} else if pnl < 0 {
-						analysis.LosingTrades++
-						analysis.AvgLoss += pnl
-					}
-					// pnl == 0 的交易不计入盈利也不计入亏损，但计入总交易数
-
-					// 更新币种统计
-					if _, exists := analysis.SymbolStats[symbol]; !exists {
-						analysis.SymbolStats[symbol] = &SymbolPerformance{
-							Symbol: symbol,
-						}
-					}
-					stats := analysis.SymbolStats[symbol]
-					stats.TotalTrades++
-					stats.TotalPnL += pnl
-					if pnl > 0 {
-						stats.WinningTrades++
-					} else if pnl < 0 {
-						stats.LosingTrades++
-					}
-
-					// 移除已平仓记录
-					delete(openPositions, posKey)
+			// 更新币种统计
+			if _, exists := analysis.SymbolStats[outcome.Symbol]; !exists {
+				analysis.SymbolStats[outcome.Symbol] = &SymbolPerformance{
+					Symbol: outcome.Symbol,
 				}
 			}
+			stats := analysis.SymbolStats[outcome.Symbol]
+			stats.TotalTrades++
+			stats.TotalPnL += pnl
+			if pnl > 0 {
+				stats.WinningTrades++
+			} else if pnl < 0 {
+				stats.LosingTrades++
+			}
 		}
 	}
 
@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"nofx/market"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// estimatedTakerFeeRate 估算的单边吃单手续费率（决策日志不记录实际手续费）
+const estimatedTakerFeeRate = 0.0005
+
+// PnLSummary 单个币种的已实现盈亏汇总
+type PnLSummary struct {
+	Symbol       string  `json:"symbol"`
+	NetPnL       float64 `json:"net_pnl"`       // 净盈亏 = 毛盈亏 - 手续费 + 资金费
+	GrossPnL     float64 `json:"gross_pnl"`     // 毛盈亏（仅价格差）
+	TotalFees    float64 `json:"total_fees"`    // 估算手续费（开平仓双边吃单）
+	TotalFunding float64 `json:"total_funding"` // 持仓期间资金费（正数为收取，负数为支付）
+	TradeCount   int     `json:"trade_count"`   // 平仓交易数
+	WinRate      float64 `json:"win_rate"`      // 胜率（按净盈亏计算，百分比）
+}
+
+// GetPnLBySymbol 按币种汇总平仓时间在 [start, end] 内的已实现盈亏
+// 交易由决策日志中的开平仓动作配对得到；手续费按吃单费率估算，资金费按持仓期间的历史费率计算
+func (l *DecisionLogger) GetPnLBySymbol(start, end time.Time) (map[string]*PnLSummary, error) {
+	if end.Before(start) {
+		return nil, fmt.Errorf("结束时间不能早于开始时间")
+	}
+
+	records, err := l.getRecordsUntil(end)
+	if err != nil {
+		return nil, err
+	}
+
+	trades := matchClosedTrades(records)
+
+	// 按币种分组
+	tradesBySymbol := make(map[string][]TradeOutcome)
+	for _, trade := range trades {
+		if trade.CloseTime.Before(start) || trade.CloseTime.After(end) {
+			continue
+		}
+		tradesBySymbol[trade.Symbol] = append(tradesBySymbol[trade.Symbol], trade)
+	}
+
+	summaries := make(map[string]*PnLSummary)
+	for symbol, symbolTrades := range tradesBySymbol {
+		fundingRecords := fetchFundingForTrades(symbol, symbolTrades)
+
+		summary := &PnLSummary{Symbol: symbol}
+		winning := 0
+		for _, trade := range symbolTrades {
+			fees := (trade.Quantity*trade.OpenPrice + trade.Quantity*trade.ClosePrice) * estimatedTakerFeeRate
+			funding := tradeFunding(trade, fundingRecords)
+			net := trade.PnL - fees + funding
+
+			summary.GrossPnL += trade.PnL
+			summary.TotalFees += fees
+			summary.TotalFunding += funding
+			summary.NetPnL += net
+			summary.TradeCount++
+			if net > 0 {
+				winning++
+			}
+		}
+		summary.WinRate = float64(winning) / float64(summary.TradeCount) * 100
+		summaries[symbol] = summary
+	}
+
+	return summaries, nil
+}
+
+// SortPnLSummary 将汇总转换为切片，按净盈亏绝对值从大到小排序
+func SortPnLSummary(summaries map[string]*PnLSummary) []*PnLSummary {
+	sorted := make([]*PnLSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		sorted = append(sorted, summary)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		ai, aj := math.Abs(sorted[i].NetPnL), math.Abs(sorted[j].NetPnL)
+		if ai != aj {
+			return ai > aj
+		}
+		return sorted[i].Symbol < sorted[j].Symbol
+	})
+	return sorted
+}
+
+// PrintPnLReport 以表格形式输出各币种盈亏汇总
+func PrintPnLReport(summaries map[string]*PnLSummary, w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Symbol\tTrades\tWinRate\tGrossPnL\tFees\tFunding\tNetPnL\t")
+
+	var total PnLSummary
+	for _, s := range SortPnLSummary(summaries) {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f%%\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+			s.Symbol, s.TradeCount, s.WinRate, s.GrossPnL, s.TotalFees, s.TotalFunding, s.NetPnL)
+
+		total.TradeCount += s.TradeCount
+		total.GrossPnL += s.GrossPnL
+		total.TotalFees += s.TotalFees
+		total.TotalFunding += s.TotalFunding
+		total.NetPnL += s.NetPnL
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\t\t%.2f\t%.2f\t%.2f\t%.2f\t\n",
+		total.TradeCount, total.GrossPnL, total.TotalFees, total.TotalFunding, total.NetPnL)
+
+	return tw.Flush()
+}
+
+// getRecordsUntil 读取决策时间不晚于end的所有记录（按时间正序）
+func (l *DecisionLogger) getRecordsUntil(end time.Time) ([]*DecisionRecord, error) {
+	files, err := ioutil.ReadDir(l.logDir)
+	if err != nil {
+		return nil, fmt.Errorf("读取日志目录失败: %w", err)
+	}
+
+	var records []*DecisionRecord
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(l.logDir, file.Name()))
+		if err != nil {
+			continue
+		}
+
+		var record DecisionRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			continue
+		}
+		if record.Timestamp.After(end) {
+			continue
+		}
+
+		records = append(records, &record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Timestamp.Before(records[j].Timestamp)
+	})
+	return records, nil
+}
+
+// matchClosedTrades 将开仓和平仓动作按 symbol_side 配对，生成已平仓交易
+func matchClosedTrades(records []*DecisionRecord) []TradeOutcome {
+	matcher := newTradeMatcher()
+	var trades []TradeOutcome
+	for _, record := range records {
+		for _, action := range record.Decisions {
+			if trade, closed := matcher.apply(action); closed {
+				trades = append(trades, trade)
+			}
+		}
+	}
+	return trades
+}
+
+// fetchFundingForTrades 获取覆盖所有交易持仓期间的历史资金费率，失败时返回nil（资金费记为0）
+func fetchFundingForTrades(symbol string, trades []TradeOutcome) []market.FundingRateRecord {
+	earliest := trades[0].OpenTime
+	for _, trade := range trades {
+		if trade.OpenTime.Before(earliest) {
+			earliest = trade.OpenTime
+		}
+	}
+
+	records, err := market.GetFundingRateHistory(symbol, earliest, 1000)
+	if err != nil {
+		fmt.Printf("⚠ 获取 %s 历史资金费率失败: %v\n", symbol, err)
+		return nil
+	}
+	return records
+}
+
+// tradeFunding 计算单笔交易持仓期间的资金费（正数为收取，负数为支付）
+func tradeFunding(trade TradeOutcome, records []market.FundingRateRecord) float64 {
+	funding := 0.0
+	for _, record := range records {
+		if record.FundingTime.Before(trade.OpenTime) || record.FundingTime.After(trade.CloseTime) {
+			continue
+		}
+		markPrice := record.MarkPrice
+		if markPrice == 0 {
+			markPrice = trade.OpenPrice
+		}
+		// 费率为正时多头支付，空头收取
+		payment := record.FundingRate * trade.Quantity * markPrice
+		if trade.Side == "short" {
+			payment = -payment
+		}
+		funding -= payment
+	}
+	return funding
+}
@@ -0,0 +1,216 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// TradeEvent 开平仓事件
+type TradeEvent struct {
+	EventType            string    `json:"event_type"`              // open/close/partial_close
+	Symbol               string    `json:"symbol"`                  // 币种
+	Side                 string    `json:"side"`                    // long/short
+	Quantity             float64   `json:"quantity"`                // 本次开仓或平仓数量
+	Price                float64   `json:"price"`                   // 成交价格
+	Leverage             int       `json:"leverage"`                // 杠杆倍数
+	UnrealizedPnLAtEntry float64   `json:"unrealized_pnl_at_entry"` // 事件发生时账户的未实现盈亏
+	ReasonTag            string    `json:"reason_tag"`              // 开平仓原因标签（如 stop_loss、take_profit、ai_decision）
+	Timestamp            time.Time `json:"timestamp"`               // 事件时间
+}
+
+// TradeMetrics 交易统计指标
+type TradeMetrics struct {
+	TotalTrades          int     `json:"total_trades"`           // 平仓次数（含部分平仓）
+	WinRate              float64 `json:"win_rate"`               // 胜率（百分比）
+	ProfitFactor         float64 `json:"profit_factor"`          // 总盈利 / 总亏损
+	AverageWin           float64 `json:"average_win"`            // 平均盈利
+	AverageLoss          float64 `json:"average_loss"`           // 平均亏损（负数）
+	MaxConsecutiveLosses int     `json:"max_consecutive_losses"` // 最大连续亏损次数
+}
+
+// TradeEventLog 内存中的开平仓事件日志，可选以JSON Lines格式持久化
+type TradeEventLog struct {
+	mu           sync.RWMutex
+	events       []TradeEvent
+	sessionStart time.Time
+}
+
+// NewTradeEventLog 创建事件日志，创建时间作为本次会话的开始时间
+func NewTradeEventLog() *TradeEventLog {
+	return &TradeEventLog{
+		sessionStart: time.Now(),
+	}
+}
+
+// Append 追加事件，未设置时间时使用当前时间
+func (l *TradeEventLog) Append(event TradeEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	l.mu.Lock()
+	l.events = append(l.events, event)
+	l.mu.Unlock()
+}
+
+// GetSessionTrades 获取本次会话（日志创建之后）的事件
+func (l *TradeEventLog) GetSessionTrades() []TradeEvent {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var events []TradeEvent
+	for _, event := range l.events {
+		if !event.Timestamp.Before(l.sessionStart) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// SaveToFile 以JSON Lines格式保存所有事件（覆盖原文件）
+func (l *TradeEventLog) SaveToFile(path string) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建事件日志文件失败: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, event := range l.events {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("序列化交易事件失败: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("写入事件日志文件失败: %w", err)
+	}
+	return nil
+}
+
+// LoadFromFile 从JSON Lines文件加载事件，替换内存中的事件
+func (l *TradeEventLog) LoadFromFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("打开事件日志文件失败: %w", err)
+	}
+	defer file.Close()
+
+	var events []TradeEvent
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var event TradeEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return fmt.Errorf("解析第 %d 行交易事件失败: %w", lineNum, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("读取事件日志文件失败: %w", err)
+	}
+
+	l.mu.Lock()
+	l.events = events
+	l.mu.Unlock()
+	return nil
+}
+
+// ComputeTradeMetrics 按 symbol_side 配对开平仓事件计算交易统计
+// 多次开仓按数量加权计算持仓均价，每次平仓（含部分平仓）计为一笔交易
+func ComputeTradeMetrics(events []TradeEvent) *TradeMetrics {
+	type openPosition struct {
+		quantity float64
+		avgPrice float64
+	}
+	positions := make(map[string]*openPosition)
+	metrics := &TradeMetrics{}
+
+	winningTrades, losingTrades := 0, 0
+	totalWin, totalLoss := 0.0, 0.0
+	consecutiveLosses := 0
+
+	for _, event := range events {
+		posKey := event.Symbol + "_" + event.Side
+
+		switch event.EventType {
+		case "open":
+			pos, exists := positions[posKey]
+			if !exists {
+				pos = &openPosition{}
+				positions[posKey] = pos
+			}
+			newQty := pos.quantity + event.Quantity
+			if newQty > 0 {
+				pos.avgPrice = (pos.avgPrice*pos.quantity + event.Price*event.Quantity) / newQty
+			}
+			pos.quantity = newQty
+
+		case "close", "partial_close":
+			pos, exists := positions[posKey]
+			if !exists || pos.quantity <= 0 {
+				continue
+			}
+
+			quantity := event.Quantity
+			if quantity <= 0 || quantity > pos.quantity {
+				quantity = pos.quantity
+			}
+
+			pnl := quantity * (event.Price - pos.avgPrice)
+			if event.Side == "short" {
+				pnl = -pnl
+			}
+
+			metrics.TotalTrades++
+			if pnl > 0 {
+				winningTrades++
+				totalWin += pnl
+				consecutiveLosses = 0
+			} else if pnl < 0 {
+				losingTrades++
+				totalLoss += pnl
+				consecutiveLosses++
+				if consecutiveLosses > metrics.MaxConsecutiveLosses {
+					metrics.MaxConsecutiveLosses = consecutiveLosses
+				}
+			}
+
+			pos.quantity -= quantity
+			if event.EventType == "close" || pos.quantity <= 0 {
+				delete(positions, posKey)
+			}
+		}
+	}
+
+	if metrics.TotalTrades > 0 {
+		metrics.WinRate = float64(winningTrades) / float64(metrics.TotalTrades) * 100
+	}
+	if winningTrades > 0 {
+		metrics.AverageWin = totalWin / float64(winningTrades)
+	}
+	if losingTrades > 0 {
+		metrics.AverageLoss = totalLoss / float64(losingTrades)
+	}
+	if totalLoss != 0 {
+		metrics.ProfitFactor = totalWin / (-totalLoss)
+	} else if totalWin > 0 {
+		// 只有盈利没有亏损，与 AnalyzePerformance 保持一致
+		metrics.ProfitFactor = 999.0
+	}
+
+	return metrics
+}
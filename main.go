@@ -38,6 +38,7 @@ type ConfigFile struct {
 	Leverage           LeverageConfig `json:"leverage"`
 	JWTSecret          string         `json:"jwt_secret"`
 	DataKLineTime      string         `json:"data_k_line_time"`
+	UseMidPrice        bool           `json:"use_mid_price"` // 行情价格使用买一卖一中间价
 }
 
 // syncConfigToDatabase 从config.json读取配置并同步到数据库
@@ -73,6 +74,7 @@ func syncConfigToDatabase(database *config.Database) error {
 		"max_daily_loss":       fmt.Sprintf("%.1f", configFile.MaxDailyLoss),
 		"max_drawdown":         fmt.Sprintf("%.1f", configFile.MaxDrawdown),
 		"stop_trading_minutes": strconv.Itoa(configFile.StopTradingMinutes),
+		"use_mid_price":        fmt.Sprintf("%t", configFile.UseMidPrice),
 	}
 
 	// 同步default_coins（转换为JSON字符串存储）
@@ -242,6 +244,13 @@ func main() {
 		log.Printf("✓ 已配置OI Top API")
 	}
 
+	// 设置行情价格是否使用买一卖一中间价
+	useMidPriceStr, _ := database.GetSystemConfig("use_mid_price")
+	if useMidPriceStr == "true" {
+		market.SetUseMidPrice(true)
+		log.Printf("✓ 行情价格使用买一卖一中间价")
+	}
+
 	// 创建TraderManager
 	traderManager := manager.NewTraderManager()
 
@@ -181,6 +181,110 @@ func (tm *TraderManager) LoadTradersFromDatabase(database *config.Database) erro
 	return nil
 }
 
+// exchangeCredentials 交易所配置到 AutoTraderConfig 的映射
+type exchangeCredentials struct {
+	apply   func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig)
+	testnet func(cfg *trader.AutoTraderConfig, testnet bool) // 为nil表示该交易所不支持测试网
+}
+
+// exchangeCredentialMappers 交易所ID -> 凭证映射，ID与 trader.Register 注册的名称一致
+// 不需要凭证的交易所（如 paper）不必登记
+var exchangeCredentialMappers = map[string]exchangeCredentials{
+	"binance": {apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+		cfg.BinanceAPIKey = ex.APIKey
+		cfg.BinanceSecretKey = ex.SecretKey
+	}},
+	"hyperliquid": {
+		apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+			cfg.HyperliquidPrivateKey = ex.APIKey // hyperliquid用APIKey存储private key
+			cfg.HyperliquidWalletAddr = ex.HyperliquidWalletAddr
+		},
+		testnet: func(cfg *trader.AutoTraderConfig, testnet bool) { cfg.HyperliquidTestnet = testnet },
+	},
+	"aster": {apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+		cfg.AsterUser = ex.AsterUser
+		cfg.AsterSigner = ex.AsterSigner
+		cfg.AsterPrivateKey = ex.AsterPrivateKey
+	}},
+	"bybit": {
+		apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+			cfg.BybitAPIKey = ex.APIKey
+			cfg.BybitSecretKey = ex.SecretKey
+		},
+		testnet: func(cfg *trader.AutoTraderConfig, testnet bool) { cfg.BybitTestnet = testnet },
+	},
+	"bitget": {apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+		cfg.BitgetAPIKey = ex.APIKey
+		cfg.BitgetSecretKey = ex.SecretKey
+		cfg.BitgetPassphrase = ex.Passphrase
+	}},
+	"kucoin": {apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+		cfg.KuCoinAPIKey = ex.APIKey
+		cfg.KuCoinSecretKey = ex.SecretKey
+		cfg.KuCoinPassphrase = ex.Passphrase
+	}},
+	"kraken": {
+		apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+			cfg.KrakenAPIKey = ex.APIKey
+			cfg.KrakenSecretKey = ex.SecretKey
+		},
+		testnet: func(cfg *trader.AutoTraderConfig, testnet bool) { cfg.KrakenTestnet = testnet },
+	},
+	"mexc": {apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+		cfg.MEXCAPIKey = ex.APIKey
+		cfg.MEXCSecretKey = ex.SecretKey
+	}},
+	"deribit": {
+		apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+			cfg.DeribitClientID = ex.APIKey
+			cfg.DeribitClientSecret = ex.SecretKey
+		},
+		testnet: func(cfg *trader.AutoTraderConfig, testnet bool) { cfg.DeribitTestnet = testnet },
+	},
+	"coinbase": {apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+		cfg.CoinbaseAPIKey = ex.APIKey
+		cfg.CoinbaseSecretKey = ex.SecretKey
+	}},
+	"htx": {apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+		cfg.HTXAPIKey = ex.APIKey
+		cfg.HTXSecretKey = ex.SecretKey
+	}},
+	"bitmex": {
+		apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+			cfg.BitMEXAPIKey = ex.APIKey
+			cfg.BitMEXSecretKey = ex.SecretKey
+		},
+		testnet: func(cfg *trader.AutoTraderConfig, testnet bool) { cfg.BitMEXTestnet = testnet },
+	},
+	"phemex": {
+		apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+			cfg.PhemexAPIKey = ex.APIKey
+			cfg.PhemexSecretKey = ex.SecretKey
+		},
+		testnet: func(cfg *trader.AutoTraderConfig, testnet bool) { cfg.PhemexTestnet = testnet },
+	},
+	"bingx": {apply: func(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+		cfg.BingXAPIKey = ex.APIKey
+		cfg.BingXSecretKey = ex.SecretKey
+	}},
+}
+
+// applyExchangeConfig 根据交易所类型把API密钥和测试网设置写入交易员配置
+// 交易所不支持测试网却开启了测试网时打印警告（仍连接主网）
+func applyExchangeConfig(cfg *trader.AutoTraderConfig, ex *config.ExchangeConfig) {
+	mapper, ok := exchangeCredentialMappers[ex.ID]
+	if !ok {
+		return
+	}
+
+	mapper.apply(cfg, ex)
+	if mapper.testnet != nil {
+		mapper.testnet(cfg, ex.Testnet)
+	} else if ex.Testnet {
+		log.Printf("⚠️  交易所 %s 不支持测试网，将连接主网", ex.ID)
+	}
+}
+
 // addTraderFromConfig 内部方法：从配置添加交易员（不加锁，因为调用方已加锁）
 func (tm *TraderManager) addTraderFromDB(traderCfg *config.TraderRecord, aiModelCfg *config.AIModelConfig, exchangeCfg *config.ExchangeConfig, coinPoolURL, oiTopURL string, maxDailyLoss, maxDrawdown float64, stopTradingMinutes int, defaultCoins []string) error {
 	if _, exists := tm.traders[traderCfg.ID]; exists {
@@ -221,7 +325,6 @@ func (tm *TraderManager) addTraderFromDB(traderCfg *config.TraderRecord, aiModel
 		BinanceAPIKey:         "",
 		BinanceSecretKey:      "",
 		HyperliquidPrivateKey: "",
-		HyperliquidTestnet:    exchangeCfg.Testnet,
 		CoinPoolAPIURL:        effectiveCoinPoolURL,
 		UseQwen:               aiModelCfg.Provider == "qwen",
 		DeepSeekKey:           "",
@@ -242,17 +345,7 @@ func (tm *TraderManager) addTraderFromDB(traderCfg *config.TraderRecord, aiModel
 	}
 
 	// 根据交易所类型设置API密钥
-	if exchangeCfg.ID == "binance" {
-		traderConfig.BinanceAPIKey = exchangeCfg.APIKey
-		traderConfig.BinanceSecretKey = exchangeCfg.SecretKey
-	} else if exchangeCfg.ID == "hyperliquid" {
-		traderConfig.HyperliquidPrivateKey = exchangeCfg.APIKey // hyperliquid用APIKey存储private key
-		traderConfig.HyperliquidWalletAddr = exchangeCfg.HyperliquidWalletAddr
-	} else if exchangeCfg.ID == "aster" {
-		traderConfig.AsterUser = exchangeCfg.AsterUser
-		traderConfig.AsterSigner = exchangeCfg.AsterSigner
-		traderConfig.AsterPrivateKey = exchangeCfg.AsterPrivateKey
-	}
+	applyExchangeConfig(&traderConfig, exchangeCfg)
 
 	// 根据AI模型设置API密钥
 	if aiModelCfg.Provider == "qwen" {
@@ -328,7 +421,6 @@ func (tm *TraderManager) AddTraderFromDB(traderCfg *config.TraderRecord, aiModel
 		BinanceAPIKey:         "",
 		BinanceSecretKey:      "",
 		HyperliquidPrivateKey: "",
-		HyperliquidTestnet:    exchangeCfg.Testnet,
 		CoinPoolAPIURL:        effectiveCoinPoolURL,
 		UseQwen:               aiModelCfg.Provider == "qwen",
 		DeepSeekKey:           "",
@@ -348,17 +440,7 @@ func (tm *TraderManager) AddTraderFromDB(traderCfg *config.TraderRecord, aiModel
 	}
 
 	// 根据交易所类型设置API密钥
-	if exchangeCfg.ID == "binance" {
-		traderConfig.BinanceAPIKey = exchangeCfg.APIKey
-		traderConfig.BinanceSecretKey = exchangeCfg.SecretKey
-	} else if exchangeCfg.ID == "hyperliquid" {
-		traderConfig.HyperliquidPrivateKey = exchangeCfg.APIKey // hyperliquid用APIKey存储private key
-		traderConfig.HyperliquidWalletAddr = exchangeCfg.HyperliquidWalletAddr
-	} else if exchangeCfg.ID == "aster" {
-		traderConfig.AsterUser = exchangeCfg.AsterUser
-		traderConfig.AsterSigner = exchangeCfg.AsterSigner
-		traderConfig.AsterPrivateKey = exchangeCfg.AsterPrivateKey
-	}
+	applyExchangeConfig(&traderConfig, exchangeCfg)
 
 	// 根据AI模型设置API密钥
 	if aiModelCfg.Provider == "qwen" {
@@ -889,21 +971,10 @@ func (tm *TraderManager) loadSingleTrader(traderCfg *config.TraderRecord, aiMode
 		DefaultCoins:         defaultCoins,
 		TradingCoins:         tradingCoins,
 		SystemPromptTemplate: traderCfg.SystemPromptTemplate, // 系统提示词模板
-		HyperliquidTestnet:   exchangeCfg.Testnet,            // Hyperliquid测试网
 	}
 
 	// 根据交易所类型设置API密钥
-	if exchangeCfg.ID == "binance" {
-		traderConfig.BinanceAPIKey = exchangeCfg.APIKey
-		traderConfig.BinanceSecretKey = exchangeCfg.SecretKey
-	} else if exchangeCfg.ID == "hyperliquid" {
-		traderConfig.HyperliquidPrivateKey = exchangeCfg.APIKey // hyperliquid用APIKey存储private key
-		traderConfig.HyperliquidWalletAddr = exchangeCfg.HyperliquidWalletAddr
-	} else if exchangeCfg.ID == "aster" {
-		traderConfig.AsterUser = exchangeCfg.AsterUser
-		traderConfig.AsterSigner = exchangeCfg.AsterSigner
-		traderConfig.AsterPrivateKey = exchangeCfg.AsterPrivateKey
-	}
+	applyExchangeConfig(&traderConfig, exchangeCfg)
 
 	// 根据AI模型设置API密钥
 	if aiModelCfg.Provider == "qwen" {
@@ -7,13 +7,19 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
 const (
 	baseURL = "https://fapi.binance.com"
+	// spotBaseURL 现货行情接口（用于现货与永续的对冲计算）
+	spotBaseURL = "https://api.binance.com"
 )
 
+// useMidPrice 行情价格是否使用买一卖一中间价，运行中可被切换，使用原子变量避免数据竞争
+var useMidPrice atomic.Bool
+
 type APIClient struct {
 	client *http.Client
 }
@@ -48,7 +54,15 @@ func (c *APIClient) GetExchangeInfo() (*ExchangeInfo, error) {
 }
 
 func (c *APIClient) GetKlines(symbol, interval string, limit int) ([]Kline, error) {
-	url := fmt.Sprintf("%s/fapi/v1/klines", baseURL)
+	return c.getKlines(fmt.Sprintf("%s/fapi/v1/klines", baseURL), symbol, interval, limit)
+}
+
+// GetSpotKlines 获取现货K线（与合约K线格式相同）
+func (c *APIClient) GetSpotKlines(symbol, interval string, limit int) ([]Kline, error) {
+	return c.getKlines(fmt.Sprintf("%s/api/v3/klines", spotBaseURL), symbol, interval, limit)
+}
+
+func (c *APIClient) getKlines(url, symbol, interval string, limit int) ([]Kline, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
@@ -114,6 +128,15 @@ func parseKline(kr KlineResponse) (Kline, error) {
 }
 
 func (c *APIClient) GetCurrentPrice(symbol string) (float64, error) {
+	// 开启中间价模式时使用 (ask+bid)/2，比最新成交价更能代表当前市场价格
+	if useMidPrice.Load() {
+		bid, ask, err := c.GetBestBidAsk(symbol)
+		if err != nil {
+			return 0, err
+		}
+		return (bid + ask) / 2, nil
+	}
+
 	url := fmt.Sprintf("%s/fapi/v1/ticker/price", baseURL)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -148,3 +171,170 @@ func (c *APIClient) GetCurrentPrice(symbol string) (float64, error) {
 
 	return price, nil
 }
+
+// GetTickers24hr 获取所有交易对的24小时行情
+func (c *APIClient) GetTickers24hr() ([]Ticker24hr, error) {
+	url := fmt.Sprintf("%s/fapi/v1/ticker/24hr", baseURL)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var tickers []Ticker24hr
+	err = json.Unmarshal(body, &tickers)
+	if err != nil {
+		return nil, err
+	}
+
+	return tickers, nil
+}
+
+// GetBestBidAsk 获取买一价和卖一价（无需拉取完整订单簿）
+func (c *APIClient) GetBestBidAsk(symbol string) (bid, ask float64, err error) {
+	url := fmt.Sprintf("%s/fapi/v1/ticker/bookTicker", baseURL)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	q := req.URL.Query()
+	q.Add("symbol", symbol)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var ticker BookTicker
+	err = json.Unmarshal(body, &ticker)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bid, err = strconv.ParseFloat(ticker.BidPrice, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	ask, err = strconv.ParseFloat(ticker.AskPrice, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return bid, ask, nil
+}
+
+// GetBookTickers 获取所有交易对的买一卖一价（单次请求）
+func (c *APIClient) GetBookTickers() ([]BookTicker, error) {
+	url := fmt.Sprintf("%s/fapi/v1/ticker/bookTicker", baseURL)
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var tickers []BookTicker
+	err = json.Unmarshal(body, &tickers)
+	if err != nil {
+		return nil, err
+	}
+
+	return tickers, nil
+}
+
+// GetAggTrades 获取时间区间内的归集成交（区间不能超过1小时，单次最多1000条）
+func (c *APIClient) GetAggTrades(symbol string, startTime, endTime time.Time) ([]AggTrade, error) {
+	url := fmt.Sprintf("%s/fapi/v1/aggTrades", baseURL)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("symbol", symbol)
+	q.Add("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	q.Add("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
+	q.Add("limit", "1000")
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var trades []AggTrade
+	err = json.Unmarshal(body, &trades)
+	if err != nil {
+		return nil, err
+	}
+
+	return trades, nil
+}
+
+// GetOrderBook 获取订单簿深度（limit 可选 5/10/20/50/100/500/1000）
+func (c *APIClient) GetOrderBook(symbol string, limit int) (*OrderBook, error) {
+	url := fmt.Sprintf("%s/fapi/v1/depth", baseURL)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("symbol", symbol)
+	q.Add("limit", strconv.Itoa(limit))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var book OrderBook
+	err = json.Unmarshal(body, &book)
+	if err != nil {
+		return nil, err
+	}
+
+	return &book, nil
+}
+
+// SetUseMidPrice 设置是否使用买一卖一中间价
+// 同时作用于 GetCurrentPrice 和所有交易器的 GetMarketPrice（见 UseMidPrice）；
+// Hyperliquid 只提供中间价，不受此开关影响
+func SetUseMidPrice(useMid bool) {
+	useMidPrice.Store(useMid)
+}
+
+// UseMidPrice 返回是否使用买一卖一中间价，交易器获取行情价格时据此选择最新价或中间价
+func UseMidPrice() bool {
+	return useMidPrice.Load()
+}
@@ -0,0 +1,131 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// BasisSpread 交割合约相对现货指数的基差
+type BasisSpread struct {
+	Symbol             string  // 标的交易对（如 BTCUSDT）
+	FuturesInstId      string  // 交割合约（如 BTCUSDT_250627）
+	PerpsInstId        string  // 同标的永续合约
+	SpotPrice          float64 // 现货指数价格
+	FuturesPrice       float64 // 交割合约标记价格
+	PerpsPrice         float64 // 永续合约标记价格
+	BasisPct           float64 // 基差百分比 (交割价 - 现货) / 现货
+	AnnualizedBasisPct float64 // 年化基差百分比
+	DaysToExpiry       int     // 距离交割天数
+}
+
+// basisCacheDuration 基差数据缓存有效期
+const basisCacheDuration = 30 * time.Second
+
+var (
+	basisCache     []BasisSpread
+	basisCacheTime time.Time
+	basisCacheMu   sync.Mutex
+)
+
+// GetAllBasisSpreads 获取所有USDT交割合约的基差，按年化基差从高到低排序（带30秒缓存）
+func GetAllBasisSpreads() ([]BasisSpread, error) {
+	basisCacheMu.Lock()
+	defer basisCacheMu.Unlock()
+
+	if basisCache != nil && time.Since(basisCacheTime) < basisCacheDuration {
+		return append([]BasisSpread(nil), basisCache...), nil
+	}
+
+	exchangeInfo, err := NewAPIClient().GetExchangeInfo()
+	if err != nil {
+		return nil, fmt.Errorf("获取交易规则失败: %w", err)
+	}
+
+	prices, err := getAllPremiumIndex()
+	if err != nil {
+		return nil, fmt.Errorf("获取标记价格失败: %w", err)
+	}
+
+	var spreads []BasisSpread
+	for i := range exchangeInfo.Symbols {
+		info := &exchangeInfo.Symbols[i]
+		if info.Status != "TRADING" {
+			continue
+		}
+		expiry := symbolExpiry(info)
+		if expiry == nil {
+			continue
+		}
+
+		futures, ok := prices[info.Symbol]
+		if !ok || futures.IndexPrice <= 0 {
+			continue
+		}
+
+		spread := BasisSpread{
+			Symbol:        info.Pair,
+			FuturesInstId: info.Symbol,
+			SpotPrice:     futures.IndexPrice,
+			FuturesPrice:  futures.MarkPrice,
+			BasisPct:      (futures.MarkPrice - futures.IndexPrice) / futures.IndexPrice * 100,
+			DaysToExpiry:  daysUntil(*expiry),
+		}
+		if perps, ok := prices[info.Pair]; ok {
+			spread.PerpsInstId = info.Pair
+			spread.PerpsPrice = perps.MarkPrice
+		}
+		if spread.DaysToExpiry > 0 {
+			spread.AnnualizedBasisPct = spread.BasisPct * 365 / float64(spread.DaysToExpiry)
+		}
+
+		spreads = append(spreads, spread)
+	}
+
+	sort.Slice(spreads, func(i, j int) bool {
+		return spreads[i].AnnualizedBasisPct > spreads[j].AnnualizedBasisPct
+	})
+
+	basisCache = spreads
+	basisCacheTime = time.Now()
+	return append([]BasisSpread(nil), spreads...), nil
+}
+
+// getAllPremiumIndex 获取所有合约的标记价格和指数价格
+func getAllPremiumIndex() (map[string]*FundingInfo, error) {
+	resp, err := http.Get("https://fapi.binance.com/fapi/v1/premiumIndex")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []struct {
+		Symbol     string `json:"symbol"`
+		MarkPrice  string `json:"markPrice"`
+		IndexPrice string `json:"indexPrice"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string]*FundingInfo, len(result))
+	for _, r := range result {
+		info := &FundingInfo{Symbol: r.Symbol}
+		info.MarkPrice, _ = strconv.ParseFloat(r.MarkPrice, 64)
+		info.IndexPrice, _ = strconv.ParseFloat(r.IndexPrice, 64)
+		prices[r.Symbol] = info
+	}
+
+	return prices, nil
+}
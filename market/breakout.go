@@ -0,0 +1,78 @@
+package market
+
+import (
+	"fmt"
+)
+
+// BreakoutSignal 区间突破信号
+type BreakoutSignal struct {
+	IsBreakoutUp   bool    // 当前价格突破前N根K线最高价
+	IsBreakoutDown bool    // 当前价格跌破前N根K线最低价
+	BreakoutLevel  float64 // 被突破的价位（向上为区间最高价，向下为区间最低价，未突破为0）
+	CurrentPrice   float64 // 当前价格（最新K线收盘价）
+}
+
+// GetRecentHighLow 获取最近periods根K线的最高价和最低价
+// interval: K线周期（如 "3m", "1h", "4h"）
+func GetRecentHighLow(symbol, interval string, periods int) (high, low float64, err error) {
+	if periods <= 0 {
+		return 0, 0, fmt.Errorf("K线数量必须大于0: %d", periods)
+	}
+
+	klines, err := NewAPIClient().GetKlines(Normalize(symbol), interval, periods)
+	if err != nil {
+		return 0, 0, fmt.Errorf("获取K线失败: %w", err)
+	}
+	if len(klines) == 0 {
+		return 0, 0, fmt.Errorf("%s 没有K线数据", symbol)
+	}
+
+	high, low = klineRange(klines)
+	return high, low, nil
+}
+
+// GetBreakoutSignal 判断当前价格是否突破前lookbackPeriods根已收盘K线的高低点
+// 最新一根K线尚未收盘，只用于取当前价格，不计入区间
+func GetBreakoutSignal(symbol, interval string, lookbackPeriods int) (*BreakoutSignal, error) {
+	if lookbackPeriods <= 0 {
+		return nil, fmt.Errorf("回看K线数量必须大于0: %d", lookbackPeriods)
+	}
+
+	klines, err := NewAPIClient().GetKlines(Normalize(symbol), interval, lookbackPeriods+1)
+	if err != nil {
+		return nil, fmt.Errorf("获取K线失败: %w", err)
+	}
+	if len(klines) < 2 {
+		return nil, fmt.Errorf("%s K线数据不足: %d", symbol, len(klines))
+	}
+
+	high, low := klineRange(klines[:len(klines)-1])
+	signal := &BreakoutSignal{
+		CurrentPrice: klines[len(klines)-1].Close,
+	}
+
+	if signal.CurrentPrice > high {
+		signal.IsBreakoutUp = true
+		signal.BreakoutLevel = high
+	} else if signal.CurrentPrice < low {
+		signal.IsBreakoutDown = true
+		signal.BreakoutLevel = low
+	}
+
+	return signal, nil
+}
+
+// klineRange 计算K线序列的最高价和最低价
+func klineRange(klines []Kline) (high, low float64) {
+	high = klines[0].High
+	low = klines[0].Low
+	for _, k := range klines[1:] {
+		if k.High > high {
+			high = k.High
+		}
+		if k.Low < low {
+			low = k.Low
+		}
+	}
+	return high, low
+}
@@ -15,27 +15,40 @@ type CombinedStreamsClient struct {
 	conn        *websocket.Conn
 	mu          sync.RWMutex
 	subscribers map[string]chan []byte
+	registry    *MessageRegistry // 类型化处理器，与subscribers通道并存
 	reconnect   bool
 	done        chan struct{}
 	batchSize   int // 每批订阅的流数量
+
+	url            string        // 组合流端点
+	reconnectDelay time.Duration // 断线后重连前的等待时间
 }
 
 func NewCombinedStreamsClient(batchSize int) *CombinedStreamsClient {
 	return &CombinedStreamsClient{
 		subscribers: make(map[string]chan []byte),
+		registry:    NewMessageRegistry(),
 		reconnect:   true,
 		done:        make(chan struct{}),
 		batchSize:   batchSize,
+
+		url:            "wss://fstream.binance.com/stream",
+		reconnectDelay: 3 * time.Second,
 	}
 }
 
+// Registry 返回类型化消息处理器注册表，处理器在读取协程中同步执行，不应阻塞
+func (c *CombinedStreamsClient) Registry() *MessageRegistry {
+	return c.registry
+}
+
 func (c *CombinedStreamsClient) Connect() error {
 	dialer := websocket.Dialer{
 		HandshakeTimeout: 10 * time.Second,
 	}
 
 	// 组合流使用不同的端点
-	conn, _, err := dialer.Dial("wss://fstream.binance.com/stream", nil)
+	conn, _, err := dialer.Dial(c.url, nil)
 	if err != nil {
 		return fmt.Errorf("组合流WebSocket连接失败: %v", err)
 	}
@@ -76,6 +89,42 @@ func (c *CombinedStreamsClient) BatchSubscribeKlines(symbols []string, interval
 	return nil
 }
 
+// BatchSubscribeMarkPrice 批量订阅标记价格（每秒推送）
+func (c *CombinedStreamsClient) BatchSubscribeMarkPrice(symbols []string) error {
+	batches := c.splitIntoBatches(symbols, c.batchSize)
+
+	for i, batch := range batches {
+		streams := make([]string, len(batch))
+		for j, symbol := range batch {
+			streams[j] = MarkPriceStream(symbol)
+		}
+
+		if err := c.subscribeStreams(streams); err != nil {
+			return fmt.Errorf("第 %d 批标记价格订阅失败: %v", i+1, err)
+		}
+
+		if i < len(batches)-1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	return nil
+}
+
+// BatchUnsubscribeMarkPrice 批量取消订阅标记价格
+func (c *CombinedStreamsClient) BatchUnsubscribeMarkPrice(symbols []string) error {
+	streams := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		streams[i] = MarkPriceStream(symbol)
+	}
+	return c.sendStreamsRequest("UNSUBSCRIBE", streams)
+}
+
+// MarkPriceStream 标记价格流名称
+func MarkPriceStream(symbol string) string {
+	return fmt.Sprintf("%s@markPrice@1s", strings.ToLower(symbol))
+}
+
 // splitIntoBatches 将切片分成指定大小的批次
 func (c *CombinedStreamsClient) splitIntoBatches(symbols []string, batchSize int) [][]string {
 	var batches [][]string
@@ -93,8 +142,13 @@ func (c *CombinedStreamsClient) splitIntoBatches(symbols []string, batchSize int
 
 // subscribeStreams 订阅多个流
 func (c *CombinedStreamsClient) subscribeStreams(streams []string) error {
-	subscribeMsg := map[string]interface{}{
-		"method": "SUBSCRIBE",
+	return c.sendStreamsRequest("SUBSCRIBE", streams)
+}
+
+// sendStreamsRequest 发送订阅/取消订阅请求
+func (c *CombinedStreamsClient) sendStreamsRequest(method string, streams []string) error {
+	msg := map[string]interface{}{
+		"method": method,
 		"params": streams,
 		"id":     time.Now().UnixNano(),
 	}
@@ -106,8 +160,8 @@ func (c *CombinedStreamsClient) subscribeStreams(streams []string) error {
 		return fmt.Errorf("WebSocket未连接")
 	}
 
-	log.Printf("订阅流: %v", streams)
-	return c.conn.WriteJSON(subscribeMsg)
+	log.Printf("%s 流: %v", method, streams)
+	return c.conn.WriteJSON(msg)
 }
 
 func (c *CombinedStreamsClient) readMessages() {
@@ -138,16 +192,14 @@ func (c *CombinedStreamsClient) readMessages() {
 }
 
 func (c *CombinedStreamsClient) handleCombinedMessage(message []byte) {
-	var combinedMsg struct {
-		Stream string          `json:"stream"`
-		Data   json.RawMessage `json:"data"`
-	}
-
+	var combinedMsg WSMessage
 	if err := json.Unmarshal(message, &combinedMsg); err != nil {
 		log.Printf("解析组合消息失败: %v", err)
 		return
 	}
 
+	Dispatch(c.registry, combinedMsg)
+
 	c.mu.RLock()
 	ch, exists := c.subscribers[combinedMsg.Stream]
 	c.mu.RUnlock()
@@ -175,12 +227,47 @@ func (c *CombinedStreamsClient) handleReconnect() {
 	}
 
 	log.Println("组合流尝试重新连接...")
-	time.Sleep(3 * time.Second)
+	time.Sleep(c.reconnectDelay)
 
 	if err := c.Connect(); err != nil {
 		log.Printf("组合流重新连接失败: %v", err)
 		go c.handleReconnect()
+		return
 	}
+
+	// 新连接上没有任何订阅，需要重新订阅订阅者通道和类型化处理器的流
+	if err := c.resubscribe(); err != nil {
+		log.Printf("组合流重新订阅失败: %v", err)
+	}
+}
+
+// resubscribe 重新订阅所有订阅者通道和注册表处理器的流（两者取并集）
+func (c *CombinedStreamsClient) resubscribe() error {
+	streams := c.registry.ListRegisteredChannels()
+	registered := make(map[string]bool, len(streams))
+	for _, stream := range streams {
+		registered[stream] = true
+	}
+
+	c.mu.RLock()
+	for stream := range c.subscribers {
+		if !registered[stream] {
+			streams = append(streams, stream)
+		}
+	}
+	c.mu.RUnlock()
+
+	batches := c.splitIntoBatches(streams, c.batchSize)
+	for i, batch := range batches {
+		if err := c.subscribeStreams(batch); err != nil {
+			return fmt.Errorf("第 %d 批重新订阅失败: %v", i+1, err)
+		}
+		if i < len(batches)-1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	log.Printf("组合流已重新订阅 %d 个流", len(streams))
+	return nil
 }
 
 func (c *CombinedStreamsClient) Close() {
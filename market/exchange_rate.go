@@ -0,0 +1,97 @@
+package market
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// quoteCurrency 汇率计算的中间币种（所有合约均以USDT计价）
+const quoteCurrency = "USDT"
+
+// GetInternalExchangeRate 获取 fromCcy -> toCcy 的汇率（1个fromCcy可兑换的toCcy数量）
+// 通过两个币种的USDT永续合约价格换算，USDT本身价格为1
+func GetInternalExchangeRate(fromCcy, toCcy string) (float64, error) {
+	client := NewAPIClient()
+
+	fromPrice, err := usdtPrice(client, fromCcy)
+	if err != nil {
+		return 0, err
+	}
+	toPrice, err := usdtPrice(client, toCcy)
+	if err != nil {
+		return 0, err
+	}
+
+	return fromPrice / toPrice, nil
+}
+
+// ConvertAmount 将 amount 个 fromCcy 换算为 toCcy
+func ConvertAmount(amount float64, fromCcy, toCcy string) (float64, error) {
+	rate, err := GetInternalExchangeRate(fromCcy, toCcy)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// GetCrossRates 获取多个币种两两之间的汇率矩阵 rates[from][to]
+func GetCrossRates(ccys []string) (map[string]map[string]float64, error) {
+	client := NewAPIClient()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	prices := make(map[string]float64, len(ccys))
+	var firstErr error
+
+	for _, ccy := range ccys {
+		ccy = strings.ToUpper(ccy)
+		wg.Add(1)
+		go func(ccy string) {
+			defer wg.Done()
+
+			price, err := usdtPrice(client, ccy)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			prices[ccy] = price
+		}(ccy)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	rates := make(map[string]map[string]float64, len(prices))
+	for from, fromPrice := range prices {
+		rates[from] = make(map[string]float64, len(prices))
+		for to, toPrice := range prices {
+			rates[from][to] = fromPrice / toPrice
+		}
+	}
+	return rates, nil
+}
+
+// usdtPrice 获取币种的USDT价格
+func usdtPrice(client *APIClient, ccy string) (float64, error) {
+	ccy = strings.ToUpper(ccy)
+	if ccy == quoteCurrency {
+		return 1, nil
+	}
+
+	price, err := client.GetCurrentPrice(ccy + quoteCurrency)
+	if err != nil {
+		return 0, fmt.Errorf("获取 %s 价格失败: %w", ccy, err)
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("%s 没有可用的USDT价格", ccy)
+	}
+	return price, nil
+}
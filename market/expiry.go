@@ -0,0 +1,95 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// GetInstrumentExpiry 获取合约交割时间，永续合约返回nil
+// symbol 需为完整合约名（交割合约如 BTCUSDT_250627）
+func GetInstrumentExpiry(symbol string) (*time.Time, error) {
+	info, err := findSymbolInfo(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return symbolExpiry(info), nil
+}
+
+// GetDaysToExpiry 获取距离交割的天数（向上取整），已到期返回0
+func GetDaysToExpiry(symbol string) (int, error) {
+	expiry, err := GetInstrumentExpiry(symbol)
+	if err != nil {
+		return 0, err
+	}
+	if expiry == nil {
+		return 0, fmt.Errorf("%s 是永续合约，没有交割时间", symbol)
+	}
+	return daysUntil(*expiry), nil
+}
+
+// GetNearestExpiry 获取指定币种最近交割的USDT交割合约
+func GetNearestExpiry(baseCcy string) (*SymbolInfo, error) {
+	exchangeInfo, err := NewAPIClient().GetExchangeInfo()
+	if err != nil {
+		return nil, fmt.Errorf("获取交易规则失败: %w", err)
+	}
+
+	baseCcy = strings.ToUpper(baseCcy)
+	now := time.Now()
+
+	var nearest *SymbolInfo
+	for i := range exchangeInfo.Symbols {
+		info := &exchangeInfo.Symbols[i]
+		if info.BaseAsset != baseCcy || info.Status != "TRADING" {
+			continue
+		}
+		expiry := symbolExpiry(info)
+		if expiry == nil || expiry.Before(now) {
+			continue
+		}
+		if nearest == nil || info.DeliveryDate < nearest.DeliveryDate {
+			nearest = info
+		}
+	}
+
+	if nearest == nil {
+		return nil, fmt.Errorf("%s 没有可交易的交割合约", baseCcy)
+	}
+	return nearest, nil
+}
+
+// findSymbolInfo 从交易规则中查找合约信息
+func findSymbolInfo(symbol string) (*SymbolInfo, error) {
+	exchangeInfo, err := NewAPIClient().GetExchangeInfo()
+	if err != nil {
+		return nil, fmt.Errorf("获取交易规则失败: %w", err)
+	}
+
+	symbol = strings.ToUpper(symbol)
+	for i := range exchangeInfo.Symbols {
+		if exchangeInfo.Symbols[i].Symbol == symbol {
+			return &exchangeInfo.Symbols[i], nil
+		}
+	}
+	return nil, fmt.Errorf("未找到合约 %s", symbol)
+}
+
+// symbolExpiry 返回合约交割时间，永续合约返回nil
+func symbolExpiry(info *SymbolInfo) *time.Time {
+	if info.ContractType == "PERPETUAL" || info.DeliveryDate <= 0 {
+		return nil
+	}
+	expiry := time.UnixMilli(info.DeliveryDate)
+	return &expiry
+}
+
+// daysUntil 计算距离指定时间的天数（向上取整），已过去返回0
+func daysUntil(t time.Time) int {
+	remaining := time.Until(t)
+	if remaining <= 0 {
+		return 0
+	}
+	return int(math.Ceil(remaining.Hours() / 24))
+}
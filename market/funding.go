@@ -0,0 +1,286 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultFundingIntervalHours 默认资金费结算间隔（小时）
+const defaultFundingIntervalHours = 8
+
+// fundingIntervalCacheDuration 结算间隔缓存有效期
+const fundingIntervalCacheDuration = time.Hour
+
+var (
+	fundingIntervalCache     map[string]int
+	fundingIntervalCacheTime time.Time
+	fundingIntervalCacheMu   sync.Mutex
+)
+
+// FundingInfo 资金费率信息（来自premiumIndex）
+type FundingInfo struct {
+	Symbol          string
+	MarkPrice       float64
+	IndexPrice      float64
+	FundingRate     float64   // 下次结算的预估资金费率
+	NextFundingTime time.Time // 下次结算时间
+}
+
+// GetFundingInfo 获取资金费率、标记价格和下次结算时间
+func GetFundingInfo(symbol string) (*FundingInfo, error) {
+	symbol = Normalize(symbol)
+	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/premiumIndex?symbol=%s", symbol)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Symbol          string `json:"symbol"`
+		MarkPrice       string `json:"markPrice"`
+		IndexPrice      string `json:"indexPrice"`
+		LastFundingRate string `json:"lastFundingRate"`
+		NextFundingTime int64  `json:"nextFundingTime"`
+		InterestRate    string `json:"interestRate"`
+		Time            int64  `json:"time"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	info := &FundingInfo{
+		Symbol:          result.Symbol,
+		NextFundingTime: time.UnixMilli(result.NextFundingTime),
+	}
+	info.MarkPrice, _ = strconv.ParseFloat(result.MarkPrice, 64)
+	info.IndexPrice, _ = strconv.ParseFloat(result.IndexPrice, 64)
+	info.FundingRate, _ = strconv.ParseFloat(result.LastFundingRate, 64)
+
+	return info, nil
+}
+
+// FundingRateRecord 历史资金费率结算记录
+type FundingRateRecord struct {
+	Symbol      string
+	FundingRate float64
+	FundingTime time.Time
+	MarkPrice   float64 // 结算时的标记价格
+}
+
+// GetFundingRateHistory 获取从startTime开始的历史资金费率（按时间正序，最多1000条）
+func GetFundingRateHistory(symbol string, startTime time.Time, limit int) ([]FundingRateRecord, error) {
+	symbol = Normalize(symbol)
+	if limit <= 0 || limit > 1000 {
+		limit = 1000
+	}
+
+	url := fmt.Sprintf("https://fapi.binance.com/fapi/v1/fundingRate?symbol=%s&limit=%d", symbol, limit)
+	if !startTime.IsZero() {
+		url += fmt.Sprintf("&startTime=%d", startTime.UnixMilli())
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []struct {
+		Symbol      string `json:"symbol"`
+		FundingRate string `json:"fundingRate"`
+		FundingTime int64  `json:"fundingTime"`
+		MarkPrice   string `json:"markPrice"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	records := make([]FundingRateRecord, 0, len(result))
+	for _, r := range result {
+		record := FundingRateRecord{
+			Symbol:      r.Symbol,
+			FundingTime: time.UnixMilli(r.FundingTime),
+		}
+		record.FundingRate, _ = strconv.ParseFloat(r.FundingRate, 64)
+		record.MarkPrice, _ = strconv.ParseFloat(r.MarkPrice, 64)
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// GetFundingInterval 获取资金费结算间隔
+// fundingInfo接口只返回调整过结算间隔的交易对，未返回的使用默认8小时
+func GetFundingInterval(symbol string) (time.Duration, error) {
+	symbol = Normalize(symbol)
+
+	intervals, err := getFundingIntervals()
+	if err != nil {
+		return 0, err
+	}
+
+	hours, ok := intervals[symbol]
+	if !ok || hours <= 0 {
+		hours = defaultFundingIntervalHours
+	}
+	return time.Duration(hours) * time.Hour, nil
+}
+
+// GetFundingPerYear 获取每年资金费结算次数（用于年化计算）
+func GetFundingPerYear(symbol string) (int, error) {
+	interval, err := GetFundingInterval(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return fundingPerYear(interval), nil
+}
+
+// fundingPerYear 按结算间隔计算每年结算次数：365 * 24 / 间隔小时数
+// 间隔不足1小时视为无效，使用默认8小时
+func fundingPerYear(interval time.Duration) int {
+	hours := int(interval.Hours())
+	if hours <= 0 {
+		hours = defaultFundingIntervalHours
+	}
+	return 365 * 24 / hours
+}
+
+// GetAnnualizedFundingRate 获取年化资金费率（按实际结算间隔计算）
+func GetAnnualizedFundingRate(symbol string) (float64, error) {
+	info, err := GetFundingInfo(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	perYear, err := GetFundingPerYear(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.FundingRate * float64(perYear), nil
+}
+
+// FundingTrend 近期资金费率趋势（线性回归）
+type FundingTrend struct {
+	Slope             float64 // 每次结算的费率变化
+	R2                float64 // 拟合优度
+	CurrentRate       float64 // 最近一次已结算的费率
+	PredictedNextRate float64 // 下次结算的预估费率
+}
+
+// GetPredictedFundingRate 获取下次结算的预估资金费率
+// premiumIndex 返回的 lastFundingRate 是按当前溢价实时计算的下一期费率
+func GetPredictedFundingRate(symbol string) (float64, error) {
+	info, err := GetFundingInfo(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return info.FundingRate, nil
+}
+
+// GetFundingRateTrend 对最近periods次结算的资金费率做线性回归，返回斜率和拟合优度
+func GetFundingRateTrend(symbol string, periods int) (*FundingTrend, error) {
+	if periods < 2 {
+		return nil, fmt.Errorf("结算次数至少为2: %d", periods)
+	}
+
+	records, err := GetFundingRateHistory(symbol, time.Time{}, periods)
+	if err != nil {
+		return nil, fmt.Errorf("获取历史资金费率失败: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%s 历史资金费率不足: %d", symbol, len(records))
+	}
+
+	predicted, err := GetPredictedFundingRate(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("获取预估资金费率失败: %w", err)
+	}
+
+	// x 为结算序号，y 为资金费率
+	n := float64(len(records))
+	sumX, sumY := 0.0, 0.0
+	for i, r := range records {
+		sumX += float64(i)
+		sumY += r.FundingRate
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	sxx, sxy, syy := 0.0, 0.0, 0.0
+	for i, r := range records {
+		dx := float64(i) - meanX
+		dy := r.FundingRate - meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+
+	trend := &FundingTrend{
+		Slope:             sxy / sxx,
+		CurrentRate:       records[len(records)-1].FundingRate,
+		PredictedNextRate: predicted,
+	}
+	if syy > 0 {
+		trend.R2 = (sxy * sxy) / (sxx * syy)
+	}
+
+	return trend, nil
+}
+
+// getFundingIntervals 获取各交易对的结算间隔（小时，带1小时缓存）
+func getFundingIntervals() (map[string]int, error) {
+	fundingIntervalCacheMu.Lock()
+	defer fundingIntervalCacheMu.Unlock()
+
+	if fundingIntervalCache != nil && time.Since(fundingIntervalCacheTime) < fundingIntervalCacheDuration {
+		return fundingIntervalCache, nil
+	}
+
+	resp, err := http.Get("https://fapi.binance.com/fapi/v1/fundingInfo")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []struct {
+		Symbol               string `json:"symbol"`
+		FundingIntervalHours int    `json:"fundingIntervalHours"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	intervals := make(map[string]int, len(result))
+	for _, r := range result {
+		intervals[r.Symbol] = r.FundingIntervalHours
+	}
+
+	fundingIntervalCache = intervals
+	fundingIntervalCacheTime = time.Now()
+	return intervals, nil
+}
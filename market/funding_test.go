@@ -0,0 +1,24 @@
+package market
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFundingPerYear(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		want     int
+	}{
+		{8 * time.Hour, 1095},
+		{4 * time.Hour, 2190},
+		{1 * time.Hour, 8760},
+		{0, 1095}, // 无效间隔按默认8小时
+	}
+
+	for _, tt := range tests {
+		if got := fundingPerYear(tt.interval); got != tt.want {
+			t.Errorf("fundingPerYear(%v) = %d, want %d", tt.interval, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package market
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ComputeHedgeRatio 计算现货与永续合约之间的最小方差对冲比例
+// 对永续收益率关于现货收益率做最小二乘回归，返回斜率 beta = cov(现货, 永续) / var(现货)
+func ComputeHedgeRatio(spotSymbol, perpSymbol string, lookbackPeriods int, interval string) (float64, error) {
+	if lookbackPeriods < 2 {
+		return 0, fmt.Errorf("回看K线数量至少为2: %d", lookbackPeriods)
+	}
+
+	client := NewAPIClient()
+
+	var wg sync.WaitGroup
+	var spotKlines, perpKlines []Kline
+	var spotErr, perpErr error
+
+	// 多取一根K线，收益率序列长度为 lookbackPeriods
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		spotKlines, spotErr = client.GetSpotKlines(strings.ToUpper(spotSymbol), interval, lookbackPeriods+1)
+	}()
+	go func() {
+		defer wg.Done()
+		perpKlines, perpErr = client.GetKlines(Normalize(perpSymbol), interval, lookbackPeriods+1)
+	}()
+	wg.Wait()
+
+	if spotErr != nil {
+		return 0, fmt.Errorf("获取现货 %s K线失败: %w", spotSymbol, spotErr)
+	}
+	if perpErr != nil {
+		return 0, fmt.Errorf("获取永续 %s K线失败: %w", perpSymbol, perpErr)
+	}
+
+	// 按开盘时间对齐收盘价
+	perpCloses := make(map[int64]float64, len(perpKlines))
+	for _, k := range perpKlines {
+		perpCloses[k.OpenTime] = k.Close
+	}
+
+	var spotPrices, perpPrices []float64
+	for _, k := range spotKlines {
+		perpClose, ok := perpCloses[k.OpenTime]
+		if !ok || perpClose <= 0 || k.Close <= 0 {
+			continue
+		}
+		spotPrices = append(spotPrices, k.Close)
+		perpPrices = append(perpPrices, perpClose)
+	}
+
+	if len(spotPrices) < 3 {
+		return 0, fmt.Errorf("对齐后的K线数量不足: %d", len(spotPrices))
+	}
+
+	spotReturns := make([]float64, 0, len(spotPrices)-1)
+	perpReturns := make([]float64, 0, len(perpPrices)-1)
+	for i := 1; i < len(spotPrices); i++ {
+		spotReturns = append(spotReturns, spotPrices[i]/spotPrices[i-1]-1)
+		perpReturns = append(perpReturns, perpPrices[i]/perpPrices[i-1]-1)
+	}
+
+	spotMean, perpMean := mean(spotReturns), mean(perpReturns)
+	covariance, variance := 0.0, 0.0
+	for i := range spotReturns {
+		spotDiff := spotReturns[i] - spotMean
+		covariance += spotDiff * (perpReturns[i] - perpMean)
+		variance += spotDiff * spotDiff
+	}
+
+	if variance == 0 {
+		return 0, fmt.Errorf("现货收益率方差为0，无法计算对冲比例")
+	}
+	return covariance / variance, nil
+}
+
+// ApplyHedgeRatio 根据对冲比例计算需要做空的永续合约数量
+func ApplyHedgeRatio(spotHolding, hedgeRatio float64) float64 {
+	return spotHolding * hedgeRatio
+}
+
+// mean 计算平均值
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
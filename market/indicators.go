@@ -0,0 +1,244 @@
+package market
+
+import (
+	"fmt"
+	"math"
+)
+
+// RSISignal RSI超买超卖信号
+type RSISignal string
+
+const (
+	RSISignalBuy     RSISignal = "buy"     // RSI低于超卖线
+	RSISignalSell    RSISignal = "sell"    // RSI高于超买线
+	RSISignalNeutral RSISignal = "neutral" // 介于两者之间
+)
+
+// GetRSI 获取period*2根K线，使用Wilder平滑计算RSI（0-100）
+func GetRSI(symbol, interval string, period int) (float64, error) {
+	if period <= 0 {
+		return 0, fmt.Errorf("RSI周期必须大于0: %d", period)
+	}
+
+	klines, err := getIndicatorKlines(symbol, interval, period*2, period+1)
+	if err != nil {
+		return 0, err
+	}
+	return calculateRSI(klines, period), nil
+}
+
+// GetRSISignal 根据超卖线和超买线判断RSI信号
+func GetRSISignal(symbol, interval string, period int, oversold, overbought float64) (RSISignal, error) {
+	if oversold >= overbought {
+		return RSISignalNeutral, fmt.Errorf("超卖线(%.1f)必须低于超买线(%.1f)", oversold, overbought)
+	}
+
+	rsi, err := GetRSI(symbol, interval, period)
+	if err != nil {
+		return RSISignalNeutral, err
+	}
+
+	switch {
+	case rsi < oversold:
+		return RSISignalBuy, nil
+	case rsi > overbought:
+		return RSISignalSell, nil
+	default:
+		return RSISignalNeutral, nil
+	}
+}
+
+// getIndicatorKlines 获取计算指标所需的K线，少于minCount根时返回错误
+func getIndicatorKlines(symbol, interval string, limit, minCount int) ([]Kline, error) {
+	klines, err := NewAPIClient().GetKlines(Normalize(symbol), interval, limit)
+	if err != nil {
+		return nil, fmt.Errorf("获取K线失败: %w", err)
+	}
+	if len(klines) < minCount {
+		return nil, fmt.Errorf("%s K线数据不足: 需要 %d 根，实际 %d 根", symbol, minCount, len(klines))
+	}
+	return klines, nil
+}
+
+// BollingerBands 布林带
+type BollingerBands struct {
+	Upper     float64 // 上轨 = 中轨 + 倍数 × 标准差
+	Middle    float64 // 中轨（收盘价SMA）
+	Lower     float64 // 下轨 = 中轨 - 倍数 × 标准差
+	BandWidth float64 // 带宽 = (上轨 - 下轨) / 中轨
+	PercentB  float64 // %B = (收盘价 - 下轨) / (上轨 - 下轨)
+}
+
+// BollingerSignal 布林带信号
+type BollingerSignal string
+
+const (
+	BollingerSignalNone         BollingerSignal = "none"
+	BollingerSignalSqueeze      BollingerSignal = "squeeze"       // 带宽处于回看区间最低水平，波动率收缩
+	BollingerSignalBreakoutUp   BollingerSignal = "breakout_up"   // 收盘价高于上轨
+	BollingerSignalBreakoutDown BollingerSignal = "breakout_down" // 收盘价低于下轨
+)
+
+// bollingerLookback 布林带额外获取的K线数量，用于判断带宽收缩
+const bollingerLookback = 50
+
+// GetBollingerBands 获取period+50根K线，计算最新一根K线的布林带
+func GetBollingerBands(symbol, interval string, period int, stdMult float64) (*BollingerBands, error) {
+	if period < 2 {
+		return nil, fmt.Errorf("布林带周期至少为2: %d", period)
+	}
+
+	klines, err := getIndicatorKlines(symbol, interval, period+bollingerLookback, period)
+	if err != nil {
+		return nil, err
+	}
+	return calculateBollingerBands(klines, period, stdMult), nil
+}
+
+// GetBBSignal 判断布林带突破或收缩
+// 收盘价在带外为突破；否则当前带宽不高于过去50根K线的最低带宽时为收缩
+func GetBBSignal(symbol, interval string, period int, stdMult float64) (BollingerSignal, error) {
+	if period < 2 {
+		return BollingerSignalNone, fmt.Errorf("布林带周期至少为2: %d", period)
+	}
+
+	klines, err := getIndicatorKlines(symbol, interval, period+bollingerLookback, period+1)
+	if err != nil {
+		return BollingerSignalNone, err
+	}
+
+	bands := calculateBollingerBands(klines, period, stdMult)
+	lastClose := klines[len(klines)-1].Close
+	switch {
+	case lastClose > bands.Upper:
+		return BollingerSignalBreakoutUp, nil
+	case lastClose < bands.Lower:
+		return BollingerSignalBreakoutDown, nil
+	}
+
+	for end := period; end < len(klines); end++ {
+		if calculateBollingerBands(klines[:end], period, stdMult).BandWidth < bands.BandWidth {
+			return BollingerSignalNone, nil
+		}
+	}
+	return BollingerSignalSqueeze, nil
+}
+
+// calculateBollingerBands 使用最后period根K线的收盘价计算布林带
+func calculateBollingerBands(klines []Kline, period int, stdMult float64) *BollingerBands {
+	closes := make([]float64, period)
+	for i, k := range klines[len(klines)-period:] {
+		closes[i] = k.Close
+	}
+
+	middle := mean(closes)
+	sumSquaredDiff := 0.0
+	for _, c := range closes {
+		diff := c - middle
+		sumSquaredDiff += diff * diff
+	}
+	stdDev := math.Sqrt(sumSquaredDiff / float64(period))
+
+	bands := &BollingerBands{
+		Upper:  middle + stdMult*stdDev,
+		Middle: middle,
+		Lower:  middle - stdMult*stdDev,
+	}
+	if middle != 0 {
+		bands.BandWidth = (bands.Upper - bands.Lower) / middle
+	}
+	if width := bands.Upper - bands.Lower; width != 0 {
+		bands.PercentB = (closes[period-1] - bands.Lower) / width
+	}
+	return bands
+}
+
+// MAType 均线类型
+type MAType string
+
+const (
+	MATypeEMA MAType = "ema"
+	MATypeSMA MAType = "sma"
+)
+
+// MACrossSignal 均线交叉信号
+type MACrossSignal struct {
+	FastMA      float64 // 最新K线的快线值
+	SlowMA      float64 // 最新K线的慢线值
+	GoldenCross bool    // 最新K线快线上穿慢线
+	DeathCross  bool    // 最新K线快线下穿慢线
+}
+
+// GetEMA 计算收盘价的指数移动平均（获取period*3根K线，使初始值影响足够小）
+func GetEMA(symbol, interval string, period int) (float64, error) {
+	if period <= 0 {
+		return 0, fmt.Errorf("均线周期必须大于0: %d", period)
+	}
+
+	klines, err := getIndicatorKlines(symbol, interval, period*3, period)
+	if err != nil {
+		return 0, err
+	}
+	return calculateEMA(klines, period), nil
+}
+
+// GetSMA 计算最近period根K线收盘价的简单移动平均
+func GetSMA(symbol, interval string, period int) (float64, error) {
+	if period <= 0 {
+		return 0, fmt.Errorf("均线周期必须大于0: %d", period)
+	}
+
+	klines, err := getIndicatorKlines(symbol, interval, period, period)
+	if err != nil {
+		return 0, err
+	}
+	return calculateSMA(klines, period), nil
+}
+
+// GetMACross 判断最新一根K线上快线是否上穿（金叉）或下穿（死叉）慢线
+func GetMACross(symbol, interval string, fastPeriod, slowPeriod int, maType MAType) (*MACrossSignal, error) {
+	if fastPeriod <= 0 || fastPeriod >= slowPeriod {
+		return nil, fmt.Errorf("快线周期(%d)必须大于0且小于慢线周期(%d)", fastPeriod, slowPeriod)
+	}
+
+	var calculate func([]Kline, int) float64
+	switch maType {
+	case MATypeEMA:
+		calculate = calculateEMA
+	case MATypeSMA:
+		calculate = calculateSMA
+	default:
+		return nil, fmt.Errorf("不支持的均线类型: %s", maType)
+	}
+
+	klines, err := getIndicatorKlines(symbol, interval, slowPeriod*3, slowPeriod+1)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := klines[:len(klines)-1]
+	prevDiff := calculate(previous, fastPeriod) - calculate(previous, slowPeriod)
+
+	signal := &MACrossSignal{
+		FastMA: calculate(klines, fastPeriod),
+		SlowMA: calculate(klines, slowPeriod),
+	}
+	currDiff := signal.FastMA - signal.SlowMA
+	signal.GoldenCross = prevDiff <= 0 && currDiff > 0
+	signal.DeathCross = prevDiff >= 0 && currDiff < 0
+
+	return signal, nil
+}
+
+// calculateSMA 计算最后period根K线收盘价的简单移动平均
+func calculateSMA(klines []Kline, period int) float64 {
+	if len(klines) < period {
+		return 0
+	}
+
+	sum := 0.0
+	for _, k := range klines[len(klines)-period:] {
+		sum += k.Close
+	}
+	return sum / float64(period)
+}
@@ -0,0 +1,44 @@
+package market
+
+import (
+	"math"
+	"testing"
+)
+
+// klinesFromCloses 用收盘价构造K线
+func klinesFromCloses(closes ...float64) []Kline {
+	klines := make([]Kline, len(closes))
+	for i, c := range closes {
+		klines[i] = Kline{OpenTime: int64(i) * 60000, Open: c, High: c, Low: c, Close: c}
+	}
+	return klines
+}
+
+func TestCalculateRSI(t *testing.T) {
+	tests := []struct {
+		name   string
+		closes []float64
+		period int
+		want   float64
+	}{
+		{"全部上涨", []float64{10, 11, 12, 13, 14}, 3, 100},
+		{"全部下跌", []float64{14, 13, 12, 11, 10}, 3, 0},
+		{"涨跌相等", []float64{1, 2, 1}, 2, 50},
+		// 初始 avgGain=2/3 avgLoss=1/3 -> RS=2
+		{"初始窗口", []float64{10, 11, 12, 11}, 3, 100 - 100/3.0},
+		// Wilder平滑：avgGain=(2/3*2+2)/3=10/9 avgLoss=(1/3*2)/3=2/9 -> RS=5
+		{"Wilder平滑", []float64{10, 11, 12, 11, 13}, 3, 100 - 100/6.0},
+		// 平滑下跌：avgGain=(2/3*2)/3=4/9 avgLoss=(1/3*2+3)/3=11/9 -> RS=4/11
+		{"平滑后下跌", []float64{10, 11, 12, 11, 8}, 3, 100 - 100/(1+4.0/11)},
+		{"数据不足", []float64{10, 11, 12}, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateRSI(klinesFromCloses(tt.closes...), tt.period)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("calculateRSI(%v, %d) = %v, want %v", tt.closes, tt.period, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package market
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetInstrumentsByUnderlying 获取指定标的（如 BTCUSDT）的所有交易中合约，包括永续和交割合约
+func GetInstrumentsByUnderlying(underlying string) ([]SymbolInfo, error) {
+	underlying = strings.ToUpper(strings.ReplaceAll(underlying, "-", ""))
+	return filterInstruments(func(info SymbolInfo) bool {
+		return info.Pair == underlying
+	})
+}
+
+// GetInstrumentsBySettlement 获取以指定币种结算（保证金币种，如 USDT、USDC）的所有交易中合约
+func GetInstrumentsBySettlement(settleCcy string) ([]SymbolInfo, error) {
+	settleCcy = strings.ToUpper(settleCcy)
+	return filterInstruments(func(info SymbolInfo) bool {
+		return info.MarginAsset == settleCcy
+	})
+}
+
+// SortInstrumentsByVolume 按24小时成交额从高到低排序，没有行情的合约排在最后
+func SortInstrumentsByVolume(instruments []SymbolInfo) ([]SymbolInfo, error) {
+	tickers, err := NewAPIClient().GetTickers24hr()
+	if err != nil {
+		return nil, fmt.Errorf("获取24小时行情失败: %w", err)
+	}
+
+	volumes := make(map[string]float64, len(tickers))
+	for _, ticker := range tickers {
+		volumes[ticker.Symbol], _ = strconv.ParseFloat(ticker.QuoteVolume, 64)
+	}
+
+	sorted := append([]SymbolInfo(nil), instruments...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return volumes[sorted[i].Symbol] > volumes[sorted[j].Symbol]
+	})
+	return sorted, nil
+}
+
+// filterInstruments 获取一次交易规则后在本地筛选交易中的合约
+func filterInstruments(match func(SymbolInfo) bool) ([]SymbolInfo, error) {
+	exchangeInfo, err := NewAPIClient().GetExchangeInfo()
+	if err != nil {
+		return nil, fmt.Errorf("获取交易规则失败: %w", err)
+	}
+
+	var instruments []SymbolInfo
+	for _, info := range exchangeInfo.Symbols {
+		if info.Status == "TRADING" && match(info) {
+			instruments = append(instruments, info)
+		}
+	}
+	return instruments, nil
+}
@@ -0,0 +1,74 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GetOptimalLimitPrice 在买一和卖一之间计算限价单价格
+// 买入：买一 + aggressiveness × 价差；卖出：卖一 - aggressiveness × 价差
+// aggressiveness=0 为最被动的挂单价，1 为可立即成交的对手价，0.5 为中间价
+// 结果按合约价格步进（tick size）四舍五入
+func GetOptimalLimitPrice(symbol, side string, aggressiveness float64) (float64, error) {
+	if aggressiveness < 0 || aggressiveness > 1 {
+		return 0, fmt.Errorf("aggressiveness 必须在0到1之间: %.4f", aggressiveness)
+	}
+
+	symbol = Normalize(symbol)
+	bid, ask, err := NewAPIClient().GetBestBidAsk(symbol)
+	if err != nil {
+		return 0, fmt.Errorf("获取买一卖一价格失败: %w", err)
+	}
+	if bid <= 0 || ask <= 0 {
+		return 0, fmt.Errorf("%s 买一卖一价格无效", symbol)
+	}
+
+	var price float64
+	switch side {
+	case "buy", "long":
+		price = bid + aggressiveness*(ask-bid)
+	case "sell", "short":
+		price = ask - aggressiveness*(ask-bid)
+	default:
+		return 0, fmt.Errorf("无效的买卖方向: %s", side)
+	}
+
+	info, err := findSymbolInfo(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	return formatLimitPrice(price, info), nil
+}
+
+// formatLimitPrice 将价格四舍五入到合约的价格步进（tick size），没有步进信息时按价格精度四舍五入
+func formatLimitPrice(price float64, info *SymbolInfo) float64 {
+	decimals := info.PricePrecision
+	if tickSize := info.TickSize(); tickSize > 0 {
+		price = math.Round(price/tickSize) * tickSize
+		// 按步进的小数位再取整一次，去掉相乘产生的浮点误差
+		tickStr := strconv.FormatFloat(tickSize, 'f', -1, 64)
+		decimals = 0
+		if dot := strings.IndexByte(tickStr, '.'); dot >= 0 {
+			decimals = len(tickStr) - dot - 1
+		}
+	}
+	multiplier := math.Pow10(decimals)
+	return math.Round(price*multiplier) / multiplier
+}
+
+// TickSize 从 PRICE_FILTER 中解析价格步进，没有时返回0
+func (s *SymbolInfo) TickSize() float64 {
+	for _, filter := range s.Filters {
+		if filterType, _ := filter["filterType"].(string); filterType != "PRICE_FILTER" {
+			continue
+		}
+		if tickSizeStr, ok := filter["tickSize"].(string); ok {
+			tickSize, _ := strconv.ParseFloat(tickSizeStr, 64)
+			return tickSize
+		}
+	}
+	return 0
+}
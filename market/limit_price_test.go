@@ -0,0 +1,37 @@
+package market
+
+import "testing"
+
+func TestFormatLimitPrice(t *testing.T) {
+	tickInfo := func(tickSize string, precision int) *SymbolInfo {
+		return &SymbolInfo{
+			PricePrecision: precision,
+			Filters: []map[string]interface{}{
+				{"filterType": "LOT_SIZE", "stepSize": "0.001"},
+				{"filterType": "PRICE_FILTER", "tickSize": tickSize},
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		price float64
+		info  *SymbolInfo
+		want  float64
+	}{
+		// pricePrecision=2 时按精度取整会得到 60000.13，不是0.1的整数倍
+		{"按步进0.1取整", 60000.13, tickInfo("0.10", 2), 60000.1},
+		{"按步进0.5取整", 101.3, tickInfo("0.5", 3), 101.5},
+		{"按步进0.0001取整", 0.123456, tickInfo("0.0001", 7), 0.1235},
+		{"步进为整数", 1234.6, tickInfo("5", 0), 1235},
+		{"没有步进时按精度取整", 60000.126, &SymbolInfo{PricePrecision: 2}, 60000.13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatLimitPrice(tt.price, tt.info); got != tt.want {
+				t.Errorf("formatLimitPrice(%v) = %v, want %v", tt.price, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package market
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// liquidityDepthRange 统计订单簿深度的价格范围（距中间价±0.5%）
+const liquidityDepthRange = 0.005
+
+// liquidityCandidateCount 参与流动性评分的币种数（按24小时成交额取前N），
+// 每个候选需要一次持仓量和一次订单簿请求，限制数量以控制请求权重
+const liquidityCandidateCount = 50
+
+// liquidityCacheDuration 流动性评分缓存有效期
+const liquidityCacheDuration = rankingCacheDuration
+
+// scoredSymbol 已评分的币种
+type scoredSymbol struct {
+	symbol string
+	score  float64
+}
+
+var (
+	liquidityCache     []scoredSymbol // 按评分从高到低排序
+	liquidityCacheTime time.Time
+	liquidityCacheMu   sync.Mutex
+)
+
+// LiquidityScore 币种流动性评分
+type LiquidityScore struct {
+	Score         float64 // 综合评分（0-100）
+	SpreadPct     float64 // 买一卖一价差百分比
+	Volume24hUSDT float64 // 24小时成交额（USDT）
+	OI            float64 // 持仓价值（USDT）
+	Depth05Pct    float64 // 中间价±0.5%范围内的买卖盘总挂单价值（USDT）
+	Grade         string  // 评级 A/B/C/D
+}
+
+// GetLiquidityScore 综合价差、成交额、持仓量和订单簿深度评估币种流动性
+func GetLiquidityScore(symbol string) (*LiquidityScore, error) {
+	symbol = Normalize(symbol)
+
+	tickers, err := NewAPIClient().GetTickers24hr()
+	if err != nil {
+		return nil, fmt.Errorf("获取24小时行情失败: %w", err)
+	}
+
+	volume24h := -1.0
+	for _, ticker := range tickers {
+		if ticker.Symbol == symbol {
+			volume24h, _ = strconv.ParseFloat(ticker.QuoteVolume, 64)
+			break
+		}
+	}
+	if volume24h < 0 {
+		return nil, fmt.Errorf("未找到币种 %s 的行情", symbol)
+	}
+
+	oiData, err := getOpenInterestData(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓量失败: %w", err)
+	}
+
+	bestBid, bestAsk, err := NewAPIClient().GetBestBidAsk(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("获取买一卖一价失败: %w", err)
+	}
+
+	return computeLiquidityScore(symbol, volume24h, oiData.Latest, bestBid, bestAsk)
+}
+
+// GetTradableSymbols 返回流动性评分不低于minScore的USDT永续合约（按评分从高到低）
+// 只对24小时成交额前 liquidityCandidateCount 的币种评分，评分结果缓存60秒
+func GetTradableSymbols(minScore float64) ([]string, error) {
+	scored, err := getLiquidityScores()
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []string
+	for _, s := range scored {
+		if s.score < minScore {
+			break
+		}
+		symbols = append(symbols, s.symbol)
+	}
+	return symbols, nil
+}
+
+// getLiquidityScores 获取候选币种的流动性评分（带缓存），网络请求期间不持有锁
+func getLiquidityScores() ([]scoredSymbol, error) {
+	liquidityCacheMu.Lock()
+	if liquidityCache != nil && time.Since(liquidityCacheTime) < liquidityCacheDuration {
+		scored := liquidityCache
+		liquidityCacheMu.Unlock()
+		return scored, nil
+	}
+	liquidityCacheMu.Unlock()
+
+	candidates, err := GetTopNByVolume(liquidityCandidateCount)
+	if err != nil {
+		return nil, err
+	}
+
+	// 一次请求获取全部买一卖一价，用于计算价差
+	bookTickers, err := NewAPIClient().GetBookTickers()
+	if err != nil {
+		return nil, fmt.Errorf("获取买一卖一价失败: %w", err)
+	}
+	bestPrices := make(map[string][2]float64, len(bookTickers))
+	for _, ticker := range bookTickers {
+		bid, _ := strconv.ParseFloat(ticker.BidPrice, 64)
+		ask, _ := strconv.ParseFloat(ticker.AskPrice, 64)
+		bestPrices[ticker.Symbol] = [2]float64{bid, ask}
+	}
+
+	fillOpenInterest(candidates)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var scored []scoredSymbol
+	sem := make(chan struct{}, rankingFetchConcurrency)
+
+	for _, candidate := range candidates {
+		prices, ok := bestPrices[candidate.Symbol]
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r OIRanking, bestBid, bestAsk float64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			score, err := computeLiquidityScore(r.Symbol, r.Volume24h, r.OpenInterest, bestBid, bestAsk)
+			if err != nil {
+				log.Printf("⚠️  计算 %s 流动性评分失败: %v", r.Symbol, err)
+				return
+			}
+
+			mu.Lock()
+			scored = append(scored, scoredSymbol{symbol: r.Symbol, score: score.Score})
+			mu.Unlock()
+		}(candidate, prices[0], prices[1])
+	}
+	wg.Wait()
+
+	sort.Slice(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+
+	liquidityCacheMu.Lock()
+	liquidityCache = scored
+	liquidityCacheTime = time.Now()
+	liquidityCacheMu.Unlock()
+
+	return scored, nil
+}
+
+// computeLiquidityScore 获取订单簿深度并计算评分，openInterest 为持仓量（币），价差取自买一卖一价
+// 四项指标各占25分：价差0.1%以上得0分；成交额、持仓价值、深度按数量级线性计分
+func computeLiquidityScore(symbol string, volume24h, openInterest, bestBid, bestAsk float64) (*LiquidityScore, error) {
+	if bestBid <= 0 || bestAsk <= 0 {
+		return nil, fmt.Errorf("%s 买一卖一价格无效", symbol)
+	}
+	mid := (bestBid + bestAsk) / 2
+
+	book, err := NewAPIClient().GetOrderBook(symbol, 100)
+	if err != nil {
+		return nil, fmt.Errorf("获取订单簿失败: %w", err)
+	}
+
+	ls := &LiquidityScore{
+		SpreadPct:     (bestAsk - bestBid) / mid * 100,
+		Volume24hUSDT: volume24h,
+		OI:            openInterest * mid,
+		Depth05Pct: depthWithin(book.Bids, mid*(1-liquidityDepthRange), true) +
+			depthWithin(book.Asks, mid*(1+liquidityDepthRange), false),
+	}
+
+	spreadScore := 25 * clamp01(1-ls.SpreadPct/0.1)
+	volumeScore := 25 * clamp01((math.Log10(math.Max(ls.Volume24hUSDT, 1))-6)/3) // 100万 -> 10亿
+	oiScore := 25 * clamp01((math.Log10(math.Max(ls.OI, 1))-6)/3)                // 100万 -> 10亿
+	depthScore := 25 * clamp01((math.Log10(math.Max(ls.Depth05Pct, 1))-4)/3)     // 1万 -> 1000万
+	ls.Score = spreadScore + volumeScore + oiScore + depthScore
+
+	switch {
+	case ls.Score >= 75:
+		ls.Grade = "A"
+	case ls.Score >= 50:
+		ls.Grade = "B"
+	case ls.Score >= 25:
+		ls.Grade = "C"
+	default:
+		ls.Grade = "D"
+	}
+
+	return ls, nil
+}
+
+// depthWithin 累计价格在limit以内的挂单价值（买盘价格>=limit，卖盘价格<=limit）
+func depthWithin(levels [][]string, limit float64, isBid bool) float64 {
+	total := 0.0
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		price, _ := strconv.ParseFloat(level[0], 64)
+		qty, _ := strconv.ParseFloat(level[1], 64)
+		if (isBid && price < limit) || (!isBid && price > limit) {
+			break
+		}
+		total += price * qty
+	}
+	return total
+}
+
+// clamp01 将数值限制在 [0, 1]
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
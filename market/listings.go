@@ -0,0 +1,84 @@
+package market
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// WatchInstrumentList 定期轮询交易规则，发现新上线或下线的永续合约时回调
+// 首次获取的列表作为基准不触发回调；阻塞运行直到stop被关闭，首次获取失败时直接返回错误
+func WatchInstrumentList(pollInterval time.Duration, onAdded, onRemoved func(SymbolInfo), stop <-chan struct{}) error {
+	snapshot, err := getPerpetualInstruments()
+	if err != nil {
+		return err
+	}
+	log.Printf("📋 合约列表监控已启动，当前 %d 个永续合约", len(snapshot))
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+			current, err := getPerpetualInstruments()
+			if err != nil {
+				log.Printf("⚠️  获取合约列表失败: %v", err)
+				continue
+			}
+
+			for symbol, info := range current {
+				if _, exists := snapshot[symbol]; !exists {
+					log.Printf("🆕 新上线合约: %s", symbol)
+					if onAdded != nil {
+						onAdded(info)
+					}
+				}
+			}
+			for symbol, info := range snapshot {
+				if _, exists := current[symbol]; !exists {
+					log.Printf("🗑️ 合约已下线: %s", symbol)
+					if onRemoved != nil {
+						onRemoved(info)
+					}
+				}
+			}
+
+			snapshot = current
+		}
+	}
+}
+
+// GetNewListings 获取since之后上线的永续合约
+func GetNewListings(since time.Time) ([]SymbolInfo, error) {
+	instruments, err := getPerpetualInstruments()
+	if err != nil {
+		return nil, err
+	}
+
+	var listings []SymbolInfo
+	for _, info := range instruments {
+		if time.UnixMilli(info.OnboardDate).After(since) {
+			listings = append(listings, info)
+		}
+	}
+	return listings, nil
+}
+
+// getPerpetualInstruments 获取所有交易中的永续合约
+func getPerpetualInstruments() (map[string]SymbolInfo, error) {
+	exchangeInfo, err := NewAPIClient().GetExchangeInfo()
+	if err != nil {
+		return nil, fmt.Errorf("获取交易规则失败: %w", err)
+	}
+
+	instruments := make(map[string]SymbolInfo)
+	for _, info := range exchangeInfo.Symbols {
+		if info.Status == "TRADING" && info.ContractType == "PERPETUAL" {
+			instruments[info.Symbol] = info
+		}
+	}
+	return instruments, nil
+}
@@ -0,0 +1,72 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+)
+
+// MessageRegistry 按流名称分发WebSocket消息的类型化处理器注册表
+// 每个流的处理器在注册时绑定消息类型，分发时自动反序列化，无需在调用方按流名称做字符串判断
+type MessageRegistry struct {
+	mu       sync.RWMutex
+	handlers map[string]func(json.RawMessage) error
+}
+
+// NewMessageRegistry 创建消息处理器注册表
+func NewMessageRegistry() *MessageRegistry {
+	return &MessageRegistry{
+		handlers: make(map[string]func(json.RawMessage) error),
+	}
+}
+
+// RegisterHandler 为指定流注册类型化处理器（如 KlineWSData、TickerWSData），重复注册会覆盖
+func RegisterHandler[T any](registry *MessageRegistry, channel string, handler func(T)) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.handlers[channel] = func(data json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return fmt.Errorf("解析 %s 消息失败: %w", channel, err)
+		}
+		handler(payload)
+		return nil
+	}
+}
+
+// Dispatch 将消息分发给对应流的处理器，未注册的流直接忽略
+func Dispatch(registry *MessageRegistry, msg WSMessage) {
+	registry.mu.RLock()
+	handler, exists := registry.handlers[msg.Stream]
+	registry.mu.RUnlock()
+
+	if !exists {
+		return
+	}
+	if err := handler(msg.Data); err != nil {
+		log.Printf("⚠️  %v", err)
+	}
+}
+
+// UnregisterHandler 移除指定流的处理器
+func (r *MessageRegistry) UnregisterHandler(channel string) {
+	r.mu.Lock()
+	delete(r.handlers, channel)
+	r.mu.Unlock()
+}
+
+// ListRegisteredChannels 返回已注册处理器的流名称（按字母排序）
+func (r *MessageRegistry) ListRegisteredChannels() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	channels := make([]string, 0, len(r.handlers))
+	for channel := range r.handlers {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
@@ -0,0 +1,95 @@
+package market
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestCombinedStreamsDispatchTyped(t *testing.T) {
+	c := NewCombinedStreamsClient(50)
+
+	var got []MarkPriceWSData
+	RegisterHandler(c.Registry(), MarkPriceStream("BTCUSDT"), func(msg MarkPriceWSData) {
+		got = append(got, msg)
+	})
+
+	c.handleCombinedMessage([]byte(`{"stream":"btcusdt@markPrice@1s","data":{"e":"markPriceUpdate","s":"BTCUSDT","p":"50000.5"}}`))
+	// 未注册的流和无法解析的数据不会调用处理器
+	c.handleCombinedMessage([]byte(`{"stream":"ethusdt@markPrice@1s","data":{"s":"ETHUSDT","p":"3000"}}`))
+	c.handleCombinedMessage([]byte(`{"stream":"btcusdt@markPrice@1s","data":"invalid"}`))
+
+	if len(got) != 1 || got[0].Symbol != "BTCUSDT" || got[0].MarkPrice != "50000.5" {
+		t.Fatalf("收到的消息 = %+v, want 一条 BTCUSDT 50000.5", got)
+	}
+
+	if channels := c.Registry().ListRegisteredChannels(); !reflect.DeepEqual(channels, []string{"btcusdt@markPrice@1s"}) {
+		t.Errorf("ListRegisteredChannels = %v", channels)
+	}
+	c.Registry().UnregisterHandler(MarkPriceStream("BTCUSDT"))
+	c.handleCombinedMessage([]byte(`{"stream":"btcusdt@markPrice@1s","data":{"s":"BTCUSDT","p":"50001"}}`))
+	if len(got) != 1 {
+		t.Errorf("注销后仍收到消息: %+v", got)
+	}
+}
+
+func TestCombinedStreamsResubscribeAfterReconnect(t *testing.T) {
+	var connections int32
+	subscribed := make(chan []string, 10)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// 第一个连接立即断开，模拟断线
+		if atomic.AddInt32(&connections, 1) == 1 {
+			return
+		}
+		for {
+			var msg struct {
+				Params []string `json:"params"`
+			}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			subscribed <- msg.Params
+		}
+	}))
+	defer server.Close()
+
+	c := NewCombinedStreamsClient(50)
+	c.url = "ws" + strings.TrimPrefix(server.URL, "http")
+	c.reconnectDelay = 10 * time.Millisecond
+	defer c.Close()
+
+	// 标记价格只通过注册表处理，K线通过订阅者通道
+	RegisterHandler(c.Registry(), MarkPriceStream("BTCUSDT"), func(MarkPriceWSData) {})
+	c.AddSubscriber("ethusdt@kline_1m", 10)
+
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect 返回错误: %v", err)
+	}
+
+	var got []string
+	for len(got) < 2 {
+		select {
+		case streams := <-subscribed:
+			got = append(got, streams...)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("重连后订阅的流 = %v, want 2个流", got)
+		}
+	}
+	sort.Strings(got)
+	if want := []string{"btcusdt@markPrice@1s", "ethusdt@kline_1m"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("重连后订阅的流 = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// rollMeasureInterval Roll价差估计使用的K线周期
+const rollMeasureInterval = "1m"
+
+// GetRollMeasure 使用Roll(1984)模型从1分钟收盘价收益率的一阶自协方差估计有效价差
+// Roll = 2 × sqrt(-cov(r_t, r_{t-1}))，结果为相对价格的比例（0.001 即 0.1%）
+// 自协方差非负时模型不适用，返回0
+func GetRollMeasure(symbol string, periods int) (float64, error) {
+	if periods < 3 {
+		return 0, fmt.Errorf("K线数量至少为3: %d", periods)
+	}
+
+	klines, err := NewAPIClient().GetKlines(Normalize(symbol), rollMeasureInterval, periods+1)
+	if err != nil {
+		return 0, fmt.Errorf("获取K线失败: %w", err)
+	}
+	if len(klines) < 4 {
+		return 0, fmt.Errorf("%s K线数据不足: %d", symbol, len(klines))
+	}
+
+	returns := make([]float64, 0, len(klines)-1)
+	for i := 1; i < len(klines); i++ {
+		if klines[i-1].Close <= 0 {
+			continue
+		}
+		returns = append(returns, klines[i].Close/klines[i-1].Close-1)
+	}
+
+	cov := autocovariance(returns)
+	if cov >= 0 {
+		return 0, nil
+	}
+	return 2 * math.Sqrt(-cov), nil
+}
+
+// GetHasbrouckInformation 估算成交序列中的信息含量，作为逆向选择的代理指标
+// 将逐笔价格变动对主动成交方向（买+1/卖-1）做回归，返回拟合优度R²（0-1）
+// 数值越高说明价格变动越多由成交方向解释，即订单流携带的信息越多
+func GetHasbrouckInformation(trades []AggTrade) float64 {
+	if len(trades) < 3 {
+		return 0
+	}
+
+	var priceChanges, signs []float64
+	prevPrice, _ := strconv.ParseFloat(trades[0].Price, 64)
+	for _, trade := range trades[1:] {
+		price, err := strconv.ParseFloat(trade.Price, 64)
+		if err != nil || prevPrice <= 0 {
+			continue
+		}
+
+		sign := 1.0
+		if trade.IsBuyerMaker {
+			sign = -1.0
+		}
+		priceChanges = append(priceChanges, price-prevPrice)
+		signs = append(signs, sign)
+		prevPrice = price
+	}
+
+	if len(priceChanges) < 2 {
+		return 0
+	}
+
+	meanX, meanY := mean(signs), mean(priceChanges)
+	sxx, sxy, syy := 0.0, 0.0, 0.0
+	for i := range signs {
+		dx := signs[i] - meanX
+		dy := priceChanges[i] - meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+
+	if sxx == 0 || syy == 0 {
+		return 0
+	}
+	return (sxy * sxy) / (sxx * syy)
+}
+
+// autocovariance 计算序列的一阶自协方差
+func autocovariance(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	m := mean(values)
+	sum := 0.0
+	for i := 1; i < len(values); i++ {
+		sum += (values[i] - m) * (values[i-1] - m)
+	}
+	return sum / float64(len(values)-1)
+}
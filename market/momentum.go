@@ -0,0 +1,110 @@
+package market
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MomentumItem 单个币种的24小时涨跌幅
+type MomentumItem struct {
+	Symbol         string
+	PriceChange    float64 // 24小时价格变化
+	PriceChangePct float64 // 24小时涨跌幅（百分比）
+	Volume24hUSDT  float64 // 24小时成交额（USDT），用于流动性过滤
+}
+
+// MomentumRanking 24小时涨幅榜和跌幅榜
+type MomentumRanking struct {
+	Gainers []MomentumItem // 涨幅最大的前n个（从大到小）
+	Losers  []MomentumItem // 跌幅最大的前n个（从小到大）
+}
+
+// momentumCacheDuration 涨跌幅数据缓存有效期
+const momentumCacheDuration = time.Minute
+
+var (
+	momentumCache     []MomentumItem
+	momentumCacheTime time.Time
+	momentumCacheMu   sync.Mutex
+)
+
+// GetMomentumRanking 获取USDT永续合约24小时涨幅和跌幅前n名
+func GetMomentumRanking(n int) (*MomentumRanking, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("排行数量必须大于0: %d", n)
+	}
+
+	items, err := getMomentumItems()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].PriceChangePct > items[j].PriceChangePct
+	})
+
+	count := n
+	if count > len(items) {
+		count = len(items)
+	}
+
+	ranking := &MomentumRanking{
+		Gainers: append([]MomentumItem(nil), items[:count]...),
+		Losers:  make([]MomentumItem, 0, count),
+	}
+	for i := len(items) - 1; i >= len(items)-count; i-- {
+		ranking.Losers = append(ranking.Losers, items[i])
+	}
+
+	return ranking, nil
+}
+
+// FilterByMinVolume 过滤掉24小时成交额低于minVolUSDT的币种
+func FilterByMinVolume(items []MomentumItem, minVolUSDT float64) []MomentumItem {
+	filtered := make([]MomentumItem, 0, len(items))
+	for _, item := range items {
+		if item.Volume24hUSDT >= minVolUSDT {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// getMomentumItems 获取所有USDT永续合约的24小时涨跌幅（带1分钟缓存），返回副本供调用方排序
+func getMomentumItems() ([]MomentumItem, error) {
+	momentumCacheMu.Lock()
+	defer momentumCacheMu.Unlock()
+
+	if momentumCache != nil && time.Since(momentumCacheTime) < momentumCacheDuration {
+		return append([]MomentumItem(nil), momentumCache...), nil
+	}
+
+	tickers, err := NewAPIClient().GetTickers24hr()
+	if err != nil {
+		return nil, fmt.Errorf("获取24小时行情失败: %w", err)
+	}
+
+	items := make([]MomentumItem, 0, len(tickers))
+	for _, ticker := range tickers {
+		if !strings.HasSuffix(ticker.Symbol, "USDT") {
+			continue
+		}
+		priceChange, _ := strconv.ParseFloat(ticker.PriceChange, 64)
+		priceChangePct, _ := strconv.ParseFloat(ticker.PriceChangePercent, 64)
+		quoteVolume, _ := strconv.ParseFloat(ticker.QuoteVolume, 64)
+		items = append(items, MomentumItem{
+			Symbol:         ticker.Symbol,
+			PriceChange:    priceChange,
+			PriceChangePct: priceChangePct,
+			Volume24hUSDT:  quoteVolume,
+		})
+	}
+
+	momentumCache = items
+	momentumCacheTime = time.Now()
+	return append([]MomentumItem(nil), items...), nil
+}
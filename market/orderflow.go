@@ -0,0 +1,69 @@
+package market
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// maxOrderFlowLookback 订单流回看窗口上限（归集成交接口单次查询区间不超过1小时）
+const maxOrderFlowLookback = time.Hour
+
+// FlowImbalance 主动买卖成交量失衡
+type FlowImbalance struct {
+	BuyVolume  float64       // 主动买入成交量（币）
+	SellVolume float64       // 主动卖出成交量（币）
+	Imbalance  float64       // (买量 - 卖量) / (买量 + 卖量)，接近+1为强买压，接近-1为强卖压
+	TradeCount int           // 归集成交笔数
+	Period     time.Duration // 统计窗口
+}
+
+// GetOrderFlowImbalance 统计最近lookbackSeconds秒内主动买卖成交量的失衡程度
+func GetOrderFlowImbalance(symbol string, lookbackSeconds int) (*FlowImbalance, error) {
+	if lookbackSeconds <= 0 {
+		return nil, fmt.Errorf("回看秒数必须大于0: %d", lookbackSeconds)
+	}
+
+	period := time.Duration(lookbackSeconds) * time.Second
+	if period > maxOrderFlowLookback {
+		return nil, fmt.Errorf("回看窗口不能超过 %v", maxOrderFlowLookback)
+	}
+
+	client := NewAPIClient()
+	symbol = Normalize(symbol)
+	endTime := time.Now()
+	startTime := endTime.Add(-period)
+
+	flow := &FlowImbalance{Period: period}
+	// 单次最多返回1000条，按最后一笔成交时间继续向后翻页
+	for startTime.Before(endTime) {
+		trades, err := client.GetAggTrades(symbol, startTime, endTime)
+		if err != nil {
+			return nil, fmt.Errorf("获取 %s 成交记录失败: %w", symbol, err)
+		}
+
+		for _, trade := range trades {
+			qty, err := strconv.ParseFloat(trade.Quantity, 64)
+			if err != nil {
+				continue
+			}
+			if trade.IsBuyerMaker {
+				flow.SellVolume += qty
+			} else {
+				flow.BuyVolume += qty
+			}
+			flow.TradeCount++
+		}
+
+		if len(trades) < 1000 {
+			break
+		}
+		startTime = time.UnixMilli(trades[len(trades)-1].Time + 1)
+	}
+
+	if total := flow.BuyVolume + flow.SellVolume; total > 0 {
+		flow.Imbalance = (flow.BuyVolume - flow.SellVolume) / total
+	}
+
+	return flow, nil
+}
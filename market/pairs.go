@@ -0,0 +1,116 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// GetPairSpread 并发获取两个币种的当前价格，返回价格比 priceA / priceB
+func GetPairSpread(symbolA, symbolB string) (float64, error) {
+	client := NewAPIClient()
+
+	var wg sync.WaitGroup
+	var priceA, priceB float64
+	var errA, errB error
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		priceA, errA = client.GetCurrentPrice(Normalize(symbolA))
+	}()
+	go func() {
+		defer wg.Done()
+		priceB, errB = client.GetCurrentPrice(Normalize(symbolB))
+	}()
+	wg.Wait()
+
+	if errA != nil {
+		return 0, fmt.Errorf("获取 %s 价格失败: %w", symbolA, errA)
+	}
+	if errB != nil {
+		return 0, fmt.Errorf("获取 %s 价格失败: %w", symbolB, errB)
+	}
+	if priceB <= 0 {
+		return 0, fmt.Errorf("%s 价格无效: %.8f", symbolB, priceB)
+	}
+
+	return priceA / priceB, nil
+}
+
+// GetPairSpreadHistory 获取两个币种每根K线收盘价之比 close[A] / close[B]（按时间正序）
+// 只使用两边开盘时间一致的K线，避免新上线币种K线数量不同导致错位
+func GetPairSpreadHistory(symbolA, symbolB, interval string, periods int) ([]float64, error) {
+	if periods <= 0 {
+		return nil, fmt.Errorf("K线数量必须大于0: %d", periods)
+	}
+
+	client := NewAPIClient()
+
+	var wg sync.WaitGroup
+	var klinesA, klinesB []Kline
+	var errA, errB error
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		klinesA, errA = client.GetKlines(Normalize(symbolA), interval, periods)
+	}()
+	go func() {
+		defer wg.Done()
+		klinesB, errB = client.GetKlines(Normalize(symbolB), interval, periods)
+	}()
+	wg.Wait()
+
+	if errA != nil {
+		return nil, fmt.Errorf("获取 %s K线失败: %w", symbolA, errA)
+	}
+	if errB != nil {
+		return nil, fmt.Errorf("获取 %s K线失败: %w", symbolB, errB)
+	}
+
+	closesB := make(map[int64]float64, len(klinesB))
+	for _, k := range klinesB {
+		closesB[k.OpenTime] = k.Close
+	}
+
+	spreads := make([]float64, 0, len(klinesA))
+	for _, k := range klinesA {
+		closeB, ok := closesB[k.OpenTime]
+		if !ok || closeB <= 0 {
+			continue
+		}
+		spreads = append(spreads, k.Close/closeB)
+	}
+
+	if len(spreads) == 0 {
+		return nil, fmt.Errorf("%s 和 %s 没有时间对齐的K线", symbolA, symbolB)
+	}
+	return spreads, nil
+}
+
+// ComputeZScore 计算价差序列最后一个值的Z分数 (last - mean) / stdDev
+// 序列少于2个点或标准差为0时返回0
+func ComputeZScore(spread []float64) float64 {
+	if len(spread) < 2 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range spread {
+		sum += v
+	}
+	mean := sum / float64(len(spread))
+
+	sumSquaredDiff := 0.0
+	for _, v := range spread {
+		diff := v - mean
+		sumSquaredDiff += diff * diff
+	}
+	stdDev := math.Sqrt(sumSquaredDiff / float64(len(spread)))
+	if stdDev == 0 {
+		return 0
+	}
+
+	return (spread[len(spread)-1] - mean) / stdDev
+}
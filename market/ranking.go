@@ -0,0 +1,166 @@
+package market
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OIRanking 持仓量/成交量排行条目
+type OIRanking struct {
+	Symbol           string
+	OpenInterest     float64 // 持仓量（币）
+	OpenInterestUSDT float64 // 持仓价值（USDT）
+	Volume24h        float64 // 24小时成交额（USDT）
+
+	lastPrice float64 // 最新价，用于换算持仓价值
+}
+
+// rankingCacheDuration 排行缓存有效期
+const rankingCacheDuration = 60 * time.Second
+
+// rankingFetchConcurrency 并发获取持仓量的最大请求数
+const rankingFetchConcurrency = 10
+
+// rankingCache 带有效期的排行缓存，只在读写缓存时加锁，网络请求期间不持有锁
+type rankingCache struct {
+	mu        sync.Mutex
+	rankings  []OIRanking
+	fetchedAt time.Time
+}
+
+// get 返回未过期的缓存副本
+func (c *rankingCache) get() ([]OIRanking, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rankings == nil || time.Since(c.fetchedAt) >= rankingCacheDuration {
+		return nil, false
+	}
+	return append([]OIRanking(nil), c.rankings...), true
+}
+
+// set 写入缓存
+func (c *rankingCache) set(rankings []OIRanking) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rankings = rankings
+	c.fetchedAt = time.Now()
+}
+
+var (
+	// volumeRankingCache 仅含24小时行情数据（不含持仓量）
+	volumeRankingCache rankingCache
+	// oiRankingCache 含持仓量数据
+	oiRankingCache rankingCache
+)
+
+// GetTopNByOpenInterest 获取持仓价值最大的前n个USDT永续合约
+func GetTopNByOpenInterest(n int) ([]OIRanking, error) {
+	rankings, err := getRankings()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(rankings, func(i, j int) bool {
+		return rankings[i].OpenInterestUSDT > rankings[j].OpenInterestUSDT
+	})
+	return topN(rankings, n), nil
+}
+
+// GetTopNByVolume 获取24小时成交额最大的前n个USDT永续合约
+// 只需一次24小时行情请求，返回结果不含持仓量字段
+func GetTopNByVolume(n int) ([]OIRanking, error) {
+	rankings, err := getVolumeRankings()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(rankings, func(i, j int) bool {
+		return rankings[i].Volume24h > rankings[j].Volume24h
+	})
+	return topN(rankings, n), nil
+}
+
+// getVolumeRankings 获取USDT永续合约的24小时成交额（带60秒缓存），返回副本供调用方排序
+func getVolumeRankings() ([]OIRanking, error) {
+	if rankings, ok := volumeRankingCache.get(); ok {
+		return rankings, nil
+	}
+
+	tickers, err := NewAPIClient().GetTickers24hr()
+	if err != nil {
+		return nil, fmt.Errorf("获取24小时行情失败: %w", err)
+	}
+
+	rankings := make([]OIRanking, 0, len(tickers))
+	for _, ticker := range tickers {
+		if !strings.HasSuffix(ticker.Symbol, "USDT") {
+			continue
+		}
+		quoteVolume, _ := strconv.ParseFloat(ticker.QuoteVolume, 64)
+		lastPrice, _ := strconv.ParseFloat(ticker.LastPrice, 64)
+		rankings = append(rankings, OIRanking{
+			Symbol:    ticker.Symbol,
+			Volume24h: quoteVolume,
+			lastPrice: lastPrice,
+		})
+	}
+
+	volumeRankingCache.set(rankings)
+	return append([]OIRanking(nil), rankings...), nil
+}
+
+// getRankings 获取含持仓量的排行数据（带60秒缓存），返回副本供调用方排序
+func getRankings() ([]OIRanking, error) {
+	if rankings, ok := oiRankingCache.get(); ok {
+		return rankings, nil
+	}
+
+	rankings, err := getVolumeRankings()
+	if err != nil {
+		return nil, err
+	}
+
+	// 24小时行情不包含持仓量，按币种并发获取（限制并发数）
+	fillOpenInterest(rankings)
+
+	oiRankingCache.set(rankings)
+	return append([]OIRanking(nil), rankings...), nil
+}
+
+// fillOpenInterest 并发获取各币种持仓量并填入排行条目，获取失败的条目保持为0
+func fillOpenInterest(rankings []OIRanking) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, rankingFetchConcurrency)
+	for i := range rankings {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			oiData, err := getOpenInterestData(rankings[i].Symbol)
+			if err != nil {
+				log.Printf("⚠️  获取 %s 持仓量失败: %v", rankings[i].Symbol, err)
+				return
+			}
+			rankings[i].OpenInterest = oiData.Latest
+			rankings[i].OpenInterestUSDT = oiData.Latest * rankings[i].lastPrice
+		}(i)
+	}
+	wg.Wait()
+}
+
+// topN 截取前n个元素
+func topN(rankings []OIRanking, n int) []OIRanking {
+	if n <= 0 || n >= len(rankings) {
+		return rankings
+	}
+	return rankings[:n]
+}
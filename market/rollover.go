@@ -0,0 +1,88 @@
+package market
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultRollDaysBeforeExpiry 默认在交割前几天移仓
+const defaultRollDaysBeforeExpiry = 3
+
+// RolloverEvent 交割合约移仓计划
+type RolloverEvent struct {
+	CurrentInstId       string    // 当前（近月）合约
+	NextInstId          string    // 移仓目标（远月）合约
+	ExpiryDate          time.Time // 当前合约交割时间
+	DaysUntilExpiry     int       // 距离交割天数
+	RecommendedRollDate time.Time // 建议移仓时间（交割前3天）
+}
+
+// GetRolloverSchedule 获取指定币种交割合约的移仓计划（按交割时间排序，相邻两个合约为一次移仓）
+func GetRolloverSchedule(baseCcy string) ([]RolloverEvent, error) {
+	exchangeInfo, err := NewAPIClient().GetExchangeInfo()
+	if err != nil {
+		return nil, fmt.Errorf("获取交易规则失败: %w", err)
+	}
+
+	baseCcy = strings.ToUpper(baseCcy)
+	now := time.Now()
+
+	var contracts []SymbolInfo
+	for _, info := range exchangeInfo.Symbols {
+		if info.BaseAsset != baseCcy || info.Status != "TRADING" {
+			continue
+		}
+		if expiry := symbolExpiry(&info); expiry != nil && expiry.After(now) {
+			contracts = append(contracts, info)
+		}
+	}
+
+	if len(contracts) < 2 {
+		return nil, fmt.Errorf("%s 可交易的交割合约不足两个，无法移仓", baseCcy)
+	}
+
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].DeliveryDate < contracts[j].DeliveryDate
+	})
+
+	events := make([]RolloverEvent, 0, len(contracts)-1)
+	for i := 0; i < len(contracts)-1; i++ {
+		expiry := time.UnixMilli(contracts[i].DeliveryDate)
+		events = append(events, RolloverEvent{
+			CurrentInstId:       contracts[i].Symbol,
+			NextInstId:          contracts[i+1].Symbol,
+			ExpiryDate:          expiry,
+			DaysUntilExpiry:     daysUntil(expiry),
+			RecommendedRollDate: expiry.AddDate(0, 0, -defaultRollDaysBeforeExpiry),
+		})
+	}
+
+	return events, nil
+}
+
+// ShouldRollNow 是否已到建议移仓时间
+func ShouldRollNow(event RolloverEvent) bool {
+	return !time.Now().Before(event.RecommendedRollDate)
+}
+
+// GetRolloverBasis 计算移仓成本：远月相对近月的价差百分比 (远月 - 近月) / 近月
+// 多头移仓时为正表示需要以更高价格买入远月
+func GetRolloverBasis(event RolloverEvent) (float64, error) {
+	prices, err := getAllPremiumIndex()
+	if err != nil {
+		return 0, fmt.Errorf("获取标记价格失败: %w", err)
+	}
+
+	current, ok := prices[event.CurrentInstId]
+	if !ok || current.MarkPrice <= 0 {
+		return 0, fmt.Errorf("未找到合约 %s 的标记价格", event.CurrentInstId)
+	}
+	next, ok := prices[event.NextInstId]
+	if !ok || next.MarkPrice <= 0 {
+		return 0, fmt.Errorf("未找到合约 %s 的标记价格", event.NextInstId)
+	}
+
+	return (next.MarkPrice - current.MarkPrice) / current.MarkPrice * 100, nil
+}
@@ -0,0 +1,183 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// SentimentScore 综合市场情绪评分，各分项和综合分均在 -1（极度看空）到 +1（极度看多）之间
+type SentimentScore struct {
+	Composite      float64 // 加权综合分
+	LSRatioScore   float64 // 全市场多空账户比
+	FundingScore   float64 // 资金费率
+	OITrendScore   float64 // 24小时持仓价值变化
+	TopTraderScore float64 // 大户多空持仓比
+}
+
+// SentimentWeights 各分项权重
+type SentimentWeights struct {
+	LSRatio   float64
+	Funding   float64
+	OITrend   float64
+	TopTrader float64
+}
+
+// DefaultSentimentWeights 默认等权重（各25%）
+var DefaultSentimentWeights = SentimentWeights{
+	LSRatio:   0.25,
+	Funding:   0.25,
+	OITrend:   0.25,
+	TopTrader: 0.25,
+}
+
+const (
+	// extremeFundingRate 视为极端情绪的单期资金费率（0.1%）
+	extremeFundingRate = 0.001
+	// extremeOIChange 视为极端情绪的24小时持仓价值变化（10%）
+	extremeOIChange = 0.1
+)
+
+// GetMarketSentiment 使用默认权重计算综合情绪评分
+func GetMarketSentiment(symbol string) (*SentimentScore, error) {
+	return GetMarketSentimentWithWeights(symbol, DefaultSentimentWeights)
+}
+
+// GetMarketSentimentWithWeights 并发获取多空比、资金费率、持仓量历史和大户持仓比，按权重计算综合情绪评分
+func GetMarketSentimentWithWeights(symbol string, weights SentimentWeights) (*SentimentScore, error) {
+	totalWeight := weights.LSRatio + weights.Funding + weights.OITrend + weights.TopTrader
+	if totalWeight <= 0 {
+		return nil, fmt.Errorf("情绪权重之和必须大于0")
+	}
+
+	symbol = Normalize(symbol)
+
+	var wg sync.WaitGroup
+	var lsRatio, topRatio, oiChange float64
+	var fundingInfo *FundingInfo
+	var lsErr, topErr, oiErr, fundingErr error
+
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		lsRatio, lsErr = getLongShortRatio("globalLongShortAccountRatio", symbol)
+	}()
+	go func() {
+		defer wg.Done()
+		topRatio, topErr = getLongShortRatio("topLongShortPositionRatio", symbol)
+	}()
+	go func() {
+		defer wg.Done()
+		oiChange, oiErr = getOpenInterestChange24h(symbol)
+	}()
+	go func() {
+		defer wg.Done()
+		fundingInfo, fundingErr = GetFundingInfo(symbol)
+	}()
+	wg.Wait()
+
+	if lsErr != nil {
+		return nil, fmt.Errorf("获取多空账户比失败: %w", lsErr)
+	}
+	if topErr != nil {
+		return nil, fmt.Errorf("获取大户持仓比失败: %w", topErr)
+	}
+	if oiErr != nil {
+		return nil, fmt.Errorf("获取持仓量历史失败: %w", oiErr)
+	}
+	if fundingErr != nil {
+		return nil, fmt.Errorf("获取资金费率失败: %w", fundingErr)
+	}
+
+	score := &SentimentScore{
+		LSRatioScore:   ratioScore(lsRatio),
+		FundingScore:   clampSigned(fundingInfo.FundingRate / extremeFundingRate),
+		OITrendScore:   clampSigned(oiChange / extremeOIChange),
+		TopTraderScore: ratioScore(topRatio),
+	}
+	score.Composite = (score.LSRatioScore*weights.LSRatio +
+		score.FundingScore*weights.Funding +
+		score.OITrendScore*weights.OITrend +
+		score.TopTraderScore*weights.TopTrader) / totalWeight
+
+	return score, nil
+}
+
+// getLongShortRatio 获取最近1小时的多空比（endpoint 为 futures/data 下的多空比接口）
+func getLongShortRatio(endpoint, symbol string) (float64, error) {
+	url := fmt.Sprintf("https://fapi.binance.com/futures/data/%s?symbol=%s&period=1h&limit=1", endpoint, symbol)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	var result []struct {
+		LongShortRatio string `json:"longShortRatio"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, err
+	}
+	if len(result) == 0 {
+		return 0, fmt.Errorf("%s 没有多空比数据", symbol)
+	}
+
+	return strconv.ParseFloat(result[0].LongShortRatio, 64)
+}
+
+// getOpenInterestChange24h 获取最近24小时（1小时周期24个点）持仓价值的变化比例
+func getOpenInterestChange24h(symbol string) (float64, error) {
+	url := fmt.Sprintf("https://fapi.binance.com/futures/data/openInterestHist?symbol=%s&period=1h&limit=24", symbol)
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	var result []struct {
+		SumOpenInterestValue string `json:"sumOpenInterestValue"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, err
+	}
+	if len(result) < 2 {
+		return 0, fmt.Errorf("%s 持仓量历史数据不足", symbol)
+	}
+
+	first, _ := strconv.ParseFloat(result[0].SumOpenInterestValue, 64)
+	last, _ := strconv.ParseFloat(result[len(result)-1].SumOpenInterestValue, 64)
+	if first <= 0 {
+		return 0, fmt.Errorf("%s 持仓价值无效", symbol)
+	}
+
+	return (last - first) / first, nil
+}
+
+// ratioScore 将多空比映射到 (-1, 1)：比值为1时为0，多头越多越接近+1
+func ratioScore(ratio float64) float64 {
+	if ratio <= 0 {
+		return 0
+	}
+	return math.Tanh(math.Log(ratio))
+}
+
+// clampSigned 将数值限制在 [-1, 1]
+func clampSigned(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
@@ -54,9 +54,15 @@ type SymbolInfo struct {
 	Status            string `json:"status"`
 	BaseAsset         string `json:"baseAsset"`
 	QuoteAsset        string `json:"quoteAsset"`
+	MarginAsset       string `json:"marginAsset"` // 保证金（结算）币种
+	Pair              string `json:"pair"`
 	ContractType      string `json:"contractType"`
+	DeliveryDate      int64  `json:"deliveryDate"` // 交割时间（毫秒），永续合约为远期占位值
+	OnboardDate       int64  `json:"onboardDate"`  // 上线时间（毫秒）
 	PricePrecision    int    `json:"pricePrecision"`
 	QuantityPrecision int    `json:"quantityPrecision"`
+
+	Filters []map[string]interface{} `json:"filters"` // 交易规则（PRICE_FILTER、LOT_SIZE等）
 }
 
 type Kline struct {
@@ -80,14 +86,39 @@ type PriceTicker struct {
 	Price  string `json:"price"`
 }
 
+type BookTicker struct {
+	Symbol   string `json:"symbol"`
+	BidPrice string `json:"bidPrice"`
+	BidQty   string `json:"bidQty"`
+	AskPrice string `json:"askPrice"`
+	AskQty   string `json:"askQty"`
+}
+
 type Ticker24hr struct {
 	Symbol             string `json:"symbol"`
 	PriceChange        string `json:"priceChange"`
 	PriceChangePercent string `json:"priceChangePercent"`
+	LastPrice          string `json:"lastPrice"`
 	Volume             string `json:"volume"`
 	QuoteVolume        string `json:"quoteVolume"`
 }
 
+// OrderBook 订单簿深度，每档为 [价格, 数量]
+type OrderBook struct {
+	LastUpdateID int64      `json:"lastUpdateId"`
+	Bids         [][]string `json:"bids"`
+	Asks         [][]string `json:"asks"`
+}
+
+// AggTrade 归集成交
+type AggTrade struct {
+	AggTradeID   int64  `json:"a"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	Time         int64  `json:"T"`
+	IsBuyerMaker bool   `json:"m"` // 买方是挂单方，即主动卖出
+}
+
 // 特征数据结构
 type SymbolFeatures struct {
 	Symbol           string    `json:"symbol"`
@@ -14,6 +14,7 @@ type WSClient struct {
 	conn        *websocket.Conn
 	mu          sync.RWMutex
 	subscribers map[string]chan []byte
+	registry    *MessageRegistry // 类型化处理器，与subscribers通道并存
 	reconnect   bool
 	done        chan struct{}
 }
@@ -68,14 +69,31 @@ type TickerWSData struct {
 	Count              int    `json:"n"`
 }
 
+// MarkPriceWSData 标记价格推送
+type MarkPriceWSData struct {
+	EventType       string `json:"e"`
+	EventTime       int64  `json:"E"`
+	Symbol          string `json:"s"`
+	MarkPrice       string `json:"p"`
+	IndexPrice      string `json:"i"`
+	FundingRate     string `json:"r"`
+	NextFundingTime int64  `json:"T"`
+}
+
 func NewWSClient() *WSClient {
 	return &WSClient{
 		subscribers: make(map[string]chan []byte),
+		registry:    NewMessageRegistry(),
 		reconnect:   true,
 		done:        make(chan struct{}),
 	}
 }
 
+// Registry 返回类型化消息处理器注册表，处理器在读取协程中同步执行，不应阻塞
+func (w *WSClient) Registry() *MessageRegistry {
+	return w.registry
+}
+
 func (w *WSClient) Connect() error {
 	dialer := websocket.Dialer{
 		HandshakeTimeout: 10 * time.Second,
@@ -170,6 +188,8 @@ func (w *WSClient) handleMessage(message []byte) {
 		return
 	}
 
+	Dispatch(w.registry, wsMsg)
+
 	w.mu.RLock()
 	ch, exists := w.subscribers[wsMsg.Stream]
 	w.mu.RUnlock()
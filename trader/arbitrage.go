@@ -0,0 +1,73 @@
+package trader
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// estimatedTakerFeePct 估算的单边吃单手续费率（百分比）
+const estimatedTakerFeePct = 0.05
+
+// ArbitrageOpportunity 跨交易所套利机会
+type ArbitrageOpportunity struct {
+	BuyExchange   string  // 价格最低的交易所（买入）
+	SellExchange  string  // 价格最高的交易所（卖出）
+	BuyPrice      float64 // 买入价格
+	SellPrice     float64 // 卖出价格
+	SpreadPct     float64 // 价差百分比（未扣除手续费）
+	IsOpportunity bool    // 扣除双边手续费后价差是否超过阈值
+}
+
+// GetArbitrageOpportunity 并发获取多个交易所同一币种的价格，寻找跨交易所价差
+// traders: 交易所名称 -> Trader；minSpreadPct: 扣除双边手续费后的最小价差百分比
+// 单个交易所获取价格失败时跳过，至少需要两个交易所返回价格
+func GetArbitrageOpportunity(symbol string, traders map[string]Trader, minSpreadPct float64) (*ArbitrageOpportunity, error) {
+	if len(traders) < 2 {
+		return nil, fmt.Errorf("至少需要两个交易所才能比较价格")
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	prices := make(map[string]float64)
+
+	for name, t := range traders {
+		wg.Add(1)
+		go func(name string, t Trader) {
+			defer wg.Done()
+
+			price, err := t.GetMarketPrice(symbol)
+			if err != nil || price <= 0 {
+				log.Printf("⚠️  获取 %s 在 %s 的价格失败: %v", symbol, name, err)
+				return
+			}
+
+			mu.Lock()
+			prices[name] = price
+			mu.Unlock()
+		}(name, t)
+	}
+	wg.Wait()
+
+	if len(prices) < 2 {
+		return nil, fmt.Errorf("%s 仅有 %d 个交易所返回价格，无法比较", symbol, len(prices))
+	}
+
+	opp := &ArbitrageOpportunity{}
+	for name, price := range prices {
+		if opp.BuyExchange == "" || price < opp.BuyPrice {
+			opp.BuyExchange = name
+			opp.BuyPrice = price
+		}
+		if opp.SellExchange == "" || price > opp.SellPrice {
+			opp.SellExchange = name
+			opp.SellPrice = price
+		}
+	}
+
+	opp.SpreadPct = (opp.SellPrice - opp.BuyPrice) / opp.BuyPrice * 100
+	netSpreadPct := opp.SpreadPct - 2*estimatedTakerFeePct
+	opp.IsOpportunity = opp.BuyExchange != opp.SellExchange && netSpreadPct > minSpreadPct
+
+	return opp, nil
+}
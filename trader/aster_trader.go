@@ -13,6 +13,7 @@ import (
 	"math/big"
 	"net/http"
 	"net/url"
+	"nofx/market"
 	"sort"
 	"strconv"
 	"strings"
@@ -46,6 +47,17 @@ type SymbolPrecision struct {
 	StepSize          float64 // 数量步进值
 }
 
+func init() {
+	Register("aster", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用Aster交易", cfg.Name)
+		t, err := NewAsterTrader(cfg.AsterUser, cfg.AsterSigner, cfg.AsterPrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("初始化Aster交易器失败: %w", err)
+		}
+		return t, nil
+	})
+}
+
 // NewAsterTrader 创建Aster交易器
 // user: 主钱包地址 (登录地址)
 // signer: API钱包地址 (从 https://www.asterdex.com/en/api-wallet 获取)
@@ -862,8 +874,12 @@ func (t *AsterTrader) SetLeverage(symbol string, leverage int) error {
 	return err
 }
 
-// GetMarketPrice 获取市场价格
+// GetMarketPrice 获取市场价格（开启 market.SetUseMidPrice 时为买一卖一中间价）
 func (t *AsterTrader) GetMarketPrice(symbol string) (float64, error) {
+	if market.UseMidPrice() {
+		return t.getMidPrice(symbol)
+	}
+
 	// 使用ticker接口获取当前价格
 	resp, err := t.client.Get(fmt.Sprintf("%s/fapi/v3/ticker/price?symbol=%s", t.baseURL, symbol))
 	if err != nil {
@@ -889,6 +905,35 @@ func (t *AsterTrader) GetMarketPrice(symbol string) (float64, error) {
 	return strconv.ParseFloat(priceStr, 64)
 }
 
+// getMidPrice 获取买一卖一中间价
+func (t *AsterTrader) getMidPrice(symbol string) (float64, error) {
+	resp, err := t.client.Get(fmt.Sprintf("%s/fapi/v3/ticker/bookTicker?symbol=%s", t.baseURL, symbol))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var ticker struct {
+		BidPrice string `json:"bidPrice"`
+		AskPrice string `json:"askPrice"`
+	}
+	if err := json.Unmarshal(body, &ticker); err != nil {
+		return 0, err
+	}
+
+	bid, _ := strconv.ParseFloat(ticker.BidPrice, 64)
+	ask, _ := strconv.ParseFloat(ticker.AskPrice, 64)
+	if bid <= 0 || ask <= 0 {
+		return 0, fmt.Errorf("%s 买一卖一价无效: bid=%s ask=%s", symbol, ticker.BidPrice, ticker.AskPrice)
+	}
+	return (bid + ask) / 2, nil
+}
+
 // SetStopLoss 设置止损
 func (t *AsterTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
 	side := "SELL"
@@ -21,7 +21,7 @@ type AutoTraderConfig struct {
 	AIModel string // AI模型: "qwen" 或 "deepseek"
 
 	// 交易平台选择
-	Exchange string // "binance", "hyperliquid" 或 "aster"
+	Exchange string // "binance", "hyperliquid", "aster", "bybit", "bitget", "kucoin", "kraken", "mexc", "deribit", "coinbase", "htx", "bitmex", "phemex" 或 "bingx" 或 "paper"
 
 	// 币安API配置
 	BinanceAPIKey    string
@@ -37,6 +37,57 @@ type AutoTraderConfig struct {
 	AsterSigner     string // Aster API钱包地址
 	AsterPrivateKey string // Aster API钱包私钥
 
+	// Bybit配置
+	BybitAPIKey    string
+	BybitSecretKey string
+	BybitTestnet   bool
+
+	// Bitget配置
+	BitgetAPIKey     string
+	BitgetSecretKey  string
+	BitgetPassphrase string
+
+	// KuCoin配置
+	KuCoinAPIKey     string
+	KuCoinSecretKey  string
+	KuCoinPassphrase string
+
+	// Kraken Futures配置
+	KrakenAPIKey    string
+	KrakenSecretKey string
+	KrakenTestnet   bool
+
+	// MEXC配置
+	MEXCAPIKey    string
+	MEXCSecretKey string
+
+	// Deribit配置（API Key使用Client ID/Client Secret）
+	DeribitClientID     string
+	DeribitClientSecret string
+	DeribitTestnet      bool
+
+	// Coinbase配置（CDP API Key名称和EC私钥，仅现货）
+	CoinbaseAPIKey    string
+	CoinbaseSecretKey string
+
+	// HTX配置
+	HTXAPIKey    string
+	HTXSecretKey string
+
+	// BitMEX配置
+	BitMEXAPIKey    string
+	BitMEXSecretKey string
+	BitMEXTestnet   bool
+
+	// Phemex配置
+	PhemexAPIKey    string
+	PhemexSecretKey string
+	PhemexTestnet   bool
+
+	// BingX配置
+	BingXAPIKey    string
+	BingXSecretKey string
+
 	CoinPoolAPIURL string
 
 	// AI配置
@@ -163,24 +214,9 @@ func NewAutoTrader(config AutoTraderConfig) (*AutoTrader, error) {
 	}
 	log.Printf("📊 [%s] 仓位模式: %s", config.Name, marginModeStr)
 
-	switch config.Exchange {
-	case "binance":
-		log.Printf("🏦 [%s] 使用币安合约交易", config.Name)
-		trader = NewFuturesTrader(config.BinanceAPIKey, config.BinanceSecretKey)
-	case "hyperliquid":
-		log.Printf("🏦 [%s] 使用Hyperliquid交易", config.Name)
-		trader, err = NewHyperliquidTrader(config.HyperliquidPrivateKey, config.HyperliquidWalletAddr, config.HyperliquidTestnet)
-		if err != nil {
-			return nil, fmt.Errorf("初始化Hyperliquid交易器失败: %w", err)
-		}
-	case "aster":
-		log.Printf("🏦 [%s] 使用Aster交易", config.Name)
-		trader, err = NewAsterTrader(config.AsterUser, config.AsterSigner, config.AsterPrivateKey)
-		if err != nil {
-			return nil, fmt.Errorf("初始化Aster交易器失败: %w", err)
-		}
-	default:
-		return nil, fmt.Errorf("不支持的交易平台: %s", config.Exchange)
+	trader, err = New(config.Exchange, config)
+	if err != nil {
+		return nil, err
 	}
 
 	// 验证初始金额配置
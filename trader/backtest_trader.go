@@ -0,0 +1,197 @@
+package trader
+
+import (
+	"fmt"
+	"math"
+	"nofx/market"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BacktestResult 回测结果汇总
+type BacktestResult struct {
+	InitialBalance float64 // 初始资金
+	FinalEquity    float64 // 结束时账户权益（含未实现盈亏）
+	TotalReturnPct float64 // 总收益率（%）
+	MaxDrawdownPct float64 // 最大回撤（%，按每根K线收盘权益计算）
+	Bars           int     // 回放的K线数
+	Orders         int64   // 主动下单次数（不含止损止盈和强平触发）
+}
+
+// BacktestTrader 基于历史K线的回测交易器
+// 复用PaperTrader的模拟撮合：市价单按当前K线收盘价成交，止损止盈和强平按下一根K线的
+// 最高/最低价触发（同一根K线同时触及时先止损，跳空时按开盘价成交），并计算手续费。
+// 不访问网络、订单号按序递增，同一组K线和策略的回放结果完全可复现
+type BacktestTrader struct {
+	*PaperTrader
+
+	klines   map[string][]market.Kline
+	timeline []int64        // 所有币种K线开盘时间的并集（升序）
+	cursor   int            // 当前时间在timeline中的下标，-1表示尚未开始
+	barIdx   map[string]int // 各币种当前K线下标，-1表示该币种尚无数据
+
+	initialBalance float64
+	peakEquity     float64
+	maxDrawdownPct float64
+
+	clockMu sync.RWMutex
+}
+
+// NewBacktestTrader 创建回测交易器，klines 为 币种 -> 历史K线（会按开盘时间排序）
+func NewBacktestTrader(initialBalance float64, klines map[string][]market.Kline) (*BacktestTrader, error) {
+	if initialBalance <= 0 {
+		return nil, fmt.Errorf("初始金额必须大于0")
+	}
+	if len(klines) == 0 {
+		return nil, fmt.Errorf("回测至少需要一个币种的K线数据")
+	}
+
+	bt := &BacktestTrader{
+		klines:         make(map[string][]market.Kline, len(klines)),
+		cursor:         -1,
+		barIdx:         make(map[string]int, len(klines)),
+		initialBalance: initialBalance,
+		peakEquity:     initialBalance,
+	}
+
+	seen := map[int64]bool{}
+	for symbol, series := range klines {
+		if len(series) == 0 {
+			return nil, fmt.Errorf("%s 没有K线数据", symbol)
+		}
+		sorted := append([]market.Kline(nil), series...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].OpenTime < sorted[j].OpenTime })
+
+		bt.klines[symbol] = sorted
+		bt.barIdx[symbol] = -1
+		for _, k := range sorted {
+			if !seen[k.OpenTime] {
+				seen[k.OpenTime] = true
+				bt.timeline = append(bt.timeline, k.OpenTime)
+			}
+		}
+	}
+	sort.Slice(bt.timeline, func(i, j int) bool { return bt.timeline[i] < bt.timeline[j] })
+
+	bt.PaperTrader = NewPaperTraderWithPriceSource(initialBalance, bt.currentClose)
+	return bt, nil
+}
+
+// currentClose 当前K线收盘价，作为PaperTrader的价格来源
+func (bt *BacktestTrader) currentClose(symbol string) (float64, error) {
+	bt.clockMu.RLock()
+	defer bt.clockMu.RUnlock()
+
+	idx, ok := bt.barIdx[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%s 没有回测K线数据", symbol)
+	}
+	if idx < 0 {
+		return 0, fmt.Errorf("%s 在当前回测时间还没有K线", symbol)
+	}
+	return bt.klines[symbol][idx].Close, nil
+}
+
+// Step 前进到下一根K线并处理止损止盈和强平，没有更多K线时返回false
+func (bt *BacktestTrader) Step() bool {
+	bt.clockMu.Lock()
+	if bt.cursor+1 >= len(bt.timeline) {
+		bt.clockMu.Unlock()
+		return false
+	}
+	bt.cursor++
+	now := bt.timeline[bt.cursor]
+
+	// 本根K线有数据的币种（排序保证处理顺序可复现）
+	bars := map[string]market.Kline{}
+	for symbol, series := range bt.klines {
+		next := bt.barIdx[symbol] + 1
+		if next < len(series) && series[next].OpenTime == now {
+			bt.barIdx[symbol] = next
+			bars[symbol] = series[next]
+		}
+	}
+	closes := make(map[string]float64, len(bt.barIdx))
+	for symbol, idx := range bt.barIdx {
+		if idx >= 0 {
+			closes[symbol] = bt.klines[symbol][idx].Close
+		}
+	}
+	bt.clockMu.Unlock()
+
+	symbols := make([]string, 0, len(bars))
+	for symbol := range bars {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	bt.PaperTrader.mu.Lock()
+	for _, symbol := range symbols {
+		bar := bars[symbol]
+		bt.checkTriggers(symbol, bar.Open, bar.High, bar.Low)
+	}
+	equity, _, _ := bt.equityLocked(closes)
+	bt.PaperTrader.mu.Unlock()
+
+	// 按收盘权益统计最大回撤
+	if equity > bt.peakEquity {
+		bt.peakEquity = equity
+	}
+	if bt.peakEquity > 0 {
+		bt.maxDrawdownPct = math.Max(bt.maxDrawdownPct, (bt.peakEquity-equity)/bt.peakEquity*100)
+	}
+
+	return true
+}
+
+// Run 逐根K线回放，每根K线处理完触发条件后调用onBar执行策略，onBar返回错误时中止回测
+func (bt *BacktestTrader) Run(onBar func(now time.Time) error) (*BacktestResult, error) {
+	for bt.Step() {
+		if onBar == nil {
+			continue
+		}
+		if err := onBar(bt.CurrentTime()); err != nil {
+			return bt.Result(), fmt.Errorf("回测在 %s 中止: %w", bt.CurrentTime().Format("2006-01-02 15:04"), err)
+		}
+	}
+	return bt.Result(), nil
+}
+
+// CurrentTime 当前回测时间（当前K线的开盘时间），尚未开始时返回零值
+func (bt *BacktestTrader) CurrentTime() time.Time {
+	bt.clockMu.RLock()
+	defer bt.clockMu.RUnlock()
+
+	if bt.cursor < 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(bt.timeline[bt.cursor])
+}
+
+// Result 返回当前的回测结果汇总
+func (bt *BacktestTrader) Result() *BacktestResult {
+	bt.clockMu.RLock()
+	closes := make(map[string]float64, len(bt.barIdx))
+	for symbol, idx := range bt.barIdx {
+		if idx >= 0 {
+			closes[symbol] = bt.klines[symbol][idx].Close
+		}
+	}
+	bars := bt.cursor + 1
+	bt.clockMu.RUnlock()
+
+	bt.PaperTrader.mu.Lock()
+	equity, _, _ := bt.equityLocked(closes)
+	orders := bt.orderSeq
+	bt.PaperTrader.mu.Unlock()
+
+	return &BacktestResult{
+		InitialBalance: bt.initialBalance,
+		FinalEquity:    equity,
+		TotalReturnPct: (equity - bt.initialBalance) / bt.initialBalance * 100,
+		MaxDrawdownPct: bt.maxDrawdownPct,
+		Bars:           bars,
+		Orders:         orders,
+	}
+}
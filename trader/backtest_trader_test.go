@@ -0,0 +1,219 @@
+package trader
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"nofx/market"
+)
+
+// testBars 按小时生成K线，每根K线为 {open, high, low, close}
+func testBars(ohlc ...[4]float64) []market.Kline {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	klines := make([]market.Kline, len(ohlc))
+	for i, bar := range ohlc {
+		klines[i] = market.Kline{
+			OpenTime: start + int64(i)*time.Hour.Milliseconds(),
+			Open:     bar[0],
+			High:     bar[1],
+			Low:      bar[2],
+			Close:    bar[3],
+		}
+	}
+	return klines
+}
+
+// runBacktest 在第一根K线以收盘价开1个多仓并设置止损止盈（价格为0表示不设置），
+// 之后不再下单，返回回放结束后的结果和钱包余额
+func runBacktest(t *testing.T, klines []market.Kline, stopLoss, stopLossQty, takeProfit float64) (*BacktestResult, float64) {
+	t.Helper()
+	bt, err := NewBacktestTrader(10000, map[string][]market.Kline{"BTCUSDT": klines})
+	if err != nil {
+		t.Fatalf("NewBacktestTrader 返回错误: %v", err)
+	}
+
+	bar := 0
+	result, err := bt.Run(func(now time.Time) error {
+		bar++
+		if bar != 1 {
+			return nil
+		}
+		if _, err := bt.OpenLong("BTCUSDT", 1, 10); err != nil {
+			return err
+		}
+		if stopLoss > 0 {
+			if err := bt.SetStopLoss("BTCUSDT", "LONG", stopLossQty, stopLoss); err != nil {
+				return err
+			}
+		}
+		if takeProfit > 0 {
+			if err := bt.SetTakeProfit("BTCUSDT", "LONG", 1, takeProfit); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run 返回错误: %v", err)
+	}
+
+	balance, err := bt.GetBalance()
+	if err != nil {
+		t.Fatalf("GetBalance 返回错误: %v", err)
+	}
+	return result, balance["totalWalletBalance"].(float64)
+}
+
+func TestBacktestTraderDeterministic(t *testing.T) {
+	// 正弦走势的固定K线，中途触发止损
+	ohlc := make([][4]float64, 48)
+	prev := 100.0
+	for i := range ohlc {
+		c := 100 + 8*math.Sin(float64(i)/4)
+		ohlc[i] = [4]float64{prev, math.Max(prev, c) + 1, math.Min(prev, c) - 1, c}
+		prev = c
+	}
+	klines := testBars(ohlc...)
+
+	strategy := func(bt *BacktestTrader) func(now time.Time) error {
+		bar := 0
+		return func(now time.Time) error {
+			bar++
+			switch bar % 12 {
+			case 1:
+				if _, err := bt.OpenLong("BTCUSDT", 1, 5); err != nil {
+					return err
+				}
+				return bt.SetStopLoss("BTCUSDT", "LONG", 0.5, 97)
+			case 7:
+				positions, err := bt.GetPositions()
+				if err != nil || len(positions) == 0 {
+					return err
+				}
+				_, err = bt.CloseLong("BTCUSDT", 0)
+				return err
+			}
+			return nil
+		}
+	}
+
+	var results []BacktestResult
+	for i := 0; i < 2; i++ {
+		bt, err := NewBacktestTrader(10000, map[string][]market.Kline{"BTCUSDT": klines})
+		if err != nil {
+			t.Fatalf("NewBacktestTrader 返回错误: %v", err)
+		}
+		result, err := bt.Run(strategy(bt))
+		if err != nil {
+			t.Fatalf("Run 返回错误: %v", err)
+		}
+		results = append(results, *result)
+	}
+
+	if results[0] != results[1] {
+		t.Errorf("两次回放结果不一致:\n%+v\n%+v", results[0], results[1])
+	}
+	if results[0].Bars != len(klines) {
+		t.Errorf("Bars = %d, want %d", results[0].Bars, len(klines))
+	}
+	if results[0].FinalEquity == results[0].InitialBalance {
+		t.Error("回放应产生盈亏")
+	}
+}
+
+func TestBacktestTraderTriggers(t *testing.T) {
+	const fee = paperTakerFeeRate
+	tests := []struct {
+		name        string
+		bars        [][4]float64
+		stopLoss    float64
+		stopLossQty float64
+		takeProfit  float64
+		wantWallet  float64
+		wantOrders  int64
+	}{
+		{
+			name:       "跳空越过止损按开盘价成交",
+			bars:       [][4]float64{{100, 100, 100, 100}, {90, 92, 88, 91}},
+			stopLoss:   95,
+			wantWallet: 10000 - 100*fee - 10 - 90*fee,
+			wantOrders: 1,
+		},
+		{
+			name:       "同一根K线触及止损和止盈时先止损",
+			bars:       [][4]float64{{100, 100, 100, 100}, {100, 106, 94, 103}},
+			stopLoss:   95,
+			takeProfit: 105,
+			wantWallet: 10000 - 100*fee - 5 - 95*fee,
+			wantOrders: 1,
+		},
+		{
+			name:       "止盈按触发价成交",
+			bars:       [][4]float64{{100, 100, 100, 100}, {101, 106, 100, 104}},
+			takeProfit: 105,
+			wantWallet: 10000 - 100*fee + 5 - 105*fee,
+			wantOrders: 1,
+		},
+		{
+			name:        "半仓止损只触发一次",
+			bars:        [][4]float64{{100, 100, 100, 100}, {100, 100, 94, 96}, {96, 96, 93, 94}},
+			stopLoss:    95,
+			stopLossQty: 0.5,
+			wantWallet:  10000 - 100*fee + (95-100)*0.5 - 95*0.5*fee,
+			wantOrders:  1,
+		},
+		{
+			name:       "未触发只扣开仓手续费",
+			bars:       [][4]float64{{100, 100, 100, 100}, {100, 102, 98, 101}},
+			stopLoss:   95,
+			takeProfit: 105,
+			wantWallet: 10000 - 100*fee,
+			wantOrders: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, wallet := runBacktest(t, testBars(tt.bars...), tt.stopLoss, tt.stopLossQty, tt.takeProfit)
+			if !almostEqual(wallet, tt.wantWallet) {
+				t.Errorf("钱包余额 = %v, want %v", wallet, tt.wantWallet)
+			}
+			if result.Orders != tt.wantOrders {
+				t.Errorf("Orders = %d, want %d", result.Orders, tt.wantOrders)
+			}
+		})
+	}
+}
+
+func TestBacktestTraderCloseFees(t *testing.T) {
+	bt, err := NewBacktestTrader(10000, map[string][]market.Kline{
+		"BTCUSDT": testBars([4]float64{100, 100, 100, 100}, [4]float64{100, 110, 100, 110}),
+	})
+	if err != nil {
+		t.Fatalf("NewBacktestTrader 返回错误: %v", err)
+	}
+
+	bar := 0
+	result, err := bt.Run(func(now time.Time) error {
+		bar++
+		if bar == 1 {
+			_, err := bt.OpenLong("BTCUSDT", 1, 10)
+			return err
+		}
+		_, err := bt.CloseLong("BTCUSDT", 0)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Run 返回错误: %v", err)
+	}
+
+	// 开仓手续费 100×0.0005，平仓盈利10，平仓手续费 110×0.0005
+	want := 10000 - 0.05 + 10 - 0.055
+	if !almostEqual(result.FinalEquity, want) {
+		t.Errorf("FinalEquity = %v, want %v", result.FinalEquity, want)
+	}
+	if result.Orders != 2 {
+		t.Errorf("Orders = %d, want 2", result.Orders)
+	}
+}
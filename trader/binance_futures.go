@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"nofx/market"
 	"strconv"
 	"sync"
 	"time"
@@ -29,6 +30,13 @@ type FuturesTrader struct {
 	cacheDuration time.Duration
 }
 
+func init() {
+	Register("binance", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用币安合约交易", cfg.Name)
+		return NewFuturesTrader(cfg.BinanceAPIKey, cfg.BinanceSecretKey), nil
+	})
+}
+
 // NewFuturesTrader 创建合约交易器
 func NewFuturesTrader(apiKey, secretKey string) *FuturesTrader {
 	client := futures.NewClient(apiKey, secretKey)
@@ -427,6 +435,23 @@ func (t *FuturesTrader) CancelAllOrders(symbol string) error {
 
 // GetMarketPrice 获取市场价格
 func (t *FuturesTrader) GetMarketPrice(symbol string) (float64, error) {
+	// 开启中间价模式时使用 (ask+bid)/2
+	if market.UseMidPrice() {
+		tickers, err := t.client.NewListBookTickersService().Symbol(symbol).Do(context.Background())
+		if err != nil {
+			return 0, fmt.Errorf("获取买一卖一价失败: %w", err)
+		}
+		if len(tickers) == 0 {
+			return 0, fmt.Errorf("未找到价格")
+		}
+		bid, _ := strconv.ParseFloat(tickers[0].BidPrice, 64)
+		ask, _ := strconv.ParseFloat(tickers[0].AskPrice, 64)
+		if bid <= 0 || ask <= 0 {
+			return 0, fmt.Errorf("%s 买一卖一价无效: bid=%s ask=%s", symbol, tickers[0].BidPrice, tickers[0].AskPrice)
+		}
+		return (bid + ask) / 2, nil
+	}
+
 	prices, err := t.client.NewListPricesService().Symbol(symbol).Do(context.Background())
 	if err != nil {
 		return 0, fmt.Errorf("获取价格失败: %w", err)
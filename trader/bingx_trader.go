@@ -0,0 +1,542 @@
+package trader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"nofx/market"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const bingxBaseURL = "https://open-api.bingx.com"
+
+// BingXTrader BingX USDT本位永续合约交易器（双向持仓，接口风格与Binance相近）
+type BingXTrader struct {
+	apiKey    string
+	secretKey string
+	rest      *restExchange
+
+	// 缓存交易对精度信息
+	precisions map[string]SymbolPrecision
+	mu         sync.RWMutex
+}
+
+// bingxResponse BingX 通用响应结构
+type bingxResponse struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+// BingX 接口表
+var bingxEndpoints = map[string]string{
+	"contracts":    "/openApi/swap/v2/quote/contracts",
+	"price":        "/openApi/swap/v2/quote/price",
+	"bookTicker":   "/openApi/swap/v2/quote/bookTicker",
+	"balance":      "/openApi/swap/v2/user/balance",
+	"positions":    "/openApi/swap/v2/user/positions",
+	"order":        "/openApi/swap/v2/trade/order",
+	"leverage":     "/openApi/swap/v2/trade/leverage",
+	"marginType":   "/openApi/swap/v2/trade/marginType",
+	"cancelOrders": "/openApi/swap/v2/trade/allOpenOrders",
+}
+
+func init() {
+	Register("bingx", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用BingX合约交易", cfg.Name)
+		return NewBingXTrader(cfg.BingXAPIKey, cfg.BingXSecretKey), nil
+	})
+}
+
+// NewBingXTrader 创建BingX合约交易器
+func NewBingXTrader(apiKey, secretKey string) *BingXTrader {
+	t := &BingXTrader{
+		apiKey:     apiKey,
+		secretKey:  secretKey,
+		precisions: make(map[string]SymbolPrecision),
+	}
+	t.rest = newRESTExchange(restExchangeConfig{
+		Name:      "BingX",
+		BaseURL:   bingxBaseURL,
+		Endpoints: bingxEndpoints,
+		Signer:    t.sign,
+		Decoder:   decodeBingX,
+		Symbols: restSymbolMapper{
+			toExchange:   toBingXSymbol,
+			fromExchange: fromBingXSymbol,
+		},
+	})
+	return t
+}
+
+// toBingXSymbol 将 BTCUSDT 转换为 BingX 合约代码 BTC-USDT
+func toBingXSymbol(symbol string) string {
+	return strings.TrimSuffix(strings.ToUpper(symbol), "USDT") + "-USDT"
+}
+
+// fromBingXSymbol 将 BingX 合约代码 BTC-USDT 转换回 BTCUSDT
+func fromBingXSymbol(symbol string) string {
+	return strings.ReplaceAll(symbol, "-", "")
+}
+
+// sign 签名钩子：所有参数放在querystring
+// 签名: hex(HMAC_SHA256(secret, 参数串))，参数串包含timestamp，签名追加为 signature 参数
+func (t *BingXTrader) sign(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+	query.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli(), 10))
+
+	encoded := query.Encode()
+	mac := hmac.New(sha256.New, []byte(t.secretKey))
+	mac.Write([]byte(encoded))
+
+	header.Set("X-BX-APIKEY", t.apiKey)
+	return encoded + "&signature=" + hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// decodeBingX 解析BingX响应包装
+func decodeBingX(statusCode int, body []byte) (json.RawMessage, error) {
+	var result bingxResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+	if result.Code != 0 {
+		return nil, fmt.Errorf("BingX API错误 %d: %s", result.Code, result.Msg)
+	}
+	return result.Data, nil
+}
+
+// getPrecision 获取交易对精度，首次调用时缓存全部合约
+func (t *BingXTrader) getPrecision(symbol string) (SymbolPrecision, error) {
+	t.mu.RLock()
+	if prec, ok := t.precisions[symbol]; ok {
+		t.mu.RUnlock()
+		return prec, nil
+	}
+	t.mu.RUnlock()
+
+	data, err := t.rest.publicCall("GET", "contracts", nil)
+	if err != nil {
+		return SymbolPrecision{}, fmt.Errorf("获取合约信息失败: %w", err)
+	}
+
+	var contracts []struct {
+		Symbol            string `json:"symbol"`
+		PricePrecision    int    `json:"pricePrecision"`
+		QuantityPrecision int    `json:"quantityPrecision"`
+	}
+	if err := json.Unmarshal(data, &contracts); err != nil {
+		return SymbolPrecision{}, err
+	}
+
+	t.mu.Lock()
+	for _, c := range contracts {
+		t.precisions[t.rest.localSymbol(c.Symbol)] = SymbolPrecision{
+			PricePrecision:    c.PricePrecision,
+			QuantityPrecision: c.QuantityPrecision,
+			TickSize:          math.Pow10(-c.PricePrecision),
+			StepSize:          math.Pow10(-c.QuantityPrecision),
+		}
+	}
+	prec, ok := t.precisions[symbol]
+	t.mu.Unlock()
+
+	if !ok {
+		return SymbolPrecision{}, fmt.Errorf("未找到合约 %s 的精度信息", symbol)
+	}
+	return prec, nil
+}
+
+// formatPrice 格式化价格到交易对精度
+func (t *BingXTrader) formatPrice(symbol string, price float64) (string, error) {
+	prec, err := t.getPrecision(symbol)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(roundToTickSize(price, prec.TickSize), 'f', prec.PricePrecision, 64), nil
+}
+
+// GetBalance 获取USDT合约账户余额
+func (t *BingXTrader) GetBalance() (map[string]interface{}, error) {
+	data, err := t.rest.signedCall("GET", "balance", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	var result struct {
+		Balance struct {
+			Balance          string `json:"balance"`
+			UnrealizedProfit string `json:"unrealizedProfit"`
+			AvailableMargin  string `json:"availableMargin"`
+		} `json:"balance"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	balance, _ := strconv.ParseFloat(result.Balance.Balance, 64)
+	unrealizedPnL, _ := strconv.ParseFloat(result.Balance.UnrealizedProfit, 64)
+	available, _ := strconv.ParseFloat(result.Balance.AvailableMargin, 64)
+
+	// 返回与Binance相同的字段名
+	return map[string]interface{}{
+		"totalWalletBalance":    balance,
+		"availableBalance":      available,
+		"totalUnrealizedProfit": unrealizedPnL,
+	}, nil
+}
+
+// GetPositions 获取持仓信息
+func (t *BingXTrader) GetPositions() ([]map[string]interface{}, error) {
+	data, err := t.rest.signedCall("GET", "positions", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	var positions []struct {
+		Symbol           string  `json:"symbol"`
+		PositionSide     string  `json:"positionSide"` // LONG/SHORT
+		PositionAmt      string  `json:"positionAmt"`
+		AvgPrice         string  `json:"avgPrice"`
+		MarkPrice        string  `json:"markPrice"`
+		UnrealizedProfit string  `json:"unrealizedProfit"`
+		Leverage         float64 `json:"leverage"`
+		LiquidationPrice float64 `json:"liquidationPrice"`
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, pos := range positions {
+		posAmt, _ := strconv.ParseFloat(pos.PositionAmt, 64)
+		if posAmt == 0 {
+			continue // 跳过空仓位
+		}
+
+		entryPrice, _ := strconv.ParseFloat(pos.AvgPrice, 64)
+		markPrice, _ := strconv.ParseFloat(pos.MarkPrice, 64)
+		unRealizedProfit, _ := strconv.ParseFloat(pos.UnrealizedProfit, 64)
+
+		side := "long"
+		if pos.PositionSide == "SHORT" {
+			side = "short"
+		}
+
+		// 返回与Binance相同的字段名
+		result = append(result, map[string]interface{}{
+			"symbol":           t.rest.localSymbol(pos.Symbol),
+			"side":             side,
+			"positionAmt":      absFloat(posAmt),
+			"entryPrice":       entryPrice,
+			"markPrice":        markPrice,
+			"unRealizedProfit": unRealizedProfit,
+			"leverage":         pos.Leverage,
+			"liquidationPrice": pos.LiquidationPrice,
+		})
+	}
+
+	return result, nil
+}
+
+// placeOrder 下单，positionSide为 LONG/SHORT
+func (t *BingXTrader) placeOrder(symbol, side, positionSide, orderType string, quantity float64, extra url.Values) (map[string]interface{}, error) {
+	quantityStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+	if q, _ := strconv.ParseFloat(quantityStr, 64); q <= 0 {
+		return nil, fmt.Errorf("%s 数量 %.8f 低于最小下单量", symbol, quantity)
+	}
+
+	params := url.Values{}
+	params.Set("symbol", t.rest.exchangeSymbol(symbol))
+	params.Set("side", side)
+	params.Set("positionSide", positionSide)
+	params.Set("type", orderType)
+	params.Set("quantity", quantityStr)
+	for k, v := range extra {
+		params[k] = v
+	}
+
+	data, err := t.rest.signedCall("POST", "order", params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Order struct {
+			OrderID json.Number `json:"orderId"`
+		} `json:"order"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	log.Printf("  订单ID: %s", result.Order.OrderID)
+	return map[string]interface{}{
+		"orderId": result.Order.OrderID.String(),
+		"symbol":  symbol,
+		"status":  "NEW",
+	}, nil
+}
+
+// OpenLong 开多单
+func (t *BingXTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "BUY", "LONG", "MARKET", quantity, nil)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开多仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// OpenShort 开空单
+func (t *BingXTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "SELL", "SHORT", "MARKET", quantity, nil)
+	if err != nil {
+		return nil, fmt.Errorf("开空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开空仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// CloseLong 平多单（quantity为0时平掉全部多仓）
+func (t *BingXTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "long")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到多仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "SELL", "LONG", "MARKET", quantity, nil)
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平多仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// CloseShort 平空单（quantity为0时平掉全部空仓）
+func (t *BingXTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "short")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到空仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "BUY", "SHORT", "MARKET", quantity, nil)
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平空仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// positionQuantity 获取指定方向的持仓数量
+func (t *BingXTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return pos["positionAmt"].(float64), nil
+		}
+	}
+
+	sideName := "多仓"
+	if side == "short" {
+		sideName = "空仓"
+	}
+	return 0, fmt.Errorf("没有找到 %s 的%s", symbol, sideName)
+}
+
+// SetMarginMode 设置仓位模式（CROSSED/ISOLATED）
+func (t *BingXTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	marginType := "CROSSED"
+	if !isCrossMargin {
+		marginType = "ISOLATED"
+	}
+
+	_, err := t.rest.signedCall("POST", "marginType", url.Values{
+		"symbol":     {t.rest.exchangeSymbol(symbol)},
+		"marginType": {marginType},
+	}, nil)
+	if err != nil {
+		log.Printf("  ⚠️ 设置仓位模式失败: %v", err)
+		// 不返回错误，让交易继续
+		return nil
+	}
+
+	log.Printf("  ✓ %s 仓位模式已设置为 %s", symbol, marginType)
+	return nil
+}
+
+// SetLeverage 设置杠杆倍数（双向持仓时多空分别设置）
+func (t *BingXTrader) SetLeverage(symbol string, leverage int) error {
+	for _, side := range []string{"LONG", "SHORT"} {
+		_, err := t.rest.signedCall("POST", "leverage", url.Values{
+			"symbol":   {t.rest.exchangeSymbol(symbol)},
+			"side":     {side},
+			"leverage": {strconv.Itoa(leverage)},
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("设置杠杆失败: %w", err)
+		}
+	}
+
+	log.Printf("  ✓ %s 杠杆已切换为 %dx", symbol, leverage)
+	return nil
+}
+
+// GetMarketPrice 获取最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *BingXTrader) GetMarketPrice(symbol string) (float64, error) {
+	query := url.Values{"symbol": {t.rest.exchangeSymbol(symbol)}}
+
+	if market.UseMidPrice() {
+		data, err := t.rest.publicCall("GET", "bookTicker", query)
+		if err != nil {
+			return 0, fmt.Errorf("获取买一卖一价失败: %w", err)
+		}
+
+		var result struct {
+			BookTicker struct {
+				BidPrice float64 `json:"bid_price"`
+				AskPrice float64 `json:"ask_price"`
+			} `json:"book_ticker"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return 0, err
+		}
+		if bid, ask := result.BookTicker.BidPrice, result.BookTicker.AskPrice; bid > 0 && ask > 0 {
+			return (bid + ask) / 2, nil
+		}
+	}
+
+	data, err := t.rest.publicCall("GET", "price", query)
+	if err != nil {
+		return 0, fmt.Errorf("获取价格失败: %w", err)
+	}
+
+	var ticker struct {
+		Price string `json:"price"`
+	}
+	if err := json.Unmarshal(data, &ticker); err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(ticker.Price, 64)
+}
+
+// placeTriggerOrder 下标记价格触发的平仓条件市价单
+// orderType: STOP_MARKET(止损) / TAKE_PROFIT_MARKET(止盈)
+func (t *BingXTrader) placeTriggerOrder(symbol, positionSide, orderType string, quantity, triggerPrice float64) error {
+	side := "SELL"
+	if positionSide == "SHORT" {
+		side = "BUY"
+	}
+
+	priceStr, err := t.formatPrice(symbol, triggerPrice)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.placeOrder(symbol, side, positionSide, orderType, quantity, url.Values{
+		"stopPrice":   {priceStr},
+		"workingType": {"MARK_PRICE"},
+	})
+	return err
+}
+
+// SetStopLoss 设置止损
+func (t *BingXTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, "STOP_MARKET", quantity, stopPrice); err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	log.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈
+func (t *BingXTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, "TAKE_PROFIT_MARKET", quantity, takeProfitPrice); err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	log.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 取消该币种的所有挂单（包括止损止盈单）
+func (t *BingXTrader) CancelAllOrders(symbol string) error {
+	_, err := t.rest.signedCall("DELETE", "cancelOrders", url.Values{
+		"symbol": {t.rest.exchangeSymbol(symbol)},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("取消挂单失败: %w", err)
+	}
+
+	log.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 将数量向下取整到交易对精度（实现Trader接口）
+func (t *BingXTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	prec, err := t.getPrecision(symbol)
+	if err != nil {
+		return "", err
+	}
+
+	quantity = floorToStep(quantity, prec.StepSize)
+	return strconv.FormatFloat(quantity, 'f', prec.QuantityPrecision, 64), nil
+}
@@ -0,0 +1,686 @@
+package trader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	bitgetBaseURL     = "https://api.bitget.com"
+	bitgetProductType = "USDT-FUTURES" // USDT永续合约
+	bitgetMarginCoin  = "USDT"
+	bitgetSuccessCode = "00000"
+)
+
+// BitgetTrader Bitget USDT永续合约交易器（V2 API）
+type BitgetTrader struct {
+	apiKey     string
+	secretKey  string
+	passphrase string
+	rest       *restExchange
+
+	// 下单时使用的保证金模式，由 SetMarginMode 更新
+	marginMode string
+
+	// 账户持仓模式（one_way_mode / hedge_mode），首次下单时查询
+	posMode string
+
+	// 缓存交易对精度信息
+	symbolPrecision map[string]SymbolPrecision
+	mu              sync.RWMutex
+}
+
+// bitgetResponse Bitget V2 通用响应结构
+type bitgetResponse struct {
+	Code string          `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+func init() {
+	Register("bitget", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用Bitget合约交易", cfg.Name)
+		return NewBitgetTrader(cfg.BitgetAPIKey, cfg.BitgetSecretKey, cfg.BitgetPassphrase), nil
+	})
+}
+
+// NewBitgetTrader 创建Bitget交易器
+func NewBitgetTrader(apiKey, secretKey, passphrase string) *BitgetTrader {
+	t := &BitgetTrader{
+		apiKey:          apiKey,
+		secretKey:       secretKey,
+		passphrase:      passphrase,
+		marginMode:      "crossed",
+		symbolPrecision: make(map[string]SymbolPrecision),
+	}
+	t.rest = newRESTExchange(restExchangeConfig{
+		Name:    "Bitget",
+		BaseURL: bitgetBaseURL,
+		Signer:  t.sign,
+		Decoder: decodeBitget,
+	})
+	return t
+}
+
+// sign 签名钩子: base64(HMAC_SHA256(timestamp + method + requestPath + body))
+// requestPath 包含querystring
+func (t *BitgetTrader) sign(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+	rawQuery := query.Encode()
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	mac := hmac.New(sha256.New, []byte(t.secretKey))
+	mac.Write([]byte(timestamp + method + restRequestPath(path, rawQuery) + string(body)))
+
+	header.Set("ACCESS-KEY", t.apiKey)
+	header.Set("ACCESS-SIGN", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	header.Set("ACCESS-TIMESTAMP", timestamp)
+	header.Set("ACCESS-PASSPHRASE", t.passphrase)
+	header.Set("Content-Type", "application/json")
+	header.Set("locale", "en-US")
+	return rawQuery, nil
+}
+
+// decodeBitget 解析V2响应包装，code非00000时返回错误
+func decodeBitget(statusCode int, body []byte) (json.RawMessage, error) {
+	var result bitgetResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+	if result.Code != bitgetSuccessCode {
+		return nil, fmt.Errorf("Bitget API错误 %s: %s", result.Code, result.Msg)
+	}
+	return result.Data, nil
+}
+
+// request 发送签名请求，GET参数放在querystring，POST参数以JSON放在body
+func (t *BitgetTrader) request(method, endpoint string, params map[string]interface{}) (json.RawMessage, error) {
+	method = strings.ToUpper(method)
+	if method != "GET" && method != "POST" {
+		return nil, fmt.Errorf("不支持的HTTP方法: %s", method)
+	}
+	query, payload := restParams(method, params)
+	return t.rest.signedCall(method, endpoint, query, payload)
+}
+
+// publicGet 请求无需签名的行情接口
+func (t *BitgetTrader) publicGet(endpoint string, params url.Values) (json.RawMessage, error) {
+	return t.rest.publicCall("GET", endpoint, params)
+}
+
+// getPrecision 获取交易对精度信息
+func (t *BitgetTrader) getPrecision(symbol string) (SymbolPrecision, error) {
+	t.mu.RLock()
+	if prec, ok := t.symbolPrecision[symbol]; ok {
+		t.mu.RUnlock()
+		return prec, nil
+	}
+	t.mu.RUnlock()
+
+	data, err := t.publicGet("/api/v2/mix/market/contracts", url.Values{
+		"productType": {bitgetProductType},
+		"symbol":      {symbol},
+	})
+	if err != nil {
+		return SymbolPrecision{}, fmt.Errorf("获取合约信息失败: %w", err)
+	}
+
+	var contracts []struct {
+		Symbol         string `json:"symbol"`
+		PricePlace     string `json:"pricePlace"`
+		PriceEndStep   string `json:"priceEndStep"`
+		VolumePlace    string `json:"volumePlace"`
+		SizeMultiplier string `json:"sizeMultiplier"`
+	}
+	if err := json.Unmarshal(data, &contracts); err != nil {
+		return SymbolPrecision{}, err
+	}
+	if len(contracts) == 0 {
+		return SymbolPrecision{}, fmt.Errorf("未找到交易对 %s 的精度信息", symbol)
+	}
+
+	c := contracts[0]
+	pricePlace, _ := strconv.Atoi(c.PricePlace)
+	priceEndStep, _ := strconv.ParseFloat(c.PriceEndStep, 64)
+	volumePlace, _ := strconv.Atoi(c.VolumePlace)
+	sizeMultiplier, _ := strconv.ParseFloat(c.SizeMultiplier, 64)
+
+	// 价格步进 = priceEndStep × 10^-pricePlace
+	prec := SymbolPrecision{
+		PricePrecision:    pricePlace,
+		QuantityPrecision: volumePlace,
+		TickSize:          priceEndStep * math.Pow10(-pricePlace),
+		StepSize:          sizeMultiplier,
+	}
+
+	t.mu.Lock()
+	t.symbolPrecision[symbol] = prec
+	t.mu.Unlock()
+
+	return prec, nil
+}
+
+// formatPrice 格式化价格到tick size并转为字符串
+func (t *BitgetTrader) formatPrice(symbol string, price float64) (string, error) {
+	prec, err := t.getPrecision(symbol)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(roundToTickSize(price, prec.TickSize), 'f', prec.PricePrecision, 64), nil
+}
+
+// getPositionMode 获取账户持仓模式（单向/双向），结果缓存
+func (t *BitgetTrader) getPositionMode(symbol string) (string, error) {
+	t.mu.RLock()
+	posMode := t.posMode
+	t.mu.RUnlock()
+	if posMode != "" {
+		return posMode, nil
+	}
+
+	data, err := t.request("GET", "/api/v2/mix/account/account", map[string]interface{}{
+		"symbol":      symbol,
+		"productType": bitgetProductType,
+		"marginCoin":  bitgetMarginCoin,
+	})
+	if err != nil {
+		return "", fmt.Errorf("获取持仓模式失败: %w", err)
+	}
+
+	var account struct {
+		PosMode string `json:"posMode"`
+	}
+	if err := json.Unmarshal(data, &account); err != nil {
+		return "", err
+	}
+
+	t.mu.Lock()
+	t.posMode = account.PosMode
+	t.mu.Unlock()
+
+	log.Printf("  ✓ Bitget持仓模式: %s", account.PosMode)
+	return account.PosMode, nil
+}
+
+// isHedgeMode 是否为双向持仓模式
+func (t *BitgetTrader) isHedgeMode(symbol string) (bool, error) {
+	posMode, err := t.getPositionMode(symbol)
+	if err != nil {
+		return false, err
+	}
+	return posMode == "hedge_mode", nil
+}
+
+// GetBalance 获取USDT合约账户余额
+func (t *BitgetTrader) GetBalance() (map[string]interface{}, error) {
+	data, err := t.request("GET", "/api/v2/mix/account/accounts", map[string]interface{}{
+		"productType": bitgetProductType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	var accounts []struct {
+		MarginCoin    string `json:"marginCoin"`
+		AccountEquity string `json:"accountEquity"`
+		Available     string `json:"available"`
+		UnrealizedPL  string `json:"unrealizedPL"`
+	}
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		return nil, err
+	}
+
+	totalBalance := 0.0
+	availableBalance := 0.0
+	unrealizedPnL := 0.0
+	for _, acc := range accounts {
+		if !strings.EqualFold(acc.MarginCoin, bitgetMarginCoin) {
+			continue
+		}
+		equity, _ := strconv.ParseFloat(acc.AccountEquity, 64)
+		availableBalance, _ = strconv.ParseFloat(acc.Available, 64)
+		unrealizedPnL, _ = strconv.ParseFloat(acc.UnrealizedPL, 64)
+		// 钱包余额不含未实现盈亏，与Binance口径一致
+		totalBalance = equity - unrealizedPnL
+		break
+	}
+
+	// 返回与Binance相同的字段名，确保AutoTrader能正确解析
+	return map[string]interface{}{
+		"totalWalletBalance":    totalBalance,
+		"availableBalance":      availableBalance,
+		"totalUnrealizedProfit": unrealizedPnL,
+	}, nil
+}
+
+// GetPositions 获取USDT永续持仓
+func (t *BitgetTrader) GetPositions() ([]map[string]interface{}, error) {
+	data, err := t.request("GET", "/api/v2/mix/position/all-position", map[string]interface{}{
+		"productType": bitgetProductType,
+		"marginCoin":  bitgetMarginCoin,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	var positions []struct {
+		Symbol           string `json:"symbol"`
+		HoldSide         string `json:"holdSide"` // long/short
+		Total            string `json:"total"`
+		OpenPriceAvg     string `json:"openPriceAvg"`
+		MarkPrice        string `json:"markPrice"`
+		UnrealizedPL     string `json:"unrealizedPL"`
+		Leverage         string `json:"leverage"`
+		LiquidationPrice string `json:"liquidationPrice"`
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, pos := range positions {
+		total, _ := strconv.ParseFloat(pos.Total, 64)
+		if total == 0 {
+			continue // 跳过空仓位
+		}
+
+		entryPrice, _ := strconv.ParseFloat(pos.OpenPriceAvg, 64)
+		markPrice, _ := strconv.ParseFloat(pos.MarkPrice, 64)
+		unRealizedProfit, _ := strconv.ParseFloat(pos.UnrealizedPL, 64)
+		leverage, _ := strconv.ParseFloat(pos.Leverage, 64)
+		liquidationPrice, _ := strconv.ParseFloat(pos.LiquidationPrice, 64)
+
+		// 返回与Binance相同的字段名
+		result = append(result, map[string]interface{}{
+			"symbol":           pos.Symbol,
+			"side":             pos.HoldSide,
+			"positionAmt":      total,
+			"entryPrice":       entryPrice,
+			"markPrice":        markPrice,
+			"unRealizedProfit": unRealizedProfit,
+			"leverage":         leverage,
+			"liquidationPrice": liquidationPrice,
+		})
+	}
+
+	return result, nil
+}
+
+// placeOrder 下市价单，根据持仓模式设置方向参数
+// positionSide: long/short，closing: 是否为平仓
+func (t *BitgetTrader) placeOrder(symbol, positionSide string, quantity float64, closing bool) (map[string]interface{}, error) {
+	hedge, err := t.isHedgeMode(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	sizeStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	marginMode := t.marginMode
+	t.mu.RUnlock()
+
+	params := map[string]interface{}{
+		"symbol":      symbol,
+		"productType": bitgetProductType,
+		"marginMode":  marginMode,
+		"marginCoin":  bitgetMarginCoin,
+		"size":        sizeStr,
+		"orderType":   "market",
+	}
+
+	if hedge {
+		// 双向持仓：side表示持仓方向，tradeSide区分开平
+		params["side"] = "buy"
+		if positionSide == "short" {
+			params["side"] = "sell"
+		}
+		params["tradeSide"] = "open"
+		if closing {
+			params["tradeSide"] = "close"
+		}
+	} else {
+		// 单向持仓：side表示买卖方向，平仓使用reduceOnly
+		buy := (positionSide == "long") != closing
+		params["side"] = "sell"
+		if buy {
+			params["side"] = "buy"
+		}
+		if closing {
+			params["reduceOnly"] = "YES"
+		}
+	}
+
+	data, err := t.request("POST", "/api/v2/mix/order/place-order", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var order struct {
+		OrderID string `json:"orderId"`
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+
+	log.Printf("  订单ID: %s", order.OrderID)
+	return map[string]interface{}{
+		"orderId":  order.OrderID,
+		"symbol":   symbol,
+		"quantity": sizeStr,
+	}, nil
+}
+
+// OpenLong 开多单
+func (t *BitgetTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "long", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开多仓成功: %s 数量: %v", symbol, result["quantity"])
+	return result, nil
+}
+
+// OpenShort 开空单
+func (t *BitgetTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "short", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开空仓成功: %s 数量: %v", symbol, result["quantity"])
+	return result, nil
+}
+
+// CloseLong 平多单（quantity为0时平掉全部多仓）
+func (t *BitgetTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "long")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到多仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "long", quantity, true)
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平多仓成功: %s 数量: %v", symbol, result["quantity"])
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// CloseShort 平空单（quantity为0时平掉全部空仓）
+func (t *BitgetTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "short")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到空仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "short", quantity, true)
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平空仓成功: %s 数量: %v", symbol, result["quantity"])
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// positionQuantity 获取指定方向的持仓数量
+func (t *BitgetTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return pos["positionAmt"].(float64), nil
+		}
+	}
+
+	sideName := "多仓"
+	if side == "short" {
+		sideName = "空仓"
+	}
+	return 0, fmt.Errorf("没有找到 %s 的%s", symbol, sideName)
+}
+
+// SetMarginMode 设置仓位模式，之后的下单也会使用该模式
+func (t *BitgetTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	marginMode := "crossed"
+	if !isCrossMargin {
+		marginMode = "isolated"
+	}
+
+	t.mu.Lock()
+	t.marginMode = marginMode
+	t.mu.Unlock()
+
+	_, err := t.request("POST", "/api/v2/mix/account/set-margin-mode", map[string]interface{}{
+		"symbol":      symbol,
+		"productType": bitgetProductType,
+		"marginCoin":  bitgetMarginCoin,
+		"marginMode":  marginMode,
+	})
+	if err != nil {
+		log.Printf("  ⚠️ 设置仓位模式失败: %v", err)
+		// 不返回错误，让交易继续
+		return nil
+	}
+
+	log.Printf("  ✓ %s 仓位模式已设置为 %s", symbol, marginMode)
+	return nil
+}
+
+// SetLeverage 设置杠杆倍数
+// 逐仓+双向持仓时多空杠杆需分别设置
+func (t *BitgetTrader) SetLeverage(symbol string, leverage int) error {
+	hedge, err := t.isHedgeMode(symbol)
+	if err != nil {
+		return err
+	}
+
+	t.mu.RLock()
+	isolated := t.marginMode == "isolated"
+	t.mu.RUnlock()
+
+	holdSides := []string{""}
+	if isolated && hedge {
+		holdSides = []string{"long", "short"}
+	}
+
+	for _, holdSide := range holdSides {
+		params := map[string]interface{}{
+			"symbol":      symbol,
+			"productType": bitgetProductType,
+			"marginCoin":  bitgetMarginCoin,
+			"leverage":    strconv.Itoa(leverage),
+		}
+		if holdSide != "" {
+			params["holdSide"] = holdSide
+		}
+
+		if _, err := t.request("POST", "/api/v2/mix/account/set-leverage", params); err != nil {
+			return fmt.Errorf("设置杠杆失败: %w", err)
+		}
+	}
+
+	log.Printf("  ✓ %s 杠杆已切换为 %dx", symbol, leverage)
+	return nil
+}
+
+// GetMarketPrice 获取最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *BitgetTrader) GetMarketPrice(symbol string) (float64, error) {
+	data, err := t.publicGet("/api/v2/mix/market/ticker", url.Values{
+		"symbol":      {symbol},
+		"productType": {bitgetProductType},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("获取价格失败: %w", err)
+	}
+
+	var tickers []struct {
+		LastPr string `json:"lastPr"`
+		BidPr  string `json:"bidPr"`
+		AskPr  string `json:"askPr"`
+	}
+	if err := json.Unmarshal(data, &tickers); err != nil {
+		return 0, err
+	}
+	if len(tickers) == 0 {
+		return 0, fmt.Errorf("未找到 %s 的行情", symbol)
+	}
+
+	last, err := strconv.ParseFloat(tickers[0].LastPr, 64)
+	if err != nil {
+		return 0, err
+	}
+	bid, _ := strconv.ParseFloat(tickers[0].BidPr, 64)
+	ask, _ := strconv.ParseFloat(tickers[0].AskPr, 64)
+	return pickMarketPrice(last, bid, ask), nil
+}
+
+// placeTPSLOrder 下止盈止损计划单，触发后以市价平仓
+// planType: profit_plan(止盈) / loss_plan(止损)
+func (t *BitgetTrader) placeTPSLOrder(symbol, positionSide, planType string, quantity, triggerPrice float64) error {
+	hedge, err := t.isHedgeMode(symbol)
+	if err != nil {
+		return err
+	}
+
+	// holdSide: 双向持仓为long/short，单向持仓为buy/sell
+	holdSide := strings.ToLower(positionSide)
+	if !hedge {
+		holdSide = "buy"
+		if positionSide == "SHORT" {
+			holdSide = "sell"
+		}
+	}
+
+	sizeStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return err
+	}
+	priceStr, err := t.formatPrice(symbol, triggerPrice)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.request("POST", "/api/v2/mix/order/place-tpsl-order", map[string]interface{}{
+		"symbol":       symbol,
+		"productType":  bitgetProductType,
+		"marginCoin":   bitgetMarginCoin,
+		"planType":     planType,
+		"triggerPrice": priceStr,
+		"triggerType":  "mark_price",
+		"holdSide":     holdSide,
+		"size":         sizeStr,
+	})
+	return err
+}
+
+// SetStopLoss 设置止损
+func (t *BitgetTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeTPSLOrder(symbol, positionSide, "loss_plan", quantity, stopPrice); err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	log.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈
+func (t *BitgetTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeTPSLOrder(symbol, positionSide, "profit_plan", quantity, takeProfitPrice); err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	log.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 取消该币种的所有普通挂单和止盈止损计划单
+func (t *BitgetTrader) CancelAllOrders(symbol string) error {
+	params := map[string]interface{}{
+		"symbol":      symbol,
+		"productType": bitgetProductType,
+		"marginCoin":  bitgetMarginCoin,
+	}
+	if _, err := t.request("POST", "/api/v2/mix/order/batch-cancel-orders", params); err != nil {
+		return fmt.Errorf("取消挂单失败: %w", err)
+	}
+
+	params["planType"] = "profit_loss"
+	if _, err := t.request("POST", "/api/v2/mix/order/cancel-plan-order", params); err != nil {
+		return fmt.Errorf("取消止盈止损单失败: %w", err)
+	}
+
+	log.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 格式化数量到sizeMultiplier的整数倍（实现Trader接口）
+func (t *BitgetTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	prec, err := t.getPrecision(symbol)
+	if err != nil {
+		return "", err
+	}
+
+	// 向下取整，避免超出可用保证金或持仓数量
+	qty := quantity
+	if prec.StepSize > 0 {
+		qty = math.Floor(quantity/prec.StepSize+1e-9) * prec.StepSize
+	}
+	return strconv.FormatFloat(qty, 'f', prec.QuantityPrecision, 64), nil
+}
@@ -0,0 +1,569 @@
+package trader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	bitmexMainnetURL = "https://www.bitmex.com"
+	bitmexTestnetURL = "https://testnet.bitmex.com"
+	// bitmexAPIPrefix 接口路径前缀，签名时需包含
+	bitmexAPIPrefix = "/api/v1"
+	// bitmexSettleCurrency USDT保证金在BitMEX中的币种代码
+	bitmexSettleCurrency = "USDt"
+	// bitmexUSDtScale USDt金额以百万分之一为单位返回
+	bitmexUSDtScale = 1e6
+	// bitmexExpiresSeconds 请求签名有效期
+	bitmexExpiresSeconds = 60
+)
+
+// BitMEXTrader BitMEX USDT本位永续合约交易器
+// BitMEX的线性合约按"合约数"下单，1个币 = underlyingToPositionMultiplier 个合约，
+// 下单数量需为 lotSize 的整数倍，对外接口仍使用币的数量
+type BitMEXTrader struct {
+	apiKey    string
+	secretKey string
+	rest      *restExchange
+
+	// 是否使用全仓，决定杠杆设置接口
+	cross bool
+
+	// 缓存合约信息
+	instruments map[string]bitmexInstrument
+	mu          sync.RWMutex
+}
+
+// bitmexInstrument 合约规格
+type bitmexInstrument struct {
+	LotSize                        float64 // 合约数步进
+	TickSize                       float64
+	UnderlyingToPositionMultiplier float64 // 1个币对应的合约数
+}
+
+func init() {
+	Register("bitmex", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用BitMEX合约交易", cfg.Name)
+		return NewBitMEXTrader(cfg.BitMEXAPIKey, cfg.BitMEXSecretKey, cfg.BitMEXTestnet), nil
+	})
+}
+
+// NewBitMEXTrader 创建BitMEX交易器
+// testnet为true时连接 testnet.bitmex.com
+func NewBitMEXTrader(apiKey, secretKey string, testnet bool) *BitMEXTrader {
+	baseURL := bitmexMainnetURL
+	if testnet {
+		baseURL = bitmexTestnetURL
+	}
+
+	t := &BitMEXTrader{
+		apiKey:      apiKey,
+		secretKey:   secretKey,
+		cross:       true,
+		instruments: make(map[string]bitmexInstrument),
+	}
+	t.rest = newRESTExchange(restExchangeConfig{
+		Name:    "BitMEX",
+		BaseURL: baseURL,
+		Signer:  t.sign,
+		Decoder: decodeBitMEX,
+		Symbols: restSymbolMapper{
+			toExchange:   toBitMEXSymbol,
+			fromExchange: fromBitMEXSymbol,
+		},
+	})
+	return t
+}
+
+// toBitMEXSymbol 将 BTCUSDT 转换为 BitMEX 合约代码 XBTUSDT
+func toBitMEXSymbol(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	if strings.HasPrefix(symbol, "BTC") {
+		return "XBT" + strings.TrimPrefix(symbol, "BTC")
+	}
+	return symbol
+}
+
+// fromBitMEXSymbol 将 BitMEX 合约代码 XBTUSDT 转换回 BTCUSDT
+func fromBitMEXSymbol(symbol string) string {
+	if strings.HasPrefix(symbol, "XBT") {
+		return "BTC" + strings.TrimPrefix(symbol, "XBT")
+	}
+	return symbol
+}
+
+// sign 签名钩子
+// 签名: hex(HMAC_SHA256(secret, verb + path(含querystring) + expires + body))
+func (t *BitMEXTrader) sign(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+	rawQuery := query.Encode()
+	expires := strconv.FormatInt(time.Now().Unix()+bitmexExpiresSeconds, 10)
+	mac := hmac.New(sha256.New, []byte(t.secretKey))
+	mac.Write([]byte(method + restRequestPath(path, rawQuery) + expires + string(body)))
+
+	header.Set("api-key", t.apiKey)
+	header.Set("api-expires", expires)
+	header.Set("api-signature", hex.EncodeToString(mac.Sum(nil)))
+	header.Set("Content-Type", "application/json")
+	return rawQuery, nil
+}
+
+// decodeBitMEX 非2xx状态码时解析 {"error":{"name","message"}}，成功时原样返回响应
+func decodeBitMEX(statusCode int, body []byte) (json.RawMessage, error) {
+	if statusCode < 200 || statusCode >= 300 {
+		var result struct {
+			Error struct {
+				Name    string `json:"name"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(body, &result); err == nil && result.Error.Message != "" {
+			return nil, fmt.Errorf("BitMEX API错误 %s: %s", result.Error.Name, result.Error.Message)
+		}
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+	return body, nil
+}
+
+// request 发送签名请求，GET参数放在querystring，其他方法以JSON放在body（BitMEX的DELETE也使用body）
+func (t *BitMEXTrader) request(method, path string, params map[string]interface{}) ([]byte, error) {
+	method = strings.ToUpper(method)
+
+	var query url.Values
+	var payload interface{}
+	if method == "GET" {
+		query = url.Values{}
+		for k, v := range params {
+			query.Set(k, fmt.Sprintf("%v", v))
+		}
+	} else if len(params) > 0 {
+		payload = params
+	}
+	return t.rest.signedCall(method, bitmexAPIPrefix+path, query, payload)
+}
+
+// publicGet 请求无需签名的行情接口
+func (t *BitMEXTrader) publicGet(path string, params url.Values) ([]byte, error) {
+	return t.rest.publicCall("GET", bitmexAPIPrefix+path, params)
+}
+
+// getInstrumentData 获取合约规格和行情价格（最新价，开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *BitMEXTrader) getInstrumentData(symbol string) (bitmexInstrument, float64, error) {
+	body, err := t.publicGet("/instrument", url.Values{"symbol": {t.rest.exchangeSymbol(symbol)}})
+	if err != nil {
+		return bitmexInstrument{}, 0, fmt.Errorf("获取合约信息失败: %w", err)
+	}
+
+	var instruments []struct {
+		LotSize                        float64 `json:"lotSize"`
+		TickSize                       float64 `json:"tickSize"`
+		UnderlyingToPositionMultiplier float64 `json:"underlyingToPositionMultiplier"`
+		LastPrice                      float64 `json:"lastPrice"`
+		BidPrice                       float64 `json:"bidPrice"`
+		AskPrice                       float64 `json:"askPrice"`
+	}
+	if err := json.Unmarshal(body, &instruments); err != nil {
+		return bitmexInstrument{}, 0, err
+	}
+	if len(instruments) == 0 || instruments[0].UnderlyingToPositionMultiplier <= 0 {
+		return bitmexInstrument{}, 0, fmt.Errorf("未找到合约 %s 的规格信息", t.rest.exchangeSymbol(symbol))
+	}
+
+	info := bitmexInstrument{
+		LotSize:                        instruments[0].LotSize,
+		TickSize:                       instruments[0].TickSize,
+		UnderlyingToPositionMultiplier: instruments[0].UnderlyingToPositionMultiplier,
+	}
+	if info.LotSize <= 0 {
+		info.LotSize = 1
+	}
+
+	t.mu.Lock()
+	t.instruments[t.rest.exchangeSymbol(symbol)] = info
+	t.mu.Unlock()
+
+	return info, pickMarketPrice(instruments[0].LastPrice, instruments[0].BidPrice, instruments[0].AskPrice), nil
+}
+
+// getInstrument 获取合约规格（优先使用缓存）
+func (t *BitMEXTrader) getInstrument(symbol string) (bitmexInstrument, error) {
+	t.mu.RLock()
+	if info, ok := t.instruments[t.rest.exchangeSymbol(symbol)]; ok {
+		t.mu.RUnlock()
+		return info, nil
+	}
+	t.mu.RUnlock()
+
+	info, _, err := t.getInstrumentData(symbol)
+	return info, err
+}
+
+// toContracts 将币的数量换算为合约数（向下取整到lotSize）
+func (t *BitMEXTrader) toContracts(symbol string, quantity float64) (int64, error) {
+	info, err := t.getInstrument(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	contracts := math.Floor(quantity*info.UnderlyingToPositionMultiplier/info.LotSize+1e-9) * info.LotSize
+	if contracts <= 0 {
+		return 0, fmt.Errorf("%s 数量 %.8f 低于最小下单量 %v", symbol, quantity, info.LotSize/info.UnderlyingToPositionMultiplier)
+	}
+	return int64(contracts), nil
+}
+
+// GetBalance 获取USDt保证金账户余额
+func (t *BitMEXTrader) GetBalance() (map[string]interface{}, error) {
+	body, err := t.request("GET", "/user/margin", map[string]interface{}{
+		"currency": bitmexSettleCurrency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	var margin struct {
+		WalletBalance   float64 `json:"walletBalance"`
+		UnrealisedPnl   float64 `json:"unrealisedPnl"`
+		AvailableMargin float64 `json:"availableMargin"`
+	}
+	if err := json.Unmarshal(body, &margin); err != nil {
+		return nil, err
+	}
+
+	// 返回与Binance相同的字段名
+	return map[string]interface{}{
+		"totalWalletBalance":    margin.WalletBalance / bitmexUSDtScale,
+		"availableBalance":      margin.AvailableMargin / bitmexUSDtScale,
+		"totalUnrealizedProfit": margin.UnrealisedPnl / bitmexUSDtScale,
+	}, nil
+}
+
+// GetPositions 获取USDt结算的持仓，数量由合约数换算为币的数量
+func (t *BitMEXTrader) GetPositions() ([]map[string]interface{}, error) {
+	body, err := t.request("GET", "/position", map[string]interface{}{
+		"filter": `{"isOpen":true}`,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	var positions []struct {
+		Symbol           string  `json:"symbol"`
+		Currency         string  `json:"currency"`
+		CurrentQty       float64 `json:"currentQty"` // 合约数，空仓为负
+		AvgEntryPrice    float64 `json:"avgEntryPrice"`
+		MarkPrice        float64 `json:"markPrice"`
+		UnrealisedPnl    float64 `json:"unrealisedPnl"`
+		Leverage         float64 `json:"leverage"`
+		LiquidationPrice float64 `json:"liquidationPrice"`
+	}
+	if err := json.Unmarshal(body, &positions); err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, pos := range positions {
+		if pos.CurrentQty == 0 || pos.Currency != bitmexSettleCurrency {
+			continue // 跳过空仓位和非USDt结算的合约
+		}
+
+		symbol := t.rest.localSymbol(pos.Symbol)
+		info, err := t.getInstrument(symbol)
+		if err != nil {
+			log.Printf("  ⚠ 获取 %s 合约信息失败: %v", pos.Symbol, err)
+			continue
+		}
+
+		side := "long"
+		if pos.CurrentQty < 0 {
+			side = "short"
+		}
+
+		// 返回与Binance相同的字段名
+		result = append(result, map[string]interface{}{
+			"symbol":           symbol,
+			"side":             side,
+			"positionAmt":      absFloat(pos.CurrentQty) / info.UnderlyingToPositionMultiplier,
+			"entryPrice":       pos.AvgEntryPrice,
+			"markPrice":        pos.MarkPrice,
+			"unRealizedProfit": pos.UnrealisedPnl / bitmexUSDtScale,
+			"leverage":         pos.Leverage,
+			"liquidationPrice": pos.LiquidationPrice,
+		})
+	}
+
+	return result, nil
+}
+
+// placeOrder 提交订单
+func (t *BitMEXTrader) placeOrder(params map[string]interface{}) (map[string]interface{}, error) {
+	body, err := t.request("POST", "/order", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var order struct {
+		OrderID   string  `json:"orderID"`
+		Symbol    string  `json:"symbol"`
+		OrderQty  float64 `json:"orderQty"`
+		OrdStatus string  `json:"ordStatus"`
+	}
+	if err := json.Unmarshal(body, &order); err != nil {
+		return nil, err
+	}
+
+	log.Printf("  订单ID: %s (%.0f合约)", order.OrderID, order.OrderQty)
+	return map[string]interface{}{
+		"orderId": order.OrderID,
+		"symbol":  t.rest.localSymbol(order.Symbol),
+		"status":  order.OrdStatus,
+	}, nil
+}
+
+// placeMarketOrder 按币的数量下市价单
+func (t *BitMEXTrader) placeMarketOrder(symbol, side string, quantity float64, reduceOnly bool) (map[string]interface{}, error) {
+	contracts, err := t.toContracts(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"symbol":   t.rest.exchangeSymbol(symbol),
+		"side":     side,
+		"orderQty": contracts,
+		"ordType":  "Market",
+	}
+	if reduceOnly {
+		params["execInst"] = "ReduceOnly"
+	}
+	return t.placeOrder(params)
+}
+
+// OpenLong 开多单
+func (t *BitMEXTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeMarketOrder(symbol, "Buy", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开多仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// OpenShort 开空单
+func (t *BitMEXTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeMarketOrder(symbol, "Sell", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开空仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// closePosition 平仓，quantity为0时使用 execInst=Close 平掉全部持仓
+func (t *BitMEXTrader) closePosition(symbol, side string, quantity float64) (map[string]interface{}, error) {
+	if quantity > 0 {
+		return t.placeMarketOrder(symbol, side, quantity, true)
+	}
+	return t.placeOrder(map[string]interface{}{
+		"symbol":   t.rest.exchangeSymbol(symbol),
+		"side":     side,
+		"ordType":  "Market",
+		"execInst": "Close",
+	})
+}
+
+// CloseLong 平多单（quantity为0时平掉全部多仓）
+func (t *BitMEXTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	result, err := t.closePosition(symbol, "Sell", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平多仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// CloseShort 平空单（quantity为0时平掉全部空仓）
+func (t *BitMEXTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	result, err := t.closePosition(symbol, "Buy", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平空仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// SetMarginMode 设置仓位模式（/position/isolate）
+func (t *BitMEXTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	t.mu.Lock()
+	t.cross = isCrossMargin
+	t.mu.Unlock()
+
+	_, err := t.request("POST", "/position/isolate", map[string]interface{}{
+		"symbol":  t.rest.exchangeSymbol(symbol),
+		"enabled": !isCrossMargin,
+	})
+	if err != nil {
+		log.Printf("  ⚠️ 设置仓位模式失败: %v", err)
+		// 不返回错误，让交易继续
+		return nil
+	}
+
+	modeName := "全仓"
+	if !isCrossMargin {
+		modeName = "逐仓"
+	}
+	log.Printf("  ✓ %s 仓位模式已设置为 %s", symbol, modeName)
+	return nil
+}
+
+// SetLeverage 设置杠杆倍数
+// 全仓使用 /position/crossLeverage（/position/leverage 传杠杆会切换为逐仓）
+func (t *BitMEXTrader) SetLeverage(symbol string, leverage int) error {
+	t.mu.RLock()
+	cross := t.cross
+	t.mu.RUnlock()
+
+	path := "/position/leverage"
+	if cross {
+		path = "/position/crossLeverage"
+	}
+
+	_, err := t.request("POST", path, map[string]interface{}{
+		"symbol":   t.rest.exchangeSymbol(symbol),
+		"leverage": leverage,
+	})
+	if err != nil {
+		return fmt.Errorf("设置杠杆失败: %w", err)
+	}
+
+	log.Printf("  ✓ %s 杠杆已切换为 %dx", symbol, leverage)
+	return nil
+}
+
+// GetMarketPrice 获取最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *BitMEXTrader) GetMarketPrice(symbol string) (float64, error) {
+	_, price, err := t.getInstrumentData(symbol)
+	if err != nil {
+		return 0, fmt.Errorf("获取价格失败: %w", err)
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("未找到 %s 的行情", symbol)
+	}
+	return price, nil
+}
+
+// placeTriggerOrder 下标记价格触发的reduce-only条件市价单
+// ordType: Stop(止损) / MarketIfTouched(止盈)
+func (t *BitMEXTrader) placeTriggerOrder(symbol, positionSide, ordType string, quantity, triggerPrice float64) error {
+	side := "Sell"
+	if positionSide == "SHORT" {
+		side = "Buy"
+	}
+
+	contracts, err := t.toContracts(symbol, quantity)
+	if err != nil {
+		return err
+	}
+	info, err := t.getInstrument(symbol)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.placeOrder(map[string]interface{}{
+		"symbol":   t.rest.exchangeSymbol(symbol),
+		"side":     side,
+		"orderQty": contracts,
+		"ordType":  ordType,
+		"stopPx":   roundToTickSize(triggerPrice, info.TickSize),
+		"execInst": "ReduceOnly,MarkPrice",
+	})
+	return err
+}
+
+// SetStopLoss 设置止损
+func (t *BitMEXTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, "Stop", quantity, stopPrice); err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	log.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈
+func (t *BitMEXTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, "MarketIfTouched", quantity, takeProfitPrice); err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	log.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 取消该合约的所有挂单（包括条件单）
+func (t *BitMEXTrader) CancelAllOrders(symbol string) error {
+	if _, err := t.request("DELETE", "/order/all", map[string]interface{}{
+		"symbol": t.rest.exchangeSymbol(symbol),
+	}); err != nil {
+		return fmt.Errorf("取消挂单失败: %w", err)
+	}
+
+	log.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 将数量向下取整到lotSize对应的币数量（实现Trader接口）
+func (t *BitMEXTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	info, err := t.getInstrument(symbol)
+	if err != nil {
+		return "", err
+	}
+
+	contracts := math.Floor(quantity*info.UnderlyingToPositionMultiplier/info.LotSize+1e-9) * info.LotSize
+	decimals := stepDecimals(info.LotSize / info.UnderlyingToPositionMultiplier)
+	return strconv.FormatFloat(contracts/info.UnderlyingToPositionMultiplier, 'f', decimals, 64), nil
+}
@@ -0,0 +1,661 @@
+package trader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	bybitMainnetURL  = "https://api.bybit.com"
+	bybitTestnetURL  = "https://api-testnet.bybit.com"
+	bybitRecvWindow  = "5000"
+	bybitCategory    = "linear" // USDT永续合约
+	bybitSettleCoin  = "USDT"
+	bybitAccountType = "UNIFIED" // 统一交易账户
+
+	// bybitLeverageNotModified 杠杆未变化（已是目标杠杆）
+	bybitLeverageNotModified = 110043
+)
+
+// BybitTrader Bybit V5 统一账户 USDT永续合约交易器
+type BybitTrader struct {
+	apiKey    string
+	secretKey string
+	rest      *restExchange
+	logger    *log.Logger
+
+	// 缓存交易对精度信息
+	symbolPrecision map[string]SymbolPrecision
+	mu              sync.RWMutex
+}
+
+// bybitResponse Bybit V5 通用响应结构
+type bybitResponse struct {
+	RetCode int             `json:"retCode"`
+	RetMsg  string          `json:"retMsg"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// BybitError Bybit V5 返回的业务错误（retCode非0）
+type BybitError struct {
+	RetCode int
+	RetMsg  string
+}
+
+func (e *BybitError) Error() string {
+	return fmt.Sprintf("Bybit API错误 %d: %s", e.RetCode, e.RetMsg)
+}
+
+func init() {
+	Register("bybit", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用Bybit合约交易", cfg.Name)
+		var opts []BybitOption
+		if cfg.BybitTestnet {
+			opts = append(opts, WithTestnet())
+		}
+		return NewBybitTrader(cfg.BybitAPIKey, cfg.BybitSecretKey, opts...), nil
+	})
+}
+
+// bybitOptions NewBybitTrader 的可选配置
+type bybitOptions struct {
+	testnet    bool
+	client     *http.Client
+	logger     *log.Logger
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// BybitOption NewBybitTrader 的可选配置项
+type BybitOption func(*bybitOptions)
+
+// WithTestnet 连接 api-testnet.bybit.com
+func WithTestnet() BybitOption {
+	return func(o *bybitOptions) { o.testnet = true }
+}
+
+// WithHTTPClient 使用自定义HTTP客户端（代理、超时等）
+func WithHTTPClient(client *http.Client) BybitOption {
+	return func(o *bybitOptions) { o.client = client }
+}
+
+// WithLogger 使用自定义logger输出交易和重试日志
+func WithLogger(logger *log.Logger) BybitOption {
+	return func(o *bybitOptions) { o.logger = logger }
+}
+
+// WithRetryPolicy 设置最大重试次数和首次重试等待时间（之后翻倍），不大于0时使用默认值（2次、500ms）
+// 哪些请求可以重试仍按 defaultRetryable，下单等非GET请求只在429时重试
+func WithRetryPolicy(maxRetries int, delay time.Duration) BybitOption {
+	return func(o *bybitOptions) {
+		o.maxRetries = maxRetries
+		o.retryDelay = delay
+	}
+}
+
+// NewBybitTrader 创建Bybit交易器，默认连接主网
+func NewBybitTrader(apiKey, secretKey string, opts ...BybitOption) *BybitTrader {
+	o := bybitOptions{logger: log.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	baseURL := bybitMainnetURL
+	if o.testnet {
+		baseURL = bybitTestnetURL
+	}
+
+	t := &BybitTrader{
+		apiKey:          apiKey,
+		secretKey:       secretKey,
+		logger:          o.logger,
+		symbolPrecision: make(map[string]SymbolPrecision),
+	}
+	t.rest = newRESTExchange(restExchangeConfig{
+		Name:       "Bybit",
+		BaseURL:    baseURL,
+		Signer:     t.sign,
+		Decoder:    decodeBybit,
+		MaxRetries: o.maxRetries,
+		RetryDelay: o.retryDelay,
+		Client:     o.client,
+		Logger:     o.logger,
+	})
+	return t
+}
+
+// sign 签名钩子: HMAC_SHA256(timestamp + apiKey + recvWindow + queryString|jsonBody)
+func (t *BybitTrader) sign(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+	rawQuery := query.Encode()
+	payload := rawQuery
+	if body != nil {
+		payload = string(body)
+	}
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	mac := hmac.New(sha256.New, []byte(t.secretKey))
+	mac.Write([]byte(timestamp + t.apiKey + bybitRecvWindow + payload))
+
+	header.Set("X-BAPI-API-KEY", t.apiKey)
+	header.Set("X-BAPI-TIMESTAMP", timestamp)
+	header.Set("X-BAPI-RECV-WINDOW", bybitRecvWindow)
+	header.Set("X-BAPI-SIGN", hex.EncodeToString(mac.Sum(nil)))
+	return rawQuery, nil
+}
+
+// decodeBybit 解析V5响应包装，返回result字段，retCode非0时返回 *BybitError
+func decodeBybit(statusCode int, body []byte) (json.RawMessage, error) {
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+
+	var result bybitResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if result.RetCode != 0 {
+		return nil, &BybitError{RetCode: result.RetCode, RetMsg: result.RetMsg}
+	}
+	return result.Result, nil
+}
+
+// request 发送签名请求，GET参数放在querystring，POST参数以JSON放在body
+// 返回响应中的result字段，retCode非0时返回错误
+func (t *BybitTrader) request(method, endpoint string, params map[string]interface{}) (json.RawMessage, error) {
+	method = strings.ToUpper(method)
+	if method != "GET" && method != "POST" {
+		return nil, fmt.Errorf("不支持的HTTP方法: %s", method)
+	}
+	query, payload := restParams(method, params)
+	return t.rest.signedCall(method, endpoint, query, payload)
+}
+
+// publicGet 请求无需签名的行情接口
+func (t *BybitTrader) publicGet(endpoint string, params url.Values) (json.RawMessage, error) {
+	return t.rest.publicCall("GET", endpoint, params)
+}
+
+// getPrecision 获取交易对精度信息
+func (t *BybitTrader) getPrecision(symbol string) (SymbolPrecision, error) {
+	t.mu.RLock()
+	if prec, ok := t.symbolPrecision[symbol]; ok {
+		t.mu.RUnlock()
+		return prec, nil
+	}
+	t.mu.RUnlock()
+
+	data, err := t.publicGet("/v5/market/instruments-info", url.Values{
+		"category": {bybitCategory},
+		"symbol":   {symbol},
+	})
+	if err != nil {
+		return SymbolPrecision{}, fmt.Errorf("获取交易对信息失败: %w", err)
+	}
+
+	var info struct {
+		List []struct {
+			Symbol      string `json:"symbol"`
+			PriceFilter struct {
+				TickSize string `json:"tickSize"`
+			} `json:"priceFilter"`
+			LotSizeFilter struct {
+				QtyStep string `json:"qtyStep"`
+			} `json:"lotSizeFilter"`
+		} `json:"list"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return SymbolPrecision{}, err
+	}
+	if len(info.List) == 0 {
+		return SymbolPrecision{}, fmt.Errorf("未找到交易对 %s 的精度信息", symbol)
+	}
+
+	item := info.List[0]
+	prec := SymbolPrecision{
+		PricePrecision:    calculatePrecision(item.PriceFilter.TickSize),
+		QuantityPrecision: calculatePrecision(item.LotSizeFilter.QtyStep),
+	}
+	prec.TickSize, _ = strconv.ParseFloat(item.PriceFilter.TickSize, 64)
+	prec.StepSize, _ = strconv.ParseFloat(item.LotSizeFilter.QtyStep, 64)
+
+	t.mu.Lock()
+	t.symbolPrecision[symbol] = prec
+	t.mu.Unlock()
+
+	return prec, nil
+}
+
+// formatPrice 格式化价格到tick size并转为字符串
+func (t *BybitTrader) formatPrice(symbol string, price float64) (string, error) {
+	prec, err := t.getPrecision(symbol)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(roundToTickSize(price, prec.TickSize), 'f', prec.PricePrecision, 64), nil
+}
+
+// GetBalance 获取统一账户余额
+func (t *BybitTrader) GetBalance() (map[string]interface{}, error) {
+	data, err := t.request("GET", "/v5/account/wallet-balance", map[string]interface{}{
+		"accountType": bybitAccountType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	var wallet struct {
+		List []struct {
+			TotalWalletBalance    string `json:"totalWalletBalance"`
+			TotalAvailableBalance string `json:"totalAvailableBalance"`
+			TotalPerpUPL          string `json:"totalPerpUPL"`
+		} `json:"list"`
+	}
+	if err := json.Unmarshal(data, &wallet); err != nil {
+		return nil, err
+	}
+	if len(wallet.List) == 0 {
+		return nil, fmt.Errorf("未找到%s账户", bybitAccountType)
+	}
+
+	account := wallet.List[0]
+	totalBalance, _ := strconv.ParseFloat(account.TotalWalletBalance, 64)
+	availableBalance, _ := strconv.ParseFloat(account.TotalAvailableBalance, 64)
+	unrealizedPnL, _ := strconv.ParseFloat(account.TotalPerpUPL, 64)
+
+	// 返回与Binance相同的字段名，确保AutoTrader能正确解析
+	return map[string]interface{}{
+		"totalWalletBalance":    totalBalance,
+		"availableBalance":      availableBalance,
+		"totalUnrealizedProfit": unrealizedPnL,
+	}, nil
+}
+
+// GetPositions 获取USDT永续持仓
+func (t *BybitTrader) GetPositions() ([]map[string]interface{}, error) {
+	data, err := t.request("GET", "/v5/position/list", map[string]interface{}{
+		"category":   bybitCategory,
+		"settleCoin": bybitSettleCoin,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	var positions struct {
+		List []struct {
+			Symbol        string `json:"symbol"`
+			Side          string `json:"side"` // Buy/Sell，空仓为空字符串
+			Size          string `json:"size"`
+			AvgPrice      string `json:"avgPrice"`
+			MarkPrice     string `json:"markPrice"`
+			UnrealisedPnl string `json:"unrealisedPnl"`
+			Leverage      string `json:"leverage"`
+			LiqPrice      string `json:"liqPrice"`
+		} `json:"list"`
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, pos := range positions.List {
+		size, _ := strconv.ParseFloat(pos.Size, 64)
+		if size == 0 {
+			continue // 跳过空仓位
+		}
+
+		side := "long"
+		if pos.Side == "Sell" {
+			side = "short"
+		}
+
+		entryPrice, _ := strconv.ParseFloat(pos.AvgPrice, 64)
+		markPrice, _ := strconv.ParseFloat(pos.MarkPrice, 64)
+		unRealizedProfit, _ := strconv.ParseFloat(pos.UnrealisedPnl, 64)
+		leverage, _ := strconv.ParseFloat(pos.Leverage, 64)
+		liquidationPrice, _ := strconv.ParseFloat(pos.LiqPrice, 64)
+
+		// 返回与Binance相同的字段名
+		result = append(result, map[string]interface{}{
+			"symbol":           pos.Symbol,
+			"side":             side,
+			"positionAmt":      size,
+			"entryPrice":       entryPrice,
+			"markPrice":        markPrice,
+			"unRealizedProfit": unRealizedProfit,
+			"leverage":         leverage,
+			"liquidationPrice": liquidationPrice,
+		})
+	}
+
+	return result, nil
+}
+
+// placeOrder 下市价单（单向持仓模式，positionIdx=0）
+func (t *BybitTrader) placeOrder(symbol, side string, quantity float64, reduceOnly bool) (map[string]interface{}, error) {
+	qtyStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := t.request("POST", "/v5/order/create", map[string]interface{}{
+		"category":    bybitCategory,
+		"symbol":      symbol,
+		"side":        side,
+		"orderType":   "Market",
+		"qty":         qtyStr,
+		"positionIdx": 0,
+		"reduceOnly":  reduceOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var order struct {
+		OrderID string `json:"orderId"`
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+
+	t.logger.Printf("  订单ID: %s", order.OrderID)
+	return map[string]interface{}{
+		"orderId":  order.OrderID,
+		"symbol":   symbol,
+		"quantity": qtyStr,
+	}, nil
+}
+
+// OpenLong 开多单
+func (t *BybitTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		t.logger.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "Buy", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	t.logger.Printf("✓ 开多仓成功: %s 数量: %v", symbol, result["quantity"])
+	return result, nil
+}
+
+// OpenShort 开空单
+func (t *BybitTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		t.logger.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "Sell", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开空仓失败: %w", err)
+	}
+
+	t.logger.Printf("✓ 开空仓成功: %s 数量: %v", symbol, result["quantity"])
+	return result, nil
+}
+
+// CloseLong 平多单（quantity为0时平掉全部多仓）
+func (t *BybitTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "long")
+		if err != nil {
+			return nil, err
+		}
+		t.logger.Printf("  📊 获取到多仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "Sell", quantity, true)
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	t.logger.Printf("✓ 平多仓成功: %s 数量: %v", symbol, result["quantity"])
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		t.logger.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// CloseShort 平空单（quantity为0时平掉全部空仓）
+func (t *BybitTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "short")
+		if err != nil {
+			return nil, err
+		}
+		t.logger.Printf("  📊 获取到空仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "Buy", quantity, true)
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+
+	t.logger.Printf("✓ 平空仓成功: %s 数量: %v", symbol, result["quantity"])
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		t.logger.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// positionQuantity 获取指定方向的持仓数量
+func (t *BybitTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return pos["positionAmt"].(float64), nil
+		}
+	}
+
+	sideName := "多仓"
+	if side == "short" {
+		sideName = "空仓"
+	}
+	return 0, fmt.Errorf("没有找到 %s 的%s", symbol, sideName)
+}
+
+// SetMarginMode 设置仓位模式
+// 统一账户的全仓/逐仓是账户级别设置，会作用于所有交易对
+func (t *BybitTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	marginMode := "REGULAR_MARGIN"
+	if !isCrossMargin {
+		marginMode = "ISOLATED_MARGIN"
+	}
+
+	_, err := t.request("POST", "/v5/account/set-margin-mode", map[string]interface{}{
+		"setMarginMode": marginMode,
+	})
+	if err != nil {
+		t.logger.Printf("  ⚠️ 设置仓位模式失败: %v", err)
+		// 不返回错误，让交易继续
+		return nil
+	}
+
+	t.logger.Printf("  ✓ 账户仓位模式已设置为 %s", marginMode)
+	return nil
+}
+
+// SetLeverage 设置杠杆倍数（多空同时设置）
+func (t *BybitTrader) SetLeverage(symbol string, leverage int) error {
+	lev := strconv.Itoa(leverage)
+	_, err := t.request("POST", "/v5/position/set-leverage", map[string]interface{}{
+		"category":     bybitCategory,
+		"symbol":       symbol,
+		"buyLeverage":  lev,
+		"sellLeverage": lev,
+	})
+	if err != nil {
+		var apiErr *BybitError
+		if errors.As(err, &apiErr) && apiErr.RetCode == bybitLeverageNotModified {
+			t.logger.Printf("  ✓ %s 杠杆已是 %dx", symbol, leverage)
+			return nil
+		}
+		return fmt.Errorf("设置杠杆失败: %w", err)
+	}
+
+	t.logger.Printf("  ✓ %s 杠杆已切换为 %dx", symbol, leverage)
+	return nil
+}
+
+// GetMarketPrice 获取最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *BybitTrader) GetMarketPrice(symbol string) (float64, error) {
+	data, err := t.publicGet("/v5/market/tickers", url.Values{
+		"category": {bybitCategory},
+		"symbol":   {symbol},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("获取价格失败: %w", err)
+	}
+
+	var tickers struct {
+		List []struct {
+			LastPrice string `json:"lastPrice"`
+			Bid1Price string `json:"bid1Price"`
+			Ask1Price string `json:"ask1Price"`
+		} `json:"list"`
+	}
+	if err := json.Unmarshal(data, &tickers); err != nil {
+		return 0, err
+	}
+	if len(tickers.List) == 0 {
+		return 0, fmt.Errorf("未找到 %s 的行情", symbol)
+	}
+
+	ticker := tickers.List[0]
+	last, err := strconv.ParseFloat(ticker.LastPrice, 64)
+	if err != nil {
+		return 0, err
+	}
+	bid, _ := strconv.ParseFloat(ticker.Bid1Price, 64)
+	ask, _ := strconv.ParseFloat(ticker.Ask1Price, 64)
+	return pickMarketPrice(last, bid, ask), nil
+}
+
+// placeTriggerOrder 下条件市价单，价格穿越triggerPrice时以reduce-only方式平仓
+// triggerDirection: 1=价格上涨触发, 2=价格下跌触发
+func (t *BybitTrader) placeTriggerOrder(symbol, positionSide string, quantity, triggerPrice float64, triggerDirection int) error {
+	side := "Sell"
+	if positionSide == "SHORT" {
+		side = "Buy"
+	}
+
+	qtyStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return err
+	}
+	priceStr, err := t.formatPrice(symbol, triggerPrice)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.request("POST", "/v5/order/create", map[string]interface{}{
+		"category":         bybitCategory,
+		"symbol":           symbol,
+		"side":             side,
+		"orderType":        "Market",
+		"qty":              qtyStr,
+		"triggerPrice":     priceStr,
+		"triggerDirection": triggerDirection,
+		"triggerBy":        "MarkPrice",
+		"positionIdx":      0,
+		"reduceOnly":       true,
+		"closeOnTrigger":   true,
+	})
+	return err
+}
+
+// SetStopLoss 设置止损
+func (t *BybitTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	// 多仓止损在价格下跌时触发，空仓止损在价格上涨时触发
+	direction := 2
+	if positionSide == "SHORT" {
+		direction = 1
+	}
+
+	if err := t.placeTriggerOrder(symbol, positionSide, quantity, stopPrice, direction); err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	t.logger.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈
+func (t *BybitTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	// 多仓止盈在价格上涨时触发，空仓止盈在价格下跌时触发
+	direction := 1
+	if positionSide == "SHORT" {
+		direction = 2
+	}
+
+	if err := t.placeTriggerOrder(symbol, positionSide, quantity, takeProfitPrice, direction); err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	t.logger.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 取消该币种的所有挂单（包括条件单）
+func (t *BybitTrader) CancelAllOrders(symbol string) error {
+	_, err := t.request("POST", "/v5/order/cancel-all", map[string]interface{}{
+		"category": bybitCategory,
+		"symbol":   symbol,
+	})
+	if err != nil {
+		return fmt.Errorf("取消挂单失败: %w", err)
+	}
+
+	t.logger.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 格式化数量到qtyStep的整数倍（实现Trader接口）
+func (t *BybitTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	prec, err := t.getPrecision(symbol)
+	if err != nil {
+		return "", err
+	}
+
+	// 向下取整，避免超出可用保证金或持仓数量
+	qty := quantity
+	if prec.StepSize > 0 {
+		qty = math.Floor(quantity/prec.StepSize+1e-9) * prec.StepSize
+	}
+	return strconv.FormatFloat(qty, 'f', prec.QuantityPrecision, 64), nil
+}
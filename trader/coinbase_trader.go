@@ -0,0 +1,715 @@
+package trader
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"nofx/market"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	coinbaseHost          = "api.coinbase.com"
+	coinbaseQuoteCurrency = "USD"
+	// coinbaseStopLimitSlippage 止损触发后限价单相对触发价的让价比例，保证快速成交
+	coinbaseStopLimitSlippage = 0.005
+	// coinbaseDustValue 低于该美元价值的余额视为零头，不作为持仓返回
+	coinbaseDustValue = 1.0
+)
+
+// CoinbaseTrader Coinbase Advanced Trade 现货交易器
+// 现货没有杠杆和做空：OpenLong/CloseLong 对应现货买入/卖出，杠杆参数被忽略，
+// OpenShort/CloseShort 直接返回错误。用于无法使用衍生品的地区
+type CoinbaseTrader struct {
+	keyName    string
+	privateKey *ecdsa.PrivateKey
+	rest       *restExchange
+
+	// 缓存交易对信息
+	products map[string]coinbaseProduct
+	// 本交易器买入的成本价（现货账户不提供持仓均价）
+	entryPrices map[string]float64
+	// 已设置的止损价，设置止盈时与止损合并为括号单
+	stopLosses map[string]float64
+	mu         sync.RWMutex
+}
+
+// coinbaseProduct 交易对规格
+type coinbaseProduct struct {
+	BaseIncrement  float64
+	QuoteIncrement float64
+	BaseMinSize    float64
+}
+
+// coinbaseOrderResponse 下单响应结构
+type coinbaseOrderResponse struct {
+	Success         bool `json:"success"`
+	SuccessResponse struct {
+		OrderID string `json:"order_id"`
+	} `json:"success_response"`
+	ErrorResponse struct {
+		Error                string `json:"error"`
+		Message              string `json:"message"`
+		PreviewFailureReason string `json:"preview_failure_reason"`
+	} `json:"error_response"`
+}
+
+func init() {
+	Register("coinbase", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用Coinbase现货交易（仅做多，不使用杠杆）", cfg.Name)
+		t, err := NewCoinbaseTrader(cfg.CoinbaseAPIKey, cfg.CoinbaseSecretKey)
+		if err != nil {
+			return nil, fmt.Errorf("初始化Coinbase交易器失败: %w", err)
+		}
+		return t, nil
+	})
+}
+
+// NewCoinbaseTrader 创建Coinbase现货交易器
+// apiKey为CDP API Key名称（organizations/{org_id}/apiKeys/{key_id}），secretKey为EC私钥PEM
+func NewCoinbaseTrader(apiKey, secretKey string) (*CoinbaseTrader, error) {
+	// 配置中的PEM换行常被保存为字面量 \n
+	pem := strings.ReplaceAll(secretKey, `\n`, "\n")
+	privateKey, err := jwt.ParseECPrivateKeyFromPEM([]byte(pem))
+	if err != nil {
+		return nil, fmt.Errorf("解析Coinbase私钥失败: %w", err)
+	}
+
+	t := &CoinbaseTrader{
+		keyName:     apiKey,
+		privateKey:  privateKey,
+		products:    make(map[string]coinbaseProduct),
+		entryPrices: make(map[string]float64),
+		stopLosses:  make(map[string]float64),
+	}
+	// 不设置Decoder：非2xx状态码视为错误，2xx原样返回响应
+	t.rest = newRESTExchange(restExchangeConfig{
+		Name:    "Coinbase",
+		BaseURL: "https://" + coinbaseHost,
+		Signer:  t.sign,
+		Symbols: restSymbolMapper{toExchange: toCoinbaseProduct},
+	})
+	return t, nil
+}
+
+// toCoinbaseProduct 将 BTCUSDT 转换为 Coinbase 交易对 BTC-USD
+func toCoinbaseProduct(symbol string) string {
+	return strings.TrimSuffix(strings.ToUpper(symbol), "USDT") + "-" + coinbaseQuoteCurrency
+}
+
+// buildJWT 为单个请求生成ES256签名的JWT，有效期2分钟
+func (t *CoinbaseTrader) buildJWT(method, path string) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"sub": t.keyName,
+		"iss": "cdp",
+		"nbf": now.Unix(),
+		"exp": now.Add(2 * time.Minute).Unix(),
+		"uri": method + " " + coinbaseHost + path,
+	})
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	token.Header["kid"] = t.keyName
+	token.Header["nonce"] = hex.EncodeToString(nonce)
+
+	return token.SignedString(t.privateKey)
+}
+
+// sign 签名钩子：为每个请求生成JWT（签名的uri不包含querystring）
+func (t *CoinbaseTrader) sign(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+	token, err := t.buildJWT(method, path)
+	if err != nil {
+		return "", fmt.Errorf("生成JWT失败: %w", err)
+	}
+	header.Set("Authorization", "Bearer "+token)
+	header.Set("Content-Type", "application/json")
+	return query.Encode(), nil
+}
+
+// request 发送带JWT认证的请求
+func (t *CoinbaseTrader) request(method, path string, query url.Values, payload interface{}) ([]byte, error) {
+	return t.rest.signedCall(method, path, query, payload)
+}
+
+// publicGet 请求无需认证的行情接口
+func (t *CoinbaseTrader) publicGet(path string) ([]byte, error) {
+	return t.rest.publicCall("GET", path, nil)
+}
+
+// getProduct 获取交易对规格和最新价格
+func (t *CoinbaseTrader) getProduct(productID string) (coinbaseProduct, float64, error) {
+	data, err := t.publicGet("/api/v3/brokerage/market/products/" + productID)
+	if err != nil {
+		return coinbaseProduct{}, 0, fmt.Errorf("获取交易对信息失败: %w", err)
+	}
+
+	var info struct {
+		Price          string `json:"price"`
+		BaseIncrement  string `json:"base_increment"`
+		QuoteIncrement string `json:"quote_increment"`
+		BaseMinSize    string `json:"base_min_size"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return coinbaseProduct{}, 0, err
+	}
+
+	product := coinbaseProduct{}
+	product.BaseIncrement, _ = strconv.ParseFloat(info.BaseIncrement, 64)
+	product.QuoteIncrement, _ = strconv.ParseFloat(info.QuoteIncrement, 64)
+	product.BaseMinSize, _ = strconv.ParseFloat(info.BaseMinSize, 64)
+	price, _ := strconv.ParseFloat(info.Price, 64)
+	if product.BaseIncrement <= 0 {
+		return coinbaseProduct{}, 0, fmt.Errorf("未找到交易对 %s 的规格信息", productID)
+	}
+
+	t.mu.Lock()
+	t.products[productID] = product
+	t.mu.Unlock()
+
+	return product, price, nil
+}
+
+// getCachedProduct 获取交易对规格（优先使用缓存）
+func (t *CoinbaseTrader) getCachedProduct(productID string) (coinbaseProduct, error) {
+	t.mu.RLock()
+	if product, ok := t.products[productID]; ok {
+		t.mu.RUnlock()
+		return product, nil
+	}
+	t.mu.RUnlock()
+
+	product, _, err := t.getProduct(productID)
+	return product, err
+}
+
+// formatBase 将币数量向下取整到base_increment
+func (t *CoinbaseTrader) formatBase(productID string, quantity float64) (string, error) {
+	product, err := t.getCachedProduct(productID)
+	if err != nil {
+		return "", err
+	}
+
+	size := math.Floor(quantity/product.BaseIncrement+1e-9) * product.BaseIncrement
+	if size <= 0 || size < product.BaseMinSize {
+		return "", fmt.Errorf("%s 数量 %.8f 低于最小下单量 %v", productID, quantity, product.BaseMinSize)
+	}
+	return strconv.FormatFloat(size, 'f', stepDecimals(product.BaseIncrement), 64), nil
+}
+
+// formatPrice 格式化价格到quote_increment
+func (t *CoinbaseTrader) formatPrice(productID string, price float64) (string, error) {
+	product, err := t.getCachedProduct(productID)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(roundToTickSize(price, product.QuoteIncrement), 'f', stepDecimals(product.QuoteIncrement), 64), nil
+}
+
+// listAccounts 获取所有币种账户，返回 币种 -> (可用, 冻结)
+func (t *CoinbaseTrader) listAccounts() (map[string][2]float64, error) {
+	result := make(map[string][2]float64)
+	cursor := ""
+
+	for {
+		query := url.Values{"limit": {"250"}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		data, err := t.request("GET", "/api/v3/brokerage/accounts", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Accounts []struct {
+				Currency         string `json:"currency"`
+				AvailableBalance struct {
+					Value string `json:"value"`
+				} `json:"available_balance"`
+				Hold struct {
+					Value string `json:"value"`
+				} `json:"hold"`
+			} `json:"accounts"`
+			HasNext bool   `json:"has_next"`
+			Cursor  string `json:"cursor"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, err
+		}
+
+		for _, acc := range page.Accounts {
+			available, _ := strconv.ParseFloat(acc.AvailableBalance.Value, 64)
+			hold, _ := strconv.ParseFloat(acc.Hold.Value, 64)
+			if available == 0 && hold == 0 {
+				continue
+			}
+			balance := result[acc.Currency]
+			result[acc.Currency] = [2]float64{balance[0] + available, balance[1] + hold}
+		}
+
+		if !page.HasNext || page.Cursor == "" {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	return result, nil
+}
+
+// isCoinbaseCash 判断是否为计价现金（USD/USDC）
+func isCoinbaseCash(currency string) bool {
+	return currency == "USD" || currency == "USDC"
+}
+
+// GetBalance 获取账户余额：现金加上持仓币按最新价折算的总价值
+func (t *CoinbaseTrader) GetBalance() (map[string]interface{}, error) {
+	accounts, err := t.listAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	positions, err := t.positionsFromAccounts(accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	cash := 0.0
+	availableCash := 0.0
+	for currency, balance := range accounts {
+		if isCoinbaseCash(currency) {
+			cash += balance[0] + balance[1]
+			availableCash += balance[0]
+		}
+	}
+
+	// 现货的"钱包余额"按持仓成本计，未实现盈亏单独返回，与合约账户口径一致
+	walletBalance := cash
+	unrealizedPnL := 0.0
+	for _, pos := range positions {
+		walletBalance += pos["positionAmt"].(float64) * pos["entryPrice"].(float64)
+		unrealizedPnL += pos["unRealizedProfit"].(float64)
+	}
+
+	// 返回与Binance相同的字段名
+	return map[string]interface{}{
+		"totalWalletBalance":    walletBalance,
+		"availableBalance":      availableCash,
+		"totalUnrealizedProfit": unrealizedPnL,
+	}, nil
+}
+
+// GetPositions 将非现金币种余额作为多仓返回
+func (t *CoinbaseTrader) GetPositions() ([]map[string]interface{}, error) {
+	accounts, err := t.listAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+	return t.positionsFromAccounts(accounts)
+}
+
+// positionsFromAccounts 根据账户余额构造持仓，成本价未知时使用最新价
+func (t *CoinbaseTrader) positionsFromAccounts(accounts map[string][2]float64) ([]map[string]interface{}, error) {
+	result := []map[string]interface{}{}
+	for currency, balance := range accounts {
+		if isCoinbaseCash(currency) {
+			continue
+		}
+
+		quantity := balance[0] + balance[1]
+		symbol := currency + "USDT"
+		_, markPrice, err := t.getProduct(t.rest.exchangeSymbol(symbol))
+		if err != nil || markPrice <= 0 {
+			continue // 没有USD交易对的币种不作为持仓
+		}
+		if quantity*markPrice < coinbaseDustValue {
+			continue // 跳过零头
+		}
+
+		t.mu.RLock()
+		entryPrice, ok := t.entryPrices[symbol]
+		t.mu.RUnlock()
+		if !ok {
+			entryPrice = markPrice
+		}
+
+		// 返回与Binance相同的字段名
+		result = append(result, map[string]interface{}{
+			"symbol":           symbol,
+			"side":             "long",
+			"positionAmt":      quantity,
+			"entryPrice":       entryPrice,
+			"markPrice":        markPrice,
+			"unRealizedProfit": (markPrice - entryPrice) * quantity,
+			"leverage":         1.0,
+			"liquidationPrice": 0.0,
+		})
+	}
+
+	return result, nil
+}
+
+// placeOrder 提交订单，orderConfiguration 为 order_configuration 字段
+func (t *CoinbaseTrader) placeOrder(productID, side string, orderConfiguration map[string]interface{}) (string, error) {
+	data, err := t.request("POST", "/api/v3/brokerage/orders", nil, map[string]interface{}{
+		"client_order_id":     fmt.Sprintf("nofx%d", time.Now().UnixNano()),
+		"product_id":          productID,
+		"side":                side,
+		"order_configuration": orderConfiguration,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var result coinbaseOrderResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", err
+	}
+	if !result.Success {
+		return "", fmt.Errorf("下单失败: %s %s %s", result.ErrorResponse.Error, result.ErrorResponse.Message, result.ErrorResponse.PreviewFailureReason)
+	}
+
+	log.Printf("  订单ID: %s", result.SuccessResponse.OrderID)
+	return result.SuccessResponse.OrderID, nil
+}
+
+// OpenLong 现货市价买入（杠杆参数被忽略）
+// 市价买单按计价金额(quote_size)提交，由币数量乘以最新价换算
+func (t *CoinbaseTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	productID := t.rest.exchangeSymbol(symbol)
+	product, price, err := t.getProduct(productID)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+	quoteSize := math.Floor(quantity*price/product.QuoteIncrement+1e-9) * product.QuoteIncrement
+	quoteStr := strconv.FormatFloat(quoteSize, 'f', stepDecimals(product.QuoteIncrement), 64)
+
+	orderID, err := t.placeOrder(productID, "BUY", map[string]interface{}{
+		"market_market_ioc": map[string]string{"quote_size": quoteStr},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	// 记录成本价，现货账户不返回持仓均价
+	t.mu.Lock()
+	t.entryPrices[symbol] = price
+	t.mu.Unlock()
+
+	log.Printf("✓ 开多仓成功: %s 数量: %.8f (%s %s)", symbol, quantity, quoteStr, coinbaseQuoteCurrency)
+	return map[string]interface{}{
+		"orderId": orderID,
+		"symbol":  symbol,
+		"status":  "NEW",
+	}, nil
+}
+
+// OpenShort 现货不支持做空
+func (t *CoinbaseTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	return nil, fmt.Errorf("Coinbase现货不支持做空: %s", symbol)
+}
+
+// CloseLong 现货市价卖出（quantity为0时卖出全部持仓）
+func (t *CoinbaseTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	// 先取消挂单，释放被止损止盈单冻结的余额
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	closeAll := quantity == 0
+	if closeAll {
+		var err error
+		quantity, err = t.positionQuantity(symbol)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到多仓数量: %.8f", quantity)
+	}
+
+	productID := t.rest.exchangeSymbol(symbol)
+	baseStr, err := t.formatBase(productID, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	orderID, err := t.placeOrder(productID, "SELL", map[string]interface{}{
+		"market_market_ioc": map[string]string{"base_size": baseStr},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	if closeAll {
+		t.mu.Lock()
+		delete(t.entryPrices, symbol)
+		t.mu.Unlock()
+	}
+
+	log.Printf("✓ 平多仓成功: %s 数量: %s", symbol, baseStr)
+	return map[string]interface{}{
+		"orderId": orderID,
+		"symbol":  symbol,
+		"status":  "NEW",
+	}, nil
+}
+
+// CloseShort 现货不支持做空
+func (t *CoinbaseTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	return nil, fmt.Errorf("Coinbase现货不支持做空: %s", symbol)
+}
+
+// positionQuantity 获取币种现货余额
+func (t *CoinbaseTrader) positionQuantity(symbol string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pos := range positions {
+		if pos["symbol"] == symbol {
+			return pos["positionAmt"].(float64), nil
+		}
+	}
+	return 0, fmt.Errorf("没有找到 %s 的多仓", symbol)
+}
+
+// SetMarginMode 现货没有保证金模式，直接忽略
+func (t *CoinbaseTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	return nil
+}
+
+// SetLeverage 现货没有杠杆，直接忽略
+func (t *CoinbaseTrader) SetLeverage(symbol string, leverage int) error {
+	if leverage > 1 {
+		log.Printf("  ⚠ Coinbase现货不支持杠杆，忽略 %dx 设置", leverage)
+	}
+	return nil
+}
+
+// GetMarketPrice 获取最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *CoinbaseTrader) GetMarketPrice(symbol string) (float64, error) {
+	productID := t.rest.exchangeSymbol(symbol)
+	_, price, err := t.getProduct(productID)
+	if err != nil {
+		return 0, fmt.Errorf("获取价格失败: %w", err)
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("未找到 %s 的行情", symbol)
+	}
+
+	// 交易对信息不含买一卖一价，中间价模式下额外查询订单簿
+	if market.UseMidPrice() {
+		bid, ask, err := t.getBestBidAsk(productID)
+		if err != nil {
+			return 0, fmt.Errorf("获取买一卖一价失败: %w", err)
+		}
+		return pickMarketPrice(price, bid, ask), nil
+	}
+	return price, nil
+}
+
+// getBestBidAsk 获取买一价和卖一价
+func (t *CoinbaseTrader) getBestBidAsk(productID string) (bid, ask float64, err error) {
+	data, err := t.rest.publicCall("GET", "/api/v3/brokerage/market/product_book", url.Values{
+		"product_id": {productID},
+		"limit":      {"1"},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	type level struct {
+		Price string `json:"price"`
+	}
+	var book struct {
+		Pricebook struct {
+			Bids []level `json:"bids"`
+			Asks []level `json:"asks"`
+		} `json:"pricebook"`
+	}
+	if err := json.Unmarshal(data, &book); err != nil {
+		return 0, 0, err
+	}
+	if len(book.Pricebook.Bids) == 0 || len(book.Pricebook.Asks) == 0 {
+		return 0, 0, fmt.Errorf("%s 订单簿为空", productID)
+	}
+
+	bid, _ = strconv.ParseFloat(book.Pricebook.Bids[0].Price, 64)
+	ask, _ = strconv.ParseFloat(book.Pricebook.Asks[0].Price, 64)
+	return bid, ask, nil
+}
+
+// SetStopLoss 设置止损（止损限价卖单）
+// 现货卖单会冻结余额，之后设置止盈时会与止损合并为一个括号单
+func (t *CoinbaseTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if positionSide == "SHORT" {
+		return fmt.Errorf("Coinbase现货不支持做空: %s", symbol)
+	}
+
+	productID := t.rest.exchangeSymbol(symbol)
+	baseStr, err := t.formatBase(productID, quantity)
+	if err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+	stopStr, err := t.formatPrice(productID, stopPrice)
+	if err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+	limitStr, err := t.formatPrice(productID, stopPrice*(1-coinbaseStopLimitSlippage))
+	if err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	_, err = t.placeOrder(productID, "SELL", map[string]interface{}{
+		"stop_limit_stop_limit_gtc": map[string]string{
+			"base_size":      baseStr,
+			"limit_price":    limitStr,
+			"stop_price":     stopStr,
+			"stop_direction": "STOP_DIRECTION_STOP_DOWN",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	t.mu.Lock()
+	t.stopLosses[symbol] = stopPrice
+	t.mu.Unlock()
+
+	log.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈
+// 已有止损时撤掉止损单，改为同时包含止盈限价和止损触发价的括号单；否则挂限价卖单
+func (t *CoinbaseTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if positionSide == "SHORT" {
+		return fmt.Errorf("Coinbase现货不支持做空: %s", symbol)
+	}
+
+	productID := t.rest.exchangeSymbol(symbol)
+	baseStr, err := t.formatBase(productID, quantity)
+	if err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+	limitStr, err := t.formatPrice(productID, takeProfitPrice)
+	if err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	t.mu.RLock()
+	stopPrice, hasStop := t.stopLosses[symbol]
+	t.mu.RUnlock()
+
+	orderConfiguration := map[string]interface{}{
+		"limit_limit_gtc": map[string]string{
+			"base_size":   baseStr,
+			"limit_price": limitStr,
+		},
+	}
+	if hasStop {
+		stopStr, err := t.formatPrice(productID, stopPrice)
+		if err != nil {
+			return fmt.Errorf("设置止盈失败: %w", err)
+		}
+		if err := t.CancelAllOrders(symbol); err != nil {
+			return fmt.Errorf("设置止盈失败: %w", err)
+		}
+		orderConfiguration = map[string]interface{}{
+			"trigger_bracket_gtc": map[string]string{
+				"base_size":          baseStr,
+				"limit_price":        limitStr,
+				"stop_trigger_price": stopStr,
+			},
+		}
+	}
+
+	if _, err := t.placeOrder(productID, "SELL", orderConfiguration); err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	if hasStop {
+		// CancelAllOrders 会清除止损记录，括号单中仍包含止损
+		t.mu.Lock()
+		t.stopLosses[symbol] = stopPrice
+		t.mu.Unlock()
+	}
+
+	log.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 取消该交易对的所有未成交订单
+func (t *CoinbaseTrader) CancelAllOrders(symbol string) error {
+	productID := t.rest.exchangeSymbol(symbol)
+	data, err := t.request("GET", "/api/v3/brokerage/orders/historical/batch", url.Values{
+		"product_ids":  {productID},
+		"order_status": {"OPEN"},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("获取挂单失败: %w", err)
+	}
+
+	var result struct {
+		Orders []struct {
+			OrderID string `json:"order_id"`
+		} `json:"orders"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	delete(t.stopLosses, symbol)
+	t.mu.Unlock()
+
+	if len(result.Orders) == 0 {
+		return nil
+	}
+
+	orderIDs := make([]string, 0, len(result.Orders))
+	for _, order := range result.Orders {
+		orderIDs = append(orderIDs, order.OrderID)
+	}
+	if _, err := t.request("POST", "/api/v3/brokerage/orders/batch_cancel", nil, map[string]interface{}{
+		"order_ids": orderIDs,
+	}); err != nil {
+		return fmt.Errorf("取消挂单失败: %w", err)
+	}
+
+	log.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 将数量向下取整到base_increment（实现Trader接口）
+func (t *CoinbaseTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	product, err := t.getCachedProduct(t.rest.exchangeSymbol(symbol))
+	if err != nil {
+		return "", err
+	}
+
+	size := math.Floor(quantity/product.BaseIncrement+1e-9) * product.BaseIncrement
+	return strconv.FormatFloat(size, 'f', stepDecimals(product.BaseIncrement), 64), nil
+}
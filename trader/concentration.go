@@ -0,0 +1,78 @@
+package trader
+
+import (
+	"fmt"
+	"log"
+)
+
+// hhiWarningThreshold HHI超过该值时输出集中度警告
+const hhiWarningThreshold = 0.5
+
+// ConcentrationReport 持仓集中度报告
+type ConcentrationReport struct {
+	HHI                 float64 // 赫芬达尔指数，0为完全分散，1为单一持仓
+	TopHolding          string  // 名义价值最大的持仓（symbol_side）
+	TopHoldingPct       float64 // 最大持仓占总名义价值的百分比
+	NumberOfPositions   int     // 持仓数量
+	MaxConcentrationPct float64 // 单一币种（多空合并）占总名义价值的最大百分比
+}
+
+// GetHHI 计算持仓名义价值的赫芬达尔指数 HHI = Σ(名义价值_i / 总名义价值)²
+func GetHHI(t Trader) (float64, error) {
+	report, err := GetConcentrationReport(t)
+	if err != nil {
+		return 0, err
+	}
+	return report.HHI, nil
+}
+
+// GetConcentrationReport 获取持仓集中度报告，HHI超过0.5时输出警告
+func GetConcentrationReport(t Trader) (*ConcentrationReport, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	notionals := make(map[string]float64)
+	symbolNotionals := make(map[string]float64)
+	total := 0.0
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		side, _ := pos["side"].(string)
+		quantity, _ := pos["positionAmt"].(float64)
+		markPrice, _ := pos["markPrice"].(float64)
+
+		notional := absFloat(quantity) * markPrice
+		if notional <= 0 {
+			continue
+		}
+		notionals[symbol+"_"+side] += notional
+		symbolNotionals[symbol] += notional
+		total += notional
+	}
+
+	report := &ConcentrationReport{NumberOfPositions: len(notionals)}
+	if total == 0 {
+		return report, nil
+	}
+
+	for key, notional := range notionals {
+		share := notional / total
+		report.HHI += share * share
+		if share*100 > report.TopHoldingPct {
+			report.TopHolding = key
+			report.TopHoldingPct = share * 100
+		}
+	}
+	for _, notional := range symbolNotionals {
+		if pct := notional / total * 100; pct > report.MaxConcentrationPct {
+			report.MaxConcentrationPct = pct
+		}
+	}
+
+	if report.HHI > hhiWarningThreshold {
+		log.Printf("⚠️  持仓集中度过高: HHI=%.3f，最大持仓 %s 占比 %.1f%%", report.HHI, report.TopHolding, report.TopHoldingPct)
+	}
+
+	return report, nil
+}
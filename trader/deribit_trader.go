@@ -0,0 +1,552 @@
+package trader
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	deribitMainnetURL = "https://www.deribit.com"
+	deribitTestnetURL = "https://test.deribit.com"
+)
+
+// deribitCurrencies Deribit 支持的反向永续合约币种
+var deribitCurrencies = []string{"BTC", "ETH"}
+
+// DeribitTrader Deribit BTC/ETH 反向永续合约交易器
+// 反向合约以美元面值下单（BTC每张10美元，ETH每张1美元），保证金和盈亏以币结算
+// 对外接口仍使用币的数量和美元计价的余额，换算在内部完成
+type DeribitTrader struct {
+	authHeader string
+	rest       *restExchange
+
+	// 缓存合约信息
+	instruments map[string]deribitInstrument
+	mu          sync.RWMutex
+}
+
+// deribitInstrument 合约规格
+type deribitInstrument struct {
+	ContractSize float64 // 每张合约的美元面值
+	TickSize     float64
+}
+
+// deribitResponse Deribit JSON-RPC 响应结构
+type deribitResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func init() {
+	Register("deribit", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用Deribit反向永续合约交易", cfg.Name)
+		return NewDeribitTrader(cfg.DeribitClientID, cfg.DeribitClientSecret, cfg.DeribitTestnet), nil
+	})
+}
+
+// NewDeribitTrader 创建Deribit交易器
+// testnet为true时连接 test.deribit.com
+func NewDeribitTrader(clientID, clientSecret string, testnet bool) *DeribitTrader {
+	baseURL := deribitMainnetURL
+	if testnet {
+		baseURL = deribitTestnetURL
+	}
+
+	t := &DeribitTrader{
+		authHeader:  "Basic " + base64.StdEncoding.EncodeToString([]byte(clientID+":"+clientSecret)),
+		instruments: make(map[string]deribitInstrument),
+	}
+	t.rest = newRESTExchange(restExchangeConfig{
+		Name:      "Deribit",
+		BaseURL:   baseURL + "/api/v2/",
+		Signer:    t.sign,
+		Decoder:   decodeDeribit,
+		Retryable: deribitRetryable,
+	})
+	return t
+}
+
+// toDeribitInstrument 将 BTCUSDT 转换为 BTC-PERPETUAL，仅支持BTC和ETH
+func toDeribitInstrument(symbol string) (string, error) {
+	base := strings.TrimSuffix(strings.ToUpper(symbol), "USDT")
+	for _, currency := range deribitCurrencies {
+		if base == currency {
+			return currency + "-PERPETUAL", nil
+		}
+	}
+	return "", fmt.Errorf("Deribit不支持 %s 的反向永续合约（仅支持BTC、ETH）", symbol)
+}
+
+// fromDeribitInstrument 将 BTC-PERPETUAL 转换回 BTCUSDT
+func fromDeribitInstrument(instrument string) string {
+	return strings.TrimSuffix(instrument, "-PERPETUAL") + "USDT"
+}
+
+// sign 签名钩子，私有接口使用Basic认证
+func (t *DeribitTrader) sign(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+	header.Set("Authorization", t.authHeader)
+	return query.Encode(), nil
+}
+
+// decodeDeribit 解析JSON-RPC响应，error不为空时返回错误
+func decodeDeribit(statusCode int, body []byte) (json.RawMessage, error) {
+	var result deribitResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("Deribit API错误 %d: %s", result.Error.Code, result.Error.Message)
+	}
+	return result.Result, nil
+}
+
+// deribitRetryable 所有接口都通过GET调用，下单、撤单等私有写接口只在429时重试，避免重复下单
+func deribitRetryable(method, endpoint string, statusCode int) bool {
+	if strings.HasPrefix(endpoint, "private/") && !strings.HasPrefix(endpoint, "private/get_") {
+		return statusCode == http.StatusTooManyRequests
+	}
+	return defaultRetryable(method, endpoint, statusCode)
+}
+
+// call 调用JSON-RPC over HTTP接口，private/ 开头的方法需要签名
+func (t *DeribitTrader) call(method string, params url.Values) (json.RawMessage, error) {
+	if strings.HasPrefix(method, "private/") {
+		return t.rest.signedCall("GET", method, params, nil)
+	}
+	return t.rest.publicCall("GET", method, params)
+}
+
+// getInstrument 获取合约规格
+func (t *DeribitTrader) getInstrument(instrument string) (deribitInstrument, error) {
+	t.mu.RLock()
+	if info, ok := t.instruments[instrument]; ok {
+		t.mu.RUnlock()
+		return info, nil
+	}
+	t.mu.RUnlock()
+
+	data, err := t.call("public/get_instrument", url.Values{"instrument_name": {instrument}})
+	if err != nil {
+		return deribitInstrument{}, fmt.Errorf("获取合约信息失败: %w", err)
+	}
+
+	var info deribitInstrument
+	var raw struct {
+		ContractSize float64 `json:"contract_size"`
+		TickSize     float64 `json:"tick_size"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return deribitInstrument{}, err
+	}
+	if raw.ContractSize <= 0 {
+		return deribitInstrument{}, fmt.Errorf("未找到合约 %s 的规格信息", instrument)
+	}
+	info.ContractSize = raw.ContractSize
+	info.TickSize = raw.TickSize
+
+	t.mu.Lock()
+	t.instruments[instrument] = info
+	t.mu.Unlock()
+
+	return info, nil
+}
+
+// getIndexPrice 获取币种的美元指数价格
+func (t *DeribitTrader) getIndexPrice(currency string) (float64, error) {
+	data, err := t.call("public/get_index_price", url.Values{
+		"index_name": {strings.ToLower(currency) + "_usd"},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("获取%s指数价格失败: %w", currency, err)
+	}
+
+	var index struct {
+		IndexPrice float64 `json:"index_price"`
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return 0, err
+	}
+	return index.IndexPrice, nil
+}
+
+// toUSDAmount 将币的数量按当前价格换算为美元面值，向下取整到合约面值的整数倍
+func (t *DeribitTrader) toUSDAmount(symbol string, quantity float64) (float64, string, error) {
+	instrument, err := toDeribitInstrument(symbol)
+	if err != nil {
+		return 0, "", err
+	}
+	info, err := t.getInstrument(instrument)
+	if err != nil {
+		return 0, "", err
+	}
+	price, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return 0, "", err
+	}
+
+	amount := math.Floor(quantity*price/info.ContractSize+1e-9) * info.ContractSize
+	if amount <= 0 {
+		return 0, "", fmt.Errorf("%s 数量 %.8f 不足一张合约（每张 %v 美元）", symbol, quantity, info.ContractSize)
+	}
+	return amount, instrument, nil
+}
+
+// GetBalance 获取BTC和ETH账户权益，按指数价格折算为美元后汇总
+func (t *DeribitTrader) GetBalance() (map[string]interface{}, error) {
+	totalBalance := 0.0
+	availableBalance := 0.0
+	unrealizedPnL := 0.0
+
+	for _, currency := range deribitCurrencies {
+		data, err := t.call("private/get_account_summary", url.Values{"currency": {currency}})
+		if err != nil {
+			return nil, fmt.Errorf("获取%s账户余额失败: %w", currency, err)
+		}
+
+		var summary struct {
+			Balance          float64 `json:"balance"`
+			AvailableFunds   float64 `json:"available_funds"`
+			FuturesSessionPL float64 `json:"futures_session_upl"`
+		}
+		if err := json.Unmarshal(data, &summary); err != nil {
+			return nil, err
+		}
+		if summary.Balance == 0 && summary.FuturesSessionPL == 0 {
+			continue
+		}
+
+		indexPrice, err := t.getIndexPrice(currency)
+		if err != nil {
+			return nil, err
+		}
+		totalBalance += summary.Balance * indexPrice
+		availableBalance += summary.AvailableFunds * indexPrice
+		unrealizedPnL += summary.FuturesSessionPL * indexPrice
+	}
+
+	// 返回与Binance相同的字段名（美元计价）
+	return map[string]interface{}{
+		"totalWalletBalance":    totalBalance,
+		"availableBalance":      availableBalance,
+		"totalUnrealizedProfit": unrealizedPnL,
+	}, nil
+}
+
+// GetPositions 获取永续合约持仓，数量使用币的数量，盈亏折算为美元
+func (t *DeribitTrader) GetPositions() ([]map[string]interface{}, error) {
+	result := []map[string]interface{}{}
+
+	for _, currency := range deribitCurrencies {
+		data, err := t.call("private/get_positions", url.Values{
+			"currency": {currency},
+			"kind":     {"future"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("获取%s持仓失败: %w", currency, err)
+		}
+
+		var positions []struct {
+			InstrumentName            string  `json:"instrument_name"`
+			Direction                 string  `json:"direction"` // buy/sell/zero
+			SizeCurrency              float64 `json:"size_currency"`
+			AveragePrice              float64 `json:"average_price"`
+			MarkPrice                 float64 `json:"mark_price"`
+			FloatingProfitLoss        float64 `json:"floating_profit_loss"` // 以币计价
+			Leverage                  float64 `json:"leverage"`
+			EstimatedLiquidationPrice float64 `json:"estimated_liquidation_price"`
+		}
+		if err := json.Unmarshal(data, &positions); err != nil {
+			return nil, err
+		}
+
+		for _, pos := range positions {
+			if !strings.HasSuffix(pos.InstrumentName, "-PERPETUAL") || pos.Direction == "zero" || pos.SizeCurrency == 0 {
+				continue
+			}
+
+			side := "long"
+			if pos.Direction == "sell" {
+				side = "short"
+			}
+
+			// 返回与Binance相同的字段名
+			result = append(result, map[string]interface{}{
+				"symbol":           fromDeribitInstrument(pos.InstrumentName),
+				"side":             side,
+				"positionAmt":      absFloat(pos.SizeCurrency),
+				"entryPrice":       pos.AveragePrice,
+				"markPrice":        pos.MarkPrice,
+				"unRealizedProfit": pos.FloatingProfitLoss * pos.MarkPrice,
+				"leverage":         pos.Leverage,
+				"liquidationPrice": pos.EstimatedLiquidationPrice,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// placeOrder 下单（private/buy 或 private/sell），amount为美元面值
+func (t *DeribitTrader) placeOrder(direction string, params url.Values) (map[string]interface{}, error) {
+	data, err := t.call("private/"+direction, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Order struct {
+			OrderID    string `json:"order_id"`
+			OrderState string `json:"order_state"`
+		} `json:"order"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	log.Printf("  订单ID: %s", result.Order.OrderID)
+	return map[string]interface{}{
+		"orderId": result.Order.OrderID,
+		"symbol":  fromDeribitInstrument(params.Get("instrument_name")),
+		"status":  result.Order.OrderState,
+		"amount":  params.Get("amount"),
+	}, nil
+}
+
+// placeMarketOrder 按币的数量下市价单
+func (t *DeribitTrader) placeMarketOrder(symbol, direction string, quantity float64, reduceOnly bool) (map[string]interface{}, error) {
+	amount, instrument, err := t.toUSDAmount(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{
+		"instrument_name": {instrument},
+		"amount":          {strconv.FormatFloat(amount, 'f', -1, 64)},
+		"type":            {"market"},
+	}
+	if reduceOnly {
+		params.Set("reduce_only", "true")
+	}
+
+	log.Printf("  📏 反向合约换算: %.8f 币 -> %s 美元面值", quantity, params.Get("amount"))
+	return t.placeOrder(direction, params)
+}
+
+// OpenLong 开多单
+func (t *DeribitTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeMarketOrder(symbol, "buy", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开多仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// OpenShort 开空单
+func (t *DeribitTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeMarketOrder(symbol, "sell", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开空仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// closePosition 平仓，quantity为0时使用 close_position 按美元面值全部平掉，避免换算残留
+func (t *DeribitTrader) closePosition(symbol, direction string, quantity float64) (map[string]interface{}, error) {
+	if quantity > 0 {
+		return t.placeMarketOrder(symbol, direction, quantity, true)
+	}
+
+	instrument, err := toDeribitInstrument(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return t.placeOrder("close_position", url.Values{
+		"instrument_name": {instrument},
+		"type":            {"market"},
+	})
+}
+
+// CloseLong 平多单（quantity为0时平掉全部多仓）
+func (t *DeribitTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	result, err := t.closePosition(symbol, "sell", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平多仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// CloseShort 平空单（quantity为0时平掉全部空仓）
+func (t *DeribitTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	result, err := t.closePosition(symbol, "buy", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平空仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// SetMarginMode 设置仓位模式
+// Deribit 账户统一使用全仓（组合）保证金，不支持按合约切换
+func (t *DeribitTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	if !isCrossMargin {
+		log.Printf("  ⚠️ Deribit不支持逐仓，%s 使用账户全仓保证金", symbol)
+	}
+	return nil
+}
+
+// SetLeverage 设置杠杆倍数
+// Deribit 没有杠杆设置，实际杠杆由下单面值与账户权益决定，仓位大小需由调用方控制
+func (t *DeribitTrader) SetLeverage(symbol string, leverage int) error {
+	log.Printf("  ✓ %s Deribit无需设置杠杆（目标 %dx 由仓位大小控制）", symbol, leverage)
+	return nil
+}
+
+// GetMarketPrice 获取最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *DeribitTrader) GetMarketPrice(symbol string) (float64, error) {
+	instrument, err := toDeribitInstrument(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := t.call("public/ticker", url.Values{"instrument_name": {instrument}})
+	if err != nil {
+		return 0, fmt.Errorf("获取价格失败: %w", err)
+	}
+
+	var ticker struct {
+		LastPrice    float64 `json:"last_price"`
+		BestBidPrice float64 `json:"best_bid_price"`
+		BestAskPrice float64 `json:"best_ask_price"`
+	}
+	if err := json.Unmarshal(data, &ticker); err != nil {
+		return 0, err
+	}
+	if ticker.LastPrice <= 0 {
+		return 0, fmt.Errorf("未找到 %s 的行情", symbol)
+	}
+	return pickMarketPrice(ticker.LastPrice, ticker.BestBidPrice, ticker.BestAskPrice), nil
+}
+
+// placeTriggerOrder 下标记价格触发的reduce-only条件市价单
+// orderType: stop_market(止损) / take_market(止盈)
+func (t *DeribitTrader) placeTriggerOrder(symbol, positionSide, orderType string, quantity, triggerPrice float64) error {
+	direction := "sell"
+	if positionSide == "SHORT" {
+		direction = "buy"
+	}
+
+	amount, instrument, err := t.toUSDAmount(symbol, quantity)
+	if err != nil {
+		return err
+	}
+	info, err := t.getInstrument(instrument)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.placeOrder(direction, url.Values{
+		"instrument_name": {instrument},
+		"amount":          {strconv.FormatFloat(amount, 'f', -1, 64)},
+		"type":            {orderType},
+		"trigger":         {"mark_price"},
+		"trigger_price":   {strconv.FormatFloat(roundToTickSize(triggerPrice, info.TickSize), 'f', stepDecimals(info.TickSize), 64)},
+		"reduce_only":     {"true"},
+	})
+	return err
+}
+
+// SetStopLoss 设置止损
+func (t *DeribitTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, "stop_market", quantity, stopPrice); err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	log.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈
+func (t *DeribitTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, "take_market", quantity, takeProfitPrice); err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	log.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 取消该合约的所有挂单（包括条件单）
+func (t *DeribitTrader) CancelAllOrders(symbol string) error {
+	instrument, err := toDeribitInstrument(symbol)
+	if err != nil {
+		return err
+	}
+
+	if _, err := t.call("private/cancel_all_by_instrument", url.Values{"instrument_name": {instrument}}); err != nil {
+		return fmt.Errorf("取消挂单失败: %w", err)
+	}
+
+	log.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 按当前价格将数量取整到整数张合约后，返回对应的币数量（实现Trader接口）
+func (t *DeribitTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	amount, _, err := t.toUSDAmount(symbol, quantity)
+	if err != nil {
+		return "", err
+	}
+	price, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(amount/price, 'f', 8, 64), nil
+}
@@ -0,0 +1,120 @@
+package trader
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sync"
+)
+
+// DrawdownSizer 回撤感知的仓位计算器：账户从峰值回撤越深，仓位越小
+type DrawdownSizer struct {
+	BaseSize       float64   // 无回撤时的基准仓位
+	MinSize        float64   // 仓位下限
+	MaxDrawdown    float64   // 回撤达到该比例(0-1)时直接使用最小仓位，0表示不限制
+	RecoveryFactor float64   // 回撤缩仓系数：仓位 = 基准 × (1 - 回撤 × 系数)
+	TradeHistory   []float64 // 历史交易收益率（如 -0.02 表示亏损2%），由调用方维护，不参与 ComputeSize
+}
+
+// ComputeSize 根据当前净值和峰值净值计算仓位，只使用净值回撤
+// BaseSize × max(MinSize/BaseSize, 1 - 回撤 × RecoveryFactor)
+func (s *DrawdownSizer) ComputeSize(currentEquity, peakEquity float64) float64 {
+	if s.BaseSize <= 0 {
+		return 0
+	}
+
+	drawdown := 0.0
+	if peakEquity > 0 && currentEquity < peakEquity {
+		drawdown = (peakEquity - currentEquity) / peakEquity
+	}
+	if drawdown <= 0 {
+		return s.BaseSize
+	}
+
+	if s.MaxDrawdown > 0 && drawdown >= s.MaxDrawdown {
+		return s.MinSize
+	}
+
+	factor := math.Max(s.MinSize/s.BaseSize, 1-drawdown*s.RecoveryFactor)
+	return s.BaseSize * factor
+}
+
+// LosingStreakDrawdown 按 TradeHistory 计算最近连续亏损交易的累计回撤：1 - Π(1 + 收益率)，最近一笔盈利时为0
+// 供调用方在净值尚未反映亏损时自行判断是否缩仓，ComputeSize 不使用
+func (s *DrawdownSizer) LosingStreakDrawdown() float64 {
+	equity := 1.0
+	for i := len(s.TradeHistory) - 1; i >= 0 && s.TradeHistory[i] < 0; i-- {
+		equity *= 1 + s.TradeHistory[i]
+	}
+	return math.Max(0, 1-equity)
+}
+
+// SizingMiddleware 包装Trader，开仓前按当前回撤调整下单数量，其余方法直接转发
+type SizingMiddleware struct {
+	Trader
+	sizer      *DrawdownSizer
+	mu         sync.Mutex
+	peakEquity float64
+}
+
+// NewSizingMiddleware 创建回撤缩仓中间件
+func NewSizingMiddleware(t Trader, sizer *DrawdownSizer) *SizingMiddleware {
+	return &SizingMiddleware{
+		Trader: t,
+		sizer:  sizer,
+	}
+}
+
+// OpenLong 按回撤缩放数量后开多仓
+func (m *SizingMiddleware) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	adjusted, err := m.adjustQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+	return m.Trader.OpenLong(symbol, adjusted, leverage)
+}
+
+// OpenShort 按回撤缩放数量后开空仓
+func (m *SizingMiddleware) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	adjusted, err := m.adjustQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+	return m.Trader.OpenShort(symbol, adjusted, leverage)
+}
+
+// adjustQuantity 获取账户净值，更新峰值，并按 ComputeSize / BaseSize 的比例缩放数量
+// 缩放后数量为0（达到最大回撤且 MinSize 为0）时返回错误，不向交易所下单
+func (m *SizingMiddleware) adjustQuantity(symbol string, quantity float64) (float64, error) {
+	balance, err := m.Trader.GetBalance()
+	if err != nil {
+		return 0, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	walletBalance, _ := balance["totalWalletBalance"].(float64)
+	unrealizedProfit, _ := balance["totalUnrealizedProfit"].(float64)
+	equity := walletBalance + unrealizedProfit
+
+	m.mu.Lock()
+	if equity > m.peakEquity {
+		m.peakEquity = equity
+	}
+	peakEquity := m.peakEquity
+	m.mu.Unlock()
+
+	if m.sizer.BaseSize <= 0 {
+		return quantity, nil
+	}
+
+	scale := m.sizer.ComputeSize(equity, peakEquity) / m.sizer.BaseSize
+	if scale >= 1 {
+		return quantity, nil
+	}
+
+	adjusted := quantity * scale
+	if adjusted <= 0 {
+		return 0, fmt.Errorf("回撤超过上限，暂停开仓: 净值 %.2f / 峰值 %.2f", equity, peakEquity)
+	}
+	log.Printf("  📉 回撤缩仓 %s: 净值 %.2f / 峰值 %.2f，数量 %.6f -> %.6f", symbol, equity, peakEquity, quantity, adjusted)
+	return adjusted, nil
+}
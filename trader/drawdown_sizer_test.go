@@ -0,0 +1,80 @@
+package trader
+
+import "testing"
+
+func TestDrawdownSizerComputeSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		history       []float64
+		current, peak float64
+		want          float64
+	}{
+		{"无回撤", nil, 1000, 1000, 100},
+		{"净值回撤10%", nil, 900, 1000, 80},
+		{"达到最大回撤", nil, 700, 1000, 20},
+		{"净值回撤24%", nil, 760, 1000, 52},
+		// 只按净值回撤计算，交易历史中的连续亏损不影响仓位
+		{"连续亏损不缩仓", []float64{0.03, -0.05, -0.05}, 1000, 1000, 100},
+		{"连续亏损时按净值回撤", []float64{-0.02}, 900, 1000, 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sizer := &DrawdownSizer{BaseSize: 100, MinSize: 20, MaxDrawdown: 0.3, RecoveryFactor: 2, TradeHistory: tt.history}
+			if got := sizer.ComputeSize(tt.current, tt.peak); !almostEqual(got, tt.want) {
+				t.Errorf("ComputeSize(%v, %v) = %v, want %v", tt.current, tt.peak, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDrawdownSizerLosingStreakDrawdown(t *testing.T) {
+	tests := []struct {
+		history []float64
+		want    float64
+	}{
+		{nil, 0},
+		{[]float64{-0.05, 0.02}, 0}, // 最近一笔盈利
+		{[]float64{0.03, -0.05, -0.05}, 0.0975},
+	}
+	for _, tt := range tests {
+		sizer := &DrawdownSizer{TradeHistory: tt.history}
+		if got := sizer.LosingStreakDrawdown(); !almostEqual(got, tt.want) {
+			t.Errorf("LosingStreakDrawdown(%v) = %v, want %v", tt.history, got, tt.want)
+		}
+	}
+}
+
+// equityTrader 返回固定净值并记录开仓数量，其余方法不应被调用
+type equityTrader struct {
+	Trader
+	equity float64
+	opened []float64
+}
+
+func (e *equityTrader) GetBalance() (map[string]interface{}, error) {
+	return map[string]interface{}{"totalWalletBalance": e.equity, "totalUnrealizedProfit": 0.0}, nil
+}
+
+func (e *equityTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	e.opened = append(e.opened, quantity)
+	return map[string]interface{}{}, nil
+}
+
+func TestSizingMiddlewarePausesAtMaxDrawdown(t *testing.T) {
+	inner := &equityTrader{equity: 1000}
+	m := NewSizingMiddleware(inner, &DrawdownSizer{BaseSize: 100, MaxDrawdown: 0.3, RecoveryFactor: 2})
+
+	if _, err := m.OpenLong("BTCUSDT", 1, 10); err != nil {
+		t.Fatalf("OpenLong 返回错误: %v", err)
+	}
+
+	// 回撤40%超过上限且 MinSize 为0，不应向交易所下0数量的单
+	inner.equity = 600
+	if _, err := m.OpenLong("BTCUSDT", 1, 10); err == nil {
+		t.Error("超过最大回撤时应返回错误")
+	}
+	if len(inner.opened) != 1 || inner.opened[0] != 1 {
+		t.Errorf("开仓数量 = %v, want [1]", inner.opened)
+	}
+}
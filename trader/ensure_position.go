@@ -0,0 +1,197 @@
+package trader
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// EnsurePositionResult EnsurePosition的执行结果
+type EnsurePositionResult struct {
+	Action    string  // open, increase, reduce, reverse, close, none
+	FilledQty float64 // 本次下单数量（已按精度格式化）
+	OrderId   string  // 最后一笔订单ID
+}
+
+// EnsurePosition 调整持仓使其达到目标数量
+// side: "long" 或 "short"；targetQty=0 表示全部平仓
+// 1. 无持仓 → 开仓到目标数量
+// 2. 持仓不足 → 加仓补足差额
+// 3. 持仓超出 → 平掉多出的部分
+// 4. 方向相反 → 先全部平掉反向持仓，再开仓到目标数量
+//
+// 各交易所的 OpenLong/OpenShort 开仓前会取消该币种的所有挂单，加仓也会撤掉原有的止损止盈。
+// 因此只要本次有开仓，就按 stopLoss/takeProfit 以目标数量重新挂止损止盈（价格为0表示不设置，
+// 此时原有的止损止盈不会恢复）；只减仓时不撤单，原有的止损止盈保持不变。
+func EnsurePosition(t Trader, symbol, side string, targetQty float64, leverage int, stopLoss, takeProfit float64) (*EnsurePositionResult, error) {
+	if side != "long" && side != "short" {
+		return nil, fmt.Errorf("无效的持仓方向: %s", side)
+	}
+	if targetQty < 0 {
+		return nil, fmt.Errorf("目标数量不能为负数: %.8f", targetQty)
+	}
+
+	positions, err := t.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	currentQty := 0.0
+	oppositeQty := 0.0
+	for _, pos := range positions {
+		if pos["symbol"] != symbol {
+			continue
+		}
+		qty, _ := pos["positionAmt"].(float64)
+		if pos["side"] == side {
+			currentQty = absFloat(qty)
+		} else {
+			oppositeQty = absFloat(qty)
+		}
+	}
+
+	result := &EnsurePositionResult{Action: "none"}
+
+	// 目标为0：平掉该币种的所有持仓
+	if targetQty == 0 {
+		if currentQty > 0 {
+			order, err := closePosition(t, symbol, side, 0)
+			if err != nil {
+				return nil, err
+			}
+			result.Action = "close"
+			result.FilledQty = currentQty
+			result.OrderId = orderIDString(order)
+		}
+		if oppositeQty > 0 {
+			order, err := closePosition(t, symbol, oppositeSide(side), 0)
+			if err != nil {
+				return nil, err
+			}
+			result.Action = "close"
+			result.FilledQty += oppositeQty
+			result.OrderId = orderIDString(order)
+		}
+		return result, nil
+	}
+
+	// 方向相反：先平反向持仓
+	if oppositeQty > 0 {
+		log.Printf("  🔄 %s 持有反向仓位 %.8f，先平仓再开 %s", symbol, oppositeQty, side)
+		if _, err := closePosition(t, symbol, oppositeSide(side), 0); err != nil {
+			return nil, fmt.Errorf("平反向持仓失败: %w", err)
+		}
+		result.Action = "reverse"
+	}
+
+	diff, err := formattedQuantity(t, symbol, targetQty-currentQty)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case diff > 0:
+		order, err := openPosition(t, symbol, side, diff, leverage)
+		if err != nil {
+			return nil, err
+		}
+		if result.Action != "reverse" {
+			if currentQty == 0 {
+				result.Action = "open"
+			} else {
+				result.Action = "increase"
+			}
+		}
+		result.FilledQty = diff
+		result.OrderId = orderIDString(order)
+		placeProtectiveOrders(t, symbol, side, targetQty, stopLoss, takeProfit)
+	case diff < 0:
+		order, err := closePosition(t, symbol, side, -diff)
+		if err != nil {
+			return nil, err
+		}
+		result.Action = "reduce"
+		result.FilledQty = -diff
+		result.OrderId = orderIDString(order)
+	case result.Action == "reverse":
+		// 已有目标方向的足量持仓，只平掉了反向持仓
+		result.Action = "close"
+		result.FilledQty = oppositeQty
+	}
+
+	log.Printf("  ✓ EnsurePosition %s %s: 当前 %.8f → 目标 %.8f (%s)", symbol, side, currentQty, targetQty, result.Action)
+	return result, nil
+}
+
+// placeProtectiveOrders 开仓后按持仓总量重新设置止损止盈（价格为0表示不设置），失败只记录日志
+func placeProtectiveOrders(t Trader, symbol, side string, quantity, stopLoss, takeProfit float64) {
+	positionSide := "LONG"
+	if side == "short" {
+		positionSide = "SHORT"
+	}
+	if stopLoss > 0 {
+		if err := t.SetStopLoss(symbol, positionSide, quantity, stopLoss); err != nil {
+			log.Printf("  ⚠ 设置止损失败: %v", err)
+		}
+	}
+	if takeProfit > 0 {
+		if err := t.SetTakeProfit(symbol, positionSide, quantity, takeProfit); err != nil {
+			log.Printf("  ⚠ 设置止盈失败: %v", err)
+		}
+	}
+}
+
+// formattedQuantity 按交易所精度格式化数量，差额小于最小步进时返回0
+func formattedQuantity(t Trader, symbol string, quantity float64) (float64, error) {
+	sign := 1.0
+	if quantity < 0 {
+		sign = -1.0
+	}
+
+	qtyStr, err := t.FormatQuantity(symbol, absFloat(quantity))
+	if err != nil {
+		return 0, fmt.Errorf("格式化数量失败: %w", err)
+	}
+
+	formatted, err := strconv.ParseFloat(qtyStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("解析数量失败: %w", err)
+	}
+	return sign * formatted, nil
+}
+
+// openPosition 按方向开仓
+func openPosition(t Trader, symbol, side string, quantity float64, leverage int) (map[string]interface{}, error) {
+	if side == "long" {
+		return t.OpenLong(symbol, quantity, leverage)
+	}
+	return t.OpenShort(symbol, quantity, leverage)
+}
+
+// closePosition 按方向平仓（quantity=0表示全部平仓）
+func closePosition(t Trader, symbol, side string, quantity float64) (map[string]interface{}, error) {
+	if side == "long" {
+		return t.CloseLong(symbol, quantity)
+	}
+	return t.CloseShort(symbol, quantity)
+}
+
+// oppositeSide 返回相反的持仓方向
+func oppositeSide(side string) string {
+	if side == "long" {
+		return "short"
+	}
+	return "long"
+}
+
+// orderIDString 将不同交易所返回的订单ID统一转为字符串
+func orderIDString(order map[string]interface{}) string {
+	if order == nil || order["orderId"] == nil {
+		return ""
+	}
+	// JSON解析出的数字ID是float64，避免输出科学计数法
+	if id, ok := order["orderId"].(float64); ok {
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", order["orderId"])
+}
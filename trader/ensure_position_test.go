@@ -0,0 +1,66 @@
+package trader_test
+
+import (
+	"testing"
+
+	"nofx/trader"
+	"nofx/trader/tradertest"
+)
+
+func TestEnsurePositionIncreaseRestoresProtection(t *testing.T) {
+	mock := tradertest.NewMockTrader(1000)
+	mock.Positions = []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "positionAmt": 1.0},
+	}
+
+	result, err := trader.EnsurePosition(mock, "BTCUSDT", "long", 3, 10, 90, 120)
+	if err != nil {
+		t.Fatalf("EnsurePosition 返回错误: %v", err)
+	}
+	if result.Action != "increase" || result.FilledQty != 2 {
+		t.Errorf("结果 = %+v, want increase 2", result)
+	}
+	if got := quantityArg(t, mock, "OpenLong", 1); got != 2 {
+		t.Errorf("加仓数量 = %v, want 2", got)
+	}
+	// 开仓会撤掉原有条件单，需要按目标总量重新设置
+	if got := quantityArg(t, mock, "SetStopLoss", 2); got != 3 {
+		t.Errorf("止损数量 = %v, want 3", got)
+	}
+	if got := quantityArg(t, mock, "SetTakeProfit", 2); got != 3 {
+		t.Errorf("止盈数量 = %v, want 3", got)
+	}
+
+	// 只减仓时不重新设置止损止盈
+	mock.Positions[0]["positionAmt"] = 3.0
+	mock.Reset()
+	if _, err := trader.EnsurePosition(mock, "BTCUSDT", "long", 1, 10, 90, 120); err != nil {
+		t.Fatalf("EnsurePosition 返回错误: %v", err)
+	}
+	if n := len(mock.CallsTo("SetStopLoss")); n != 0 {
+		t.Errorf("减仓后 SetStopLoss 调用次数 = %d, want 0", n)
+	}
+}
+
+func TestEnsurePositionCloseOppositeOnly(t *testing.T) {
+	// 双向持仓：多仓已达到目标，只需平掉空仓
+	mock := tradertest.NewMockTrader(1000)
+	mock.Positions = []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "positionAmt": 2.0},
+		{"symbol": "BTCUSDT", "side": "short", "positionAmt": -1.0},
+	}
+
+	result, err := trader.EnsurePosition(mock, "BTCUSDT", "long", 2, 10, 0, 0)
+	if err != nil {
+		t.Fatalf("EnsurePosition 返回错误: %v", err)
+	}
+	if result.Action != "close" || result.FilledQty != 1 {
+		t.Errorf("结果 = %+v, want close 1", result)
+	}
+	if n := len(mock.CallsTo("OpenLong")); n != 0 {
+		t.Errorf("OpenLong 调用次数 = %d, want 0", n)
+	}
+	if got := quantityArg(t, mock, "CloseShort", 1); got != 0 {
+		t.Errorf("平空数量 = %v, want 0（全部平仓）", got)
+	}
+}
@@ -0,0 +1,132 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/market"
+	"strings"
+	"time"
+)
+
+// FundingOutcome 下次资金费结算的预测结果
+type FundingOutcome struct {
+	Direction           string        // "pay" 支付 或 "receive" 收取
+	EstimatedAmountUSDT float64       // 预估资金费金额（按当前持仓名义价值计算）
+	SettlementIn        time.Duration // 距离下次结算的时间
+	FundingRate         float64       // 当前预估资金费率
+}
+
+// GetFundingOutcome 预测持仓在下次资金费结算时是支付还是收取资金费
+// 资金费率为正时多头支付、空头收取；为负时相反
+// positionSide: "long"/"short"（也兼容 "LONG"/"SHORT"）
+// 没有对应持仓时金额为0，但仍返回方向和结算时间，便于开仓前判断
+func GetFundingOutcome(t Trader, symbol, positionSide string) (*FundingOutcome, error) {
+	side := strings.ToLower(positionSide)
+	if side != "long" && side != "short" {
+		return nil, fmt.Errorf("无效的持仓方向: %s", positionSide)
+	}
+
+	info, err := market.GetFundingInfo(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("获取资金费率失败: %w", err)
+	}
+
+	positions, err := t.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	notional := 0.0
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			quantity, _ := pos["positionAmt"].(float64)
+			markPrice, _ := pos["markPrice"].(float64)
+			if markPrice == 0 {
+				markPrice = info.MarkPrice
+			}
+			notional = absFloat(quantity) * markPrice
+			break
+		}
+	}
+
+	// 多头在正费率时支付，空头在负费率时支付
+	pays := (side == "long" && info.FundingRate > 0) || (side == "short" && info.FundingRate < 0)
+	direction := "receive"
+	if pays {
+		direction = "pay"
+	}
+
+	settlementIn := time.Until(info.NextFundingTime)
+	if settlementIn < 0 {
+		settlementIn = 0
+	}
+
+	return &FundingOutcome{
+		Direction:           direction,
+		EstimatedAmountUSDT: absFloat(info.FundingRate) * notional,
+		SettlementIn:        settlementIn,
+		FundingRate:         info.FundingRate,
+	}, nil
+}
+
+// CumulativeFundingResult 持仓期间累计资金费
+type CumulativeFundingResult struct {
+	TotalPaid       float64 // 累计支付（正数）
+	TotalReceived   float64 // 累计收取（正数）
+	Net             float64 // 净额（收取 - 支付）
+	SettlementCount int     // 结算次数
+}
+
+// GetCumulativeFundingCost 估算从开仓时间至今累计的资金费
+// 按每次结算时的资金费率和标记价格，以当前持仓数量计算名义价值（假设期间未加减仓）
+func GetCumulativeFundingCost(t Trader, symbol, positionSide string, openTime time.Time) (*CumulativeFundingResult, error) {
+	side := strings.ToLower(positionSide)
+	if side != "long" && side != "short" {
+		return nil, fmt.Errorf("无效的持仓方向: %s", positionSide)
+	}
+
+	positions, err := t.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	quantity := 0.0
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			qty, _ := pos["positionAmt"].(float64)
+			quantity = absFloat(qty)
+			break
+		}
+	}
+
+	result := &CumulativeFundingResult{}
+	if quantity == 0 {
+		return result, nil
+	}
+
+	records, err := market.GetFundingRateHistory(symbol, openTime, 1000)
+	if err != nil {
+		return nil, fmt.Errorf("获取历史资金费率失败: %w", err)
+	}
+
+	for _, record := range records {
+		if record.FundingTime.Before(openTime) {
+			continue
+		}
+
+		// 费率为正时多头支付，空头收取
+		payment := record.FundingRate * quantity * record.MarkPrice
+		if side == "short" {
+			payment = -payment
+		}
+
+		if payment > 0 {
+			result.TotalPaid += payment
+		} else {
+			result.TotalReceived += -payment
+		}
+		result.SettlementCount++
+	}
+
+	result.Net = result.TotalReceived - result.TotalPaid
+	return result, nil
+}
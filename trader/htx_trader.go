@@ -0,0 +1,611 @@
+package trader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	htxHost          = "api.hbdm.com"
+	htxMarginAccount = "USDT"
+	// htxNoCancellableOrders 没有可撤销订单时返回的错误码
+	htxNoCancellableOrders = 1051
+)
+
+// HTXTrader HTX(火币) USDT本位永续合约交易器
+// HTX按张下单，每张合约对应 contract_size 个币，对外接口仍使用币的数量
+// 全仓和逐仓是两套独立接口（swap_cross_* / swap_*），由 SetMarginMode 切换
+type HTXTrader struct {
+	apiKey    string
+	secretKey string
+	rest      *restExchange
+
+	// 是否使用全仓接口
+	cross bool
+	// 各币种最近一次设置的杠杆，随订单提交 lever_rate
+	leverage map[string]int
+
+	// 缓存合约信息
+	contracts map[string]htxContract
+	mu        sync.RWMutex
+}
+
+// htxContract 合约规格
+type htxContract struct {
+	ContractSize float64 // 每张合约对应的币数量
+	PriceTick    float64
+}
+
+// htxResponse HTX 合约通用响应结构
+type htxResponse struct {
+	Status  string          `json:"status"`
+	ErrCode int             `json:"err_code"`
+	ErrMsg  string          `json:"err_msg"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// htxAPIError HTX 返回的业务错误
+type htxAPIError struct {
+	Code int
+	Msg  string
+}
+
+func (e *htxAPIError) Error() string {
+	return fmt.Sprintf("HTX API错误 %d: %s", e.Code, e.Msg)
+}
+
+func init() {
+	Register("htx", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用HTX合约交易", cfg.Name)
+		return NewHTXTrader(cfg.HTXAPIKey, cfg.HTXSecretKey), nil
+	})
+}
+
+// NewHTXTrader 创建HTX合约交易器
+func NewHTXTrader(apiKey, secretKey string) *HTXTrader {
+	t := &HTXTrader{
+		apiKey:    apiKey,
+		secretKey: secretKey,
+		cross:     true,
+		leverage:  make(map[string]int),
+		contracts: make(map[string]htxContract),
+	}
+	t.rest = newRESTExchange(restExchangeConfig{
+		Name:    "HTX",
+		BaseURL: "https://" + htxHost,
+		Signer:  t.sign,
+		Decoder: decodeHTX,
+		Symbols: restSymbolMapper{
+			toExchange:   toHTXContractCode,
+			fromExchange: fromHTXContractCode,
+		},
+	})
+	return t
+}
+
+// toHTXContractCode 将 BTCUSDT 转换为 HTX 合约代码 BTC-USDT
+func toHTXContractCode(symbol string) string {
+	return strings.TrimSuffix(strings.ToUpper(symbol), "USDT") + "-USDT"
+}
+
+// fromHTXContractCode 将 HTX 合约代码 BTC-USDT 转换回 BTCUSDT
+func fromHTXContractCode(contractCode string) string {
+	return strings.ReplaceAll(contractCode, "-", "")
+}
+
+// endpoint 根据保证金模式选择接口：全仓为 swap_cross_xxx，逐仓为 swap_xxx
+func (t *HTXTrader) endpoint(name string) string {
+	t.mu.RLock()
+	cross := t.cross
+	t.mu.RUnlock()
+
+	if cross {
+		return "/linear-swap-api/v1/swap_cross_" + name
+	}
+	return "/linear-swap-api/v1/swap_" + name
+}
+
+// sign 签名钩子
+// 签名串: POST\nhost\npath\n按key排序的认证参数，签名参数放在querystring，业务参数以JSON放在body
+func (t *HTXTrader) sign(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+	query.Set("AccessKeyId", t.apiKey)
+	query.Set("SignatureMethod", "HmacSHA256")
+	query.Set("SignatureVersion", "2")
+	query.Set("Timestamp", time.Now().UTC().Format("2006-01-02T15:04:05"))
+
+	// url.Values.Encode 已按key排序
+	payload := method + "\n" + htxHost + "\n" + path + "\n" + query.Encode()
+	mac := hmac.New(sha256.New, []byte(t.secretKey))
+	mac.Write([]byte(payload))
+	query.Set("Signature", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+
+	header.Set("Content-Type", "application/json")
+	return query.Encode(), nil
+}
+
+// decodeHTX 校验响应状态，返回完整响应（行情接口的数据不在data字段中）
+func decodeHTX(statusCode int, body []byte) (json.RawMessage, error) {
+	var result htxResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+	if result.Status != "ok" {
+		return nil, &htxAPIError{Code: result.ErrCode, Msg: result.ErrMsg}
+	}
+	return body, nil
+}
+
+// request 发送签名的POST请求，返回data字段
+func (t *HTXTrader) request(path string, params map[string]interface{}) (json.RawMessage, error) {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	body, err := t.rest.signedCall("POST", path, nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result htxResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	return result.Data, nil
+}
+
+// publicGet 请求无需签名的行情接口，返回原始响应
+func (t *HTXTrader) publicGet(path string, params url.Values) ([]byte, error) {
+	return t.rest.publicCall("GET", path, params)
+}
+
+// getContract 获取合约规格（合约面值、价格步进）
+func (t *HTXTrader) getContract(symbol string) (htxContract, error) {
+	contractCode := t.rest.exchangeSymbol(symbol)
+
+	t.mu.RLock()
+	if contract, ok := t.contracts[contractCode]; ok {
+		t.mu.RUnlock()
+		return contract, nil
+	}
+	t.mu.RUnlock()
+
+	body, err := t.publicGet("/linear-swap-api/v1/swap_contract_info", url.Values{"contract_code": {contractCode}})
+	if err != nil {
+		return htxContract{}, fmt.Errorf("获取合约信息失败: %w", err)
+	}
+
+	var result struct {
+		Data []struct {
+			ContractSize float64 `json:"contract_size"`
+			PriceTick    float64 `json:"price_tick"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return htxContract{}, err
+	}
+	if len(result.Data) == 0 || result.Data[0].ContractSize <= 0 {
+		return htxContract{}, fmt.Errorf("未找到合约 %s 的规格信息", contractCode)
+	}
+
+	contract := htxContract{
+		ContractSize: result.Data[0].ContractSize,
+		PriceTick:    result.Data[0].PriceTick,
+	}
+
+	t.mu.Lock()
+	t.contracts[contractCode] = contract
+	t.mu.Unlock()
+
+	return contract, nil
+}
+
+// toVolume 将币的数量换算为合约张数（向下取整）
+func (t *HTXTrader) toVolume(symbol string, quantity float64) (int64, error) {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	volume := int64(math.Floor(quantity/contract.ContractSize + 1e-9))
+	if volume < 1 {
+		return 0, fmt.Errorf("%s 数量 %.8f 不足一张合约（每张 %v）", symbol, quantity, contract.ContractSize)
+	}
+	return volume, nil
+}
+
+// GetBalance 获取USDT保证金账户余额
+func (t *HTXTrader) GetBalance() (map[string]interface{}, error) {
+	data, err := t.request(t.endpoint("account_info"), map[string]interface{}{
+		"margin_account": htxMarginAccount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	var accounts []struct {
+		MarginAccount     string  `json:"margin_account"`
+		MarginBalance     float64 `json:"margin_balance"`
+		ProfitUnreal      float64 `json:"profit_unreal"`
+		WithdrawAvailable float64 `json:"withdraw_available"`
+	}
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		return nil, err
+	}
+
+	totalBalance := 0.0
+	availableBalance := 0.0
+	unrealizedPnL := 0.0
+	for _, acc := range accounts {
+		totalBalance += acc.MarginBalance - acc.ProfitUnreal
+		availableBalance += acc.WithdrawAvailable
+		unrealizedPnL += acc.ProfitUnreal
+	}
+
+	// 返回与Binance相同的字段名，钱包余额不含未实现盈亏
+	return map[string]interface{}{
+		"totalWalletBalance":    totalBalance,
+		"availableBalance":      availableBalance,
+		"totalUnrealizedProfit": unrealizedPnL,
+	}, nil
+}
+
+// GetPositions 获取持仓信息，数量由张数换算为币的数量
+func (t *HTXTrader) GetPositions() ([]map[string]interface{}, error) {
+	data, err := t.request(t.endpoint("position_info"), map[string]interface{}{
+		"margin_account": htxMarginAccount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	var positions []struct {
+		ContractCode string  `json:"contract_code"`
+		Volume       float64 `json:"volume"`
+		CostOpen     float64 `json:"cost_open"`
+		LastPrice    float64 `json:"last_price"`
+		ProfitUnreal float64 `json:"profit_unreal"`
+		Direction    string  `json:"direction"` // buy=多 sell=空
+		LeverRate    float64 `json:"lever_rate"`
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, pos := range positions {
+		if pos.Volume == 0 {
+			continue // 跳过空仓位
+		}
+
+		symbol := t.rest.localSymbol(pos.ContractCode)
+		contract, err := t.getContract(symbol)
+		if err != nil {
+			log.Printf("  ⚠ 获取 %s 合约信息失败: %v", pos.ContractCode, err)
+			continue
+		}
+
+		side := "long"
+		if pos.Direction == "sell" {
+			side = "short"
+		}
+
+		// 返回与Binance相同的字段名（持仓接口不返回强平价）
+		result = append(result, map[string]interface{}{
+			"symbol":           symbol,
+			"side":             side,
+			"positionAmt":      pos.Volume * contract.ContractSize,
+			"entryPrice":       pos.CostOpen,
+			"markPrice":        pos.LastPrice,
+			"unRealizedProfit": pos.ProfitUnreal,
+			"leverage":         pos.LeverRate,
+			"liquidationPrice": 0.0,
+		})
+	}
+
+	return result, nil
+}
+
+// placeOrder 下市价单（optimal_20：最优20档成交）
+// direction: buy/sell，offset: open/close
+func (t *HTXTrader) placeOrder(symbol, direction, offset string, quantity float64) (map[string]interface{}, error) {
+	volume, err := t.toVolume(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"contract_code":    t.rest.exchangeSymbol(symbol),
+		"volume":           volume,
+		"direction":        direction,
+		"offset":           offset,
+		"order_price_type": "optimal_20",
+	}
+	t.mu.RLock()
+	if leverage := t.leverage[symbol]; leverage > 0 {
+		params["lever_rate"] = leverage
+	}
+	t.mu.RUnlock()
+
+	data, err := t.request(t.endpoint("order"), params)
+	if err != nil {
+		return nil, err
+	}
+
+	var order struct {
+		OrderID    int64  `json:"order_id"`
+		OrderIDStr string `json:"order_id_str"`
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+
+	log.Printf("  订单ID: %s (%d张)", order.OrderIDStr, volume)
+	return map[string]interface{}{
+		"orderId": order.OrderID,
+		"symbol":  symbol,
+		"volume":  volume,
+	}, nil
+}
+
+// OpenLong 开多单
+func (t *HTXTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "buy", "open", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开多仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// OpenShort 开空单
+func (t *HTXTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "sell", "open", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("开空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开空仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// CloseLong 平多单（quantity为0时平掉全部多仓）
+func (t *HTXTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "long")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到多仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "sell", "close", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平多仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// CloseShort 平空单（quantity为0时平掉全部空仓）
+func (t *HTXTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "short")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到空仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "buy", "close", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平空仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// positionQuantity 获取指定方向的持仓数量
+func (t *HTXTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return pos["positionAmt"].(float64), nil
+		}
+	}
+
+	sideName := "多仓"
+	if side == "short" {
+		sideName = "空仓"
+	}
+	return 0, fmt.Errorf("没有找到 %s 的%s", symbol, sideName)
+}
+
+// SetMarginMode 设置仓位模式
+// HTX的全仓和逐仓是两套接口，这里只切换之后使用的接口
+func (t *HTXTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	t.mu.Lock()
+	t.cross = isCrossMargin
+	t.mu.Unlock()
+
+	modeName := "全仓"
+	if !isCrossMargin {
+		modeName = "逐仓"
+	}
+	log.Printf("  ✓ %s 仓位模式已设置为 %s", symbol, modeName)
+	return nil
+}
+
+// SetLeverage 设置杠杆倍数（swap_cross_switch_lever_rate / swap_switch_lever_rate）
+// 杠杆同时会随订单以 lever_rate 提交
+func (t *HTXTrader) SetLeverage(symbol string, leverage int) error {
+	t.mu.Lock()
+	t.leverage[symbol] = leverage
+	t.mu.Unlock()
+
+	_, err := t.request(t.endpoint("switch_lever_rate"), map[string]interface{}{
+		"contract_code": t.rest.exchangeSymbol(symbol),
+		"lever_rate":    leverage,
+	})
+	if err != nil {
+		return fmt.Errorf("设置杠杆失败: %w", err)
+	}
+
+	log.Printf("  ✓ %s 杠杆已切换为 %dx", symbol, leverage)
+	return nil
+}
+
+// GetMarketPrice 获取最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *HTXTrader) GetMarketPrice(symbol string) (float64, error) {
+	body, err := t.publicGet("/linear-swap-ex/market/detail/merged", url.Values{
+		"contract_code": {t.rest.exchangeSymbol(symbol)},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("获取价格失败: %w", err)
+	}
+
+	var result struct {
+		Tick struct {
+			Close float64   `json:"close"`
+			Bid   []float64 `json:"bid"` // [价格, 数量]
+			Ask   []float64 `json:"ask"`
+		} `json:"tick"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, err
+	}
+	if result.Tick.Close <= 0 {
+		return 0, fmt.Errorf("未找到 %s 的行情", symbol)
+	}
+
+	var bid, ask float64
+	if len(result.Tick.Bid) > 0 && len(result.Tick.Ask) > 0 {
+		bid, ask = result.Tick.Bid[0], result.Tick.Ask[0]
+	}
+	return pickMarketPrice(result.Tick.Close, bid, ask), nil
+}
+
+// placeTPSLOrder 下止盈/止损单（swap_cross_tpsl_order / swap_tpsl_order）
+// prefix: sl=止损, tp=止盈，触发后以最优5档成交
+func (t *HTXTrader) placeTPSLOrder(symbol, positionSide, prefix string, quantity, triggerPrice float64) error {
+	// 止盈止损单的方向为平仓方向
+	direction := "sell"
+	if positionSide == "SHORT" {
+		direction = "buy"
+	}
+
+	volume, err := t.toVolume(symbol, quantity)
+	if err != nil {
+		return err
+	}
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.request(t.endpoint("tpsl_order"), map[string]interface{}{
+		"contract_code":              t.rest.exchangeSymbol(symbol),
+		"direction":                  direction,
+		"volume":                     volume,
+		prefix + "_trigger_price":    roundToTickSize(triggerPrice, contract.PriceTick),
+		prefix + "_order_price_type": "optimal_5",
+	})
+	return err
+}
+
+// SetStopLoss 设置止损
+func (t *HTXTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeTPSLOrder(symbol, positionSide, "sl", quantity, stopPrice); err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	log.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈
+func (t *HTXTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeTPSLOrder(symbol, positionSide, "tp", quantity, takeProfitPrice); err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	log.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 取消该合约的所有普通挂单和止盈止损单（没有可撤订单不视为错误）
+func (t *HTXTrader) CancelAllOrders(symbol string) error {
+	params := map[string]interface{}{
+		"contract_code": t.rest.exchangeSymbol(symbol),
+	}
+	for _, name := range []string{"cancelall", "tpsl_cancelall"} {
+		if _, err := t.request(t.endpoint(name), params); err != nil {
+			if apiErr, ok := err.(*htxAPIError); ok && apiErr.Code == htxNoCancellableOrders {
+				continue
+			}
+			return fmt.Errorf("取消挂单失败: %w", err)
+		}
+	}
+
+	log.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 将数量向下取整到整数张对应的币数量（实现Trader接口）
+func (t *HTXTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return "", err
+	}
+
+	volume := math.Floor(quantity/contract.ContractSize + 1e-9)
+	return strconv.FormatFloat(volume*contract.ContractSize, 'f', stepDecimals(contract.ContractSize), 64), nil
+}
@@ -22,6 +22,17 @@ type HyperliquidTrader struct {
 	isCrossMargin bool              // 是否为全仓模式
 }
 
+func init() {
+	Register("hyperliquid", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用Hyperliquid交易", cfg.Name)
+		t, err := NewHyperliquidTrader(cfg.HyperliquidPrivateKey, cfg.HyperliquidWalletAddr, cfg.HyperliquidTestnet)
+		if err != nil {
+			return nil, fmt.Errorf("初始化Hyperliquid交易器失败: %w", err)
+		}
+		return t, nil
+	})
+}
+
 // NewHyperliquidTrader 创建Hyperliquid交易器
 func NewHyperliquidTrader(privateKeyHex string, walletAddr string, testnet bool) (*HyperliquidTrader, error) {
 	// 去掉私钥的 0x 前缀（如果有，不区分大小写）
@@ -512,6 +523,7 @@ func (t *HyperliquidTrader) CancelAllOrders(symbol string) error {
 }
 
 // GetMarketPrice 获取市场价格
+// Hyperliquid 只提供买一卖一中间价（allMids），不论是否开启 market.SetUseMidPrice 都返回中间价
 func (t *HyperliquidTrader) GetMarketPrice(symbol string) (float64, error) {
 	coin := convertSymbolToHyperliquid(symbol)
 
@@ -0,0 +1,673 @@
+package trader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	krakenFuturesMainnetURL = "https://futures.kraken.com"
+	krakenFuturesDemoURL    = "https://demo-futures.kraken.com"
+	krakenFuturesAPIPrefix  = "/derivatives"
+)
+
+// KrakenFuturesTrader Kraken Futures 多抵押品线性永续合约（PF_*）交易器
+type KrakenFuturesTrader struct {
+	apiKey string
+	secret []byte // base64解码后的API私钥
+	rest   *restExchange
+
+	// Kraken的逐仓通过设置杠杆偏好开启，全仓则清除杠杆偏好
+	isCrossMargin bool
+
+	// 缓存合约精度信息
+	symbolPrecision map[string]SymbolPrecision
+	mu              sync.RWMutex
+}
+
+func init() {
+	Register("kraken", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用Kraken Futures交易", cfg.Name)
+		t, err := NewKrakenFuturesTrader(cfg.KrakenAPIKey, cfg.KrakenSecretKey, cfg.KrakenTestnet)
+		if err != nil {
+			return nil, fmt.Errorf("初始化Kraken交易器失败: %w", err)
+		}
+		return t, nil
+	})
+}
+
+// NewKrakenFuturesTrader 创建Kraken Futures交易器
+// testnet为true时连接 demo-futures.kraken.com
+func NewKrakenFuturesTrader(apiKey, secretKey string, testnet bool) (*KrakenFuturesTrader, error) {
+	secret, err := base64.StdEncoding.DecodeString(secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("解析API私钥失败: %w", err)
+	}
+
+	baseURL := krakenFuturesMainnetURL
+	if testnet {
+		baseURL = krakenFuturesDemoURL
+	}
+
+	t := &KrakenFuturesTrader{
+		apiKey:          apiKey,
+		secret:          secret,
+		isCrossMargin:   true,
+		symbolPrecision: make(map[string]SymbolPrecision),
+	}
+	t.rest = newRESTExchange(restExchangeConfig{
+		Name:    "Kraken",
+		BaseURL: baseURL + krakenFuturesAPIPrefix,
+		Signer:  t.sign,
+		Decoder: decodeKrakenResponse,
+		Symbols: restSymbolMapper{
+			toExchange:   toKrakenSymbol,
+			fromExchange: fromKrakenSymbol,
+		},
+	})
+	return t, nil
+}
+
+// toKrakenSymbol 将 BTCUSDT 转换为 Kraken 线性永续合约代码 PF_XBTUSD
+func toKrakenSymbol(symbol string) string {
+	base := strings.TrimSuffix(strings.ToUpper(symbol), "USDT")
+	if base == "BTC" {
+		base = "XBT"
+	}
+	return "PF_" + base + "USD"
+}
+
+// fromKrakenSymbol 将 Kraken 合约代码 PF_XBTUSD 转换回 BTCUSDT
+func fromKrakenSymbol(symbol string) string {
+	base := strings.TrimSuffix(strings.TrimPrefix(strings.ToUpper(symbol), "PF_"), "USD")
+	if base == "XBT" {
+		base = "BTC"
+	}
+	return base + "USDT"
+}
+
+// sign 签名钩子，Authent: base64(HMAC_SHA512(secret, SHA256(postData + nonce + endpointPath)))
+// GET/DELETE 的postData为querystring，其他方法为表单body；endpointPath 不包含 /derivatives 前缀
+func (t *KrakenFuturesTrader) sign(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+	rawQuery := query.Encode()
+	postData := string(body)
+	if method == "GET" || method == "DELETE" {
+		postData = rawQuery
+	}
+
+	nonce := strconv.FormatInt(time.Now().UnixNano(), 10)
+	digest := sha256.Sum256([]byte(postData + nonce + path))
+	mac := hmac.New(sha512.New, t.secret)
+	mac.Write(digest[:])
+
+	header.Set("APIKey", t.apiKey)
+	header.Set("Nonce", nonce)
+	header.Set("Authent", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	if body != nil {
+		header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return rawQuery, nil
+}
+
+// decodeKrakenResponse 校验响应，result非success时返回错误，成功时返回完整响应
+func decodeKrakenResponse(statusCode int, body []byte) (json.RawMessage, error) {
+	var result struct {
+		Result string      `json:"result"`
+		Error  interface{} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+	if result.Result != "success" {
+		return nil, fmt.Errorf("Kraken API错误: %v", result.Error)
+	}
+	return body, nil
+}
+
+// request 发送签名请求，GET/DELETE参数放在querystring，POST/PUT参数以表单放在body
+func (t *KrakenFuturesTrader) request(method, endpoint string, params url.Values) (map[string]interface{}, error) {
+	method = strings.ToUpper(method)
+
+	var data json.RawMessage
+	var err error
+	if method == "GET" || method == "DELETE" {
+		data, err = t.rest.signedCall(method, endpoint, params, nil)
+	} else {
+		data, err = t.rest.signedCall(method, endpoint, nil, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeKrakenMap(data)
+}
+
+// publicGet 请求无需签名的行情接口
+func (t *KrakenFuturesTrader) publicGet(endpoint string) (map[string]interface{}, error) {
+	data, err := t.rest.publicCall("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeKrakenMap(data)
+}
+
+// decodeKrakenMap 将响应解析为map，便于按字段取值
+func decodeKrakenMap(data json.RawMessage) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// decodeKraken 将响应中的字段解析到结构体
+func decodeKraken(result map[string]interface{}, key string, v interface{}) error {
+	bs, err := json.Marshal(result[key])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, v)
+}
+
+// getPrecision 获取合约精度信息（一次缓存所有合约）
+func (t *KrakenFuturesTrader) getPrecision(symbol string) (SymbolPrecision, error) {
+	krakenSymbol := t.rest.exchangeSymbol(symbol)
+
+	t.mu.RLock()
+	if prec, ok := t.symbolPrecision[krakenSymbol]; ok {
+		t.mu.RUnlock()
+		return prec, nil
+	}
+	t.mu.RUnlock()
+
+	result, err := t.publicGet("/api/v3/instruments")
+	if err != nil {
+		return SymbolPrecision{}, fmt.Errorf("获取合约信息失败: %w", err)
+	}
+
+	var instruments []struct {
+		Symbol                 string  `json:"symbol"`
+		TickSize               float64 `json:"tickSize"`
+		ContractValuePrecision int     `json:"contractValuePrecision"`
+	}
+	if err := decodeKraken(result, "instruments", &instruments); err != nil {
+		return SymbolPrecision{}, err
+	}
+
+	t.mu.Lock()
+	for _, inst := range instruments {
+		t.symbolPrecision[strings.ToUpper(inst.Symbol)] = SymbolPrecision{
+			PricePrecision:    stepDecimals(inst.TickSize),
+			QuantityPrecision: inst.ContractValuePrecision,
+			TickSize:          inst.TickSize,
+			StepSize:          math.Pow10(-inst.ContractValuePrecision),
+		}
+	}
+	prec, ok := t.symbolPrecision[krakenSymbol]
+	t.mu.Unlock()
+
+	if !ok {
+		return SymbolPrecision{}, fmt.Errorf("未找到合约 %s 的精度信息", krakenSymbol)
+	}
+	return prec, nil
+}
+
+// krakenTicker 合约行情
+type krakenTicker struct {
+	Symbol    string  `json:"symbol"`
+	MarkPrice float64 `json:"markPrice"`
+	Last      float64 `json:"last"`
+	Bid       float64 `json:"bid"`
+	Ask       float64 `json:"ask"`
+}
+
+// getTickers 获取所有合约的行情（合约代码 -> 行情）
+func (t *KrakenFuturesTrader) getTickers() (map[string]krakenTicker, error) {
+	result, err := t.publicGet("/api/v3/tickers")
+	if err != nil {
+		return nil, fmt.Errorf("获取行情失败: %w", err)
+	}
+
+	var tickers []krakenTicker
+	if err := decodeKraken(result, "tickers", &tickers); err != nil {
+		return nil, err
+	}
+
+	bySymbol := make(map[string]krakenTicker, len(tickers))
+	for _, ticker := range tickers {
+		bySymbol[strings.ToUpper(ticker.Symbol)] = ticker
+	}
+	return bySymbol, nil
+}
+
+// krakenFlexAccount 多抵押品（flex）账户的保证金信息
+type krakenFlexAccount struct {
+	BalanceValue      float64 `json:"balanceValue"`
+	AvailableMargin   float64 `json:"availableMargin"`
+	TotalUnrealized   float64 `json:"totalUnrealized"`
+	MarginEquity      float64 `json:"marginEquity"`
+	MaintenanceMargin float64 `json:"maintenanceMargin"`
+}
+
+// getFlexAccount 获取多抵押品（flex）账户
+func (t *KrakenFuturesTrader) getFlexAccount() (krakenFlexAccount, error) {
+	result, err := t.request("GET", "/api/v3/accounts", nil)
+	if err != nil {
+		return krakenFlexAccount{}, fmt.Errorf("获取账户信息失败: %w", err)
+	}
+
+	var accounts struct {
+		Flex krakenFlexAccount `json:"flex"`
+	}
+	if err := decodeKraken(result, "accounts", &accounts); err != nil {
+		return krakenFlexAccount{}, err
+	}
+	return accounts.Flex, nil
+}
+
+// GetBalance 获取多抵押品（flex）账户余额
+func (t *KrakenFuturesTrader) GetBalance() (map[string]interface{}, error) {
+	account, err := t.getFlexAccount()
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	// 返回与Binance相同的字段名，确保AutoTrader能正确解析
+	return map[string]interface{}{
+		"totalWalletBalance":    account.BalanceValue,
+		"availableBalance":      account.AvailableMargin,
+		"totalUnrealizedProfit": account.TotalUnrealized,
+	}, nil
+}
+
+// GetPositions 获取线性永续持仓
+// openpositions 不返回标记价格、盈亏和强平价，标记价格取自行情接口，强平价按账户保证金估算
+func (t *KrakenFuturesTrader) GetPositions() ([]map[string]interface{}, error) {
+	result, err := t.request("GET", "/api/v3/openpositions", nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	var positions []struct {
+		Symbol           string  `json:"symbol"`
+		Side             string  `json:"side"` // long/short
+		Price            float64 `json:"price"`
+		Size             float64 `json:"size"`
+		MaxFixedLeverage float64 `json:"maxFixedLeverage"`
+	}
+	if err := decodeKraken(result, "openPositions", &positions); err != nil {
+		return nil, err
+	}
+
+	res := []map[string]interface{}{}
+	if len(positions) == 0 {
+		return res, nil
+	}
+
+	tickers, err := t.getTickers()
+	if err != nil {
+		return nil, err
+	}
+	account, err := t.getFlexAccount()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	// 全仓持仓没有固定杠杆，使用账户有效杠杆（线性永续总名义价值÷保证金权益）
+	totalNotional := 0.0
+	for _, pos := range positions {
+		krakenSymbol := strings.ToUpper(pos.Symbol)
+		if strings.HasPrefix(krakenSymbol, "PF_") {
+			totalNotional += pos.Size * tickers[krakenSymbol].MarkPrice
+		}
+	}
+	crossLeverage := 1.0
+	if account.MarginEquity > 0 {
+		crossLeverage = math.Max(1, math.Round(totalNotional/account.MarginEquity))
+	}
+
+	for _, pos := range positions {
+		krakenSymbol := strings.ToUpper(pos.Symbol)
+		if !strings.HasPrefix(krakenSymbol, "PF_") || pos.Size == 0 {
+			continue // 只处理线性永续合约
+		}
+
+		markPrice := tickers[krakenSymbol].MarkPrice
+		unRealizedProfit := (markPrice - pos.Price) * pos.Size
+		if pos.Side == "short" {
+			unRealizedProfit = -unRealizedProfit
+		}
+
+		leverage := pos.MaxFixedLeverage
+		if leverage <= 0 {
+			leverage = crossLeverage
+		}
+
+		// 保证金权益跌到维持保证金时强平，假设其他持仓价格不变，按本持仓可承受的价格变动估算强平价
+		buffer := (account.MarginEquity - account.MaintenanceMargin) / pos.Size
+		liquidationPrice := math.Max(0, markPrice-buffer)
+		if pos.Side == "short" {
+			liquidationPrice = markPrice + buffer
+		}
+
+		res = append(res, map[string]interface{}{
+			"symbol":           t.rest.localSymbol(krakenSymbol),
+			"side":             pos.Side,
+			"positionAmt":      pos.Size,
+			"entryPrice":       pos.Price,
+			"markPrice":        markPrice,
+			"unRealizedProfit": unRealizedProfit,
+			"leverage":         leverage,
+			"liquidationPrice": liquidationPrice,
+		})
+	}
+
+	return res, nil
+}
+
+// sendOrder 下单，sendStatus非placed时返回错误
+func (t *KrakenFuturesTrader) sendOrder(params url.Values) (map[string]interface{}, error) {
+	result, err := t.request("POST", "/api/v3/sendorder", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var sendStatus struct {
+		OrderID string `json:"order_id"`
+		Status  string `json:"status"`
+	}
+	if err := decodeKraken(result, "sendStatus", &sendStatus); err != nil {
+		return nil, err
+	}
+	if sendStatus.Status != "placed" {
+		return nil, fmt.Errorf("下单被拒绝: %s", sendStatus.Status)
+	}
+
+	log.Printf("  订单ID: %s", sendStatus.OrderID)
+	return map[string]interface{}{
+		"orderId": sendStatus.OrderID,
+		"symbol":  t.rest.localSymbol(params.Get("symbol")),
+		"status":  sendStatus.Status,
+	}, nil
+}
+
+// placeMarketOrder 下市价单
+func (t *KrakenFuturesTrader) placeMarketOrder(symbol, side string, quantity float64, reduceOnly bool) (map[string]interface{}, error) {
+	sizeStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{
+		"orderType": {"mkt"},
+		"symbol":    {t.rest.exchangeSymbol(symbol)},
+		"side":      {side},
+		"size":      {sizeStr},
+	}
+	if reduceOnly {
+		params.Set("reduceOnly", "true")
+	}
+	return t.sendOrder(params)
+}
+
+// OpenLong 开多单
+func (t *KrakenFuturesTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeMarketOrder(symbol, "buy", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开多仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// OpenShort 开空单
+func (t *KrakenFuturesTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeMarketOrder(symbol, "sell", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开空仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// CloseLong 平多单（quantity为0时平掉全部多仓）
+func (t *KrakenFuturesTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "long")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到多仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeMarketOrder(symbol, "sell", quantity, true)
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平多仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// CloseShort 平空单（quantity为0时平掉全部空仓）
+func (t *KrakenFuturesTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "short")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到空仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeMarketOrder(symbol, "buy", quantity, true)
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平空仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// positionQuantity 获取指定方向的持仓数量
+func (t *KrakenFuturesTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return pos["positionAmt"].(float64), nil
+		}
+	}
+
+	sideName := "多仓"
+	if side == "short" {
+		sideName = "空仓"
+	}
+	return 0, fmt.Errorf("没有找到 %s 的%s", symbol, sideName)
+}
+
+// SetMarginMode 设置仓位模式
+// Kraken没有单独的保证金模式接口：清除杠杆偏好即为全仓，逐仓在 SetLeverage 时通过设置杠杆偏好开启
+func (t *KrakenFuturesTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	t.mu.Lock()
+	t.isCrossMargin = isCrossMargin
+	t.mu.Unlock()
+
+	if !isCrossMargin {
+		log.Printf("  ✓ %s 将在设置杠杆时切换为逐仓", symbol)
+		return nil
+	}
+
+	_, err := t.request("PUT", "/api/v3/leveragepreferences", url.Values{
+		"symbol": {t.rest.exchangeSymbol(symbol)},
+	})
+	if err != nil {
+		log.Printf("  ⚠️ 设置仓位模式失败: %v", err)
+		// 不返回错误，让交易继续
+		return nil
+	}
+
+	log.Printf("  ✓ %s 仓位模式已设置为全仓", symbol)
+	return nil
+}
+
+// SetLeverage 设置杠杆倍数（仅逐仓生效，全仓按账户保证金计算）
+func (t *KrakenFuturesTrader) SetLeverage(symbol string, leverage int) error {
+	t.mu.RLock()
+	cross := t.isCrossMargin
+	t.mu.RUnlock()
+
+	if cross {
+		log.Printf("  ✓ %s 全仓模式无需设置杠杆", symbol)
+		return nil
+	}
+
+	_, err := t.request("PUT", "/api/v3/leveragepreferences", url.Values{
+		"symbol":      {t.rest.exchangeSymbol(symbol)},
+		"maxLeverage": {strconv.Itoa(leverage)},
+	})
+	if err != nil {
+		return fmt.Errorf("设置杠杆失败: %w", err)
+	}
+
+	log.Printf("  ✓ %s 杠杆已切换为 %dx（逐仓）", symbol, leverage)
+	return nil
+}
+
+// GetMarketPrice 获取最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *KrakenFuturesTrader) GetMarketPrice(symbol string) (float64, error) {
+	result, err := t.publicGet("/api/v3/tickers/" + t.rest.exchangeSymbol(symbol))
+	if err != nil {
+		return 0, fmt.Errorf("获取价格失败: %w", err)
+	}
+
+	var ticker krakenTicker
+	if err := decodeKraken(result, "ticker", &ticker); err != nil {
+		return 0, err
+	}
+	if ticker.Last <= 0 {
+		return 0, fmt.Errorf("未找到 %s 的行情", symbol)
+	}
+
+	return pickMarketPrice(ticker.Last, ticker.Bid, ticker.Ask), nil
+}
+
+// placeTriggerOrder 下标记价格触发的reduce-only条件单
+// orderType: stp(止损) / take_profit(止盈)
+func (t *KrakenFuturesTrader) placeTriggerOrder(symbol, positionSide, orderType string, quantity, triggerPrice float64) error {
+	side := "sell"
+	if positionSide == "SHORT" {
+		side = "buy"
+	}
+
+	sizeStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return err
+	}
+	prec, err := t.getPrecision(symbol)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.sendOrder(url.Values{
+		"orderType":     {orderType},
+		"symbol":        {t.rest.exchangeSymbol(symbol)},
+		"side":          {side},
+		"size":          {sizeStr},
+		"stopPrice":     {strconv.FormatFloat(roundToTickSize(triggerPrice, prec.TickSize), 'f', prec.PricePrecision, 64)},
+		"triggerSignal": {"mark"},
+		"reduceOnly":    {"true"},
+	})
+	return err
+}
+
+// SetStopLoss 设置止损
+func (t *KrakenFuturesTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, "stp", quantity, stopPrice); err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	log.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈
+func (t *KrakenFuturesTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, "take_profit", quantity, takeProfitPrice); err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	log.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 取消该合约的所有挂单（包括条件单）
+func (t *KrakenFuturesTrader) CancelAllOrders(symbol string) error {
+	_, err := t.request("POST", "/api/v3/cancelallorders", url.Values{
+		"symbol": {t.rest.exchangeSymbol(symbol)},
+	})
+	if err != nil {
+		return fmt.Errorf("取消挂单失败: %w", err)
+	}
+
+	log.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 按合约数量精度向下取整（实现Trader接口）
+func (t *KrakenFuturesTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	prec, err := t.getPrecision(symbol)
+	if err != nil {
+		return "", err
+	}
+
+	qty := math.Floor(quantity/prec.StepSize+1e-9) * prec.StepSize
+	return strconv.FormatFloat(qty, 'f', prec.QuantityPrecision, 64), nil
+}
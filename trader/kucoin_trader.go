@@ -0,0 +1,638 @@
+package trader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	kucoinFuturesBaseURL = "https://api-futures.kucoin.com"
+	kucoinSuccessCode    = "200000"
+	kucoinSettleCurrency = "USDT"
+)
+
+// KuCoinTrader KuCoin USDT本位永续合约交易器
+// KuCoin按张下单，每张合约对应 multiplier 个币，对外接口仍使用币的数量
+type KuCoinTrader struct {
+	apiKey     string
+	secretKey  string
+	passphrase string
+	rest       *restExchange
+
+	// 下单时使用的保证金模式，由 SetMarginMode 更新
+	marginMode string
+	// 各币种最近一次设置的杠杆，逐仓下单时需要随订单提交
+	leverage map[string]int
+
+	// 缓存合约信息
+	contracts map[string]kucoinContract
+	mu        sync.RWMutex
+}
+
+// kucoinContract 合约规格
+type kucoinContract struct {
+	Multiplier float64 // 每张合约对应的币数量
+	LotSize    float64 // 最小下单张数步进
+	TickSize   float64
+}
+
+// kucoinResponse KuCoin 通用响应结构
+type kucoinResponse struct {
+	Code string          `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+func init() {
+	Register("kucoin", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用KuCoin合约交易", cfg.Name)
+		return NewKuCoinTrader(cfg.KuCoinAPIKey, cfg.KuCoinSecretKey, cfg.KuCoinPassphrase), nil
+	})
+}
+
+// NewKuCoinTrader 创建KuCoin合约交易器
+func NewKuCoinTrader(apiKey, secretKey, passphrase string) *KuCoinTrader {
+	t := &KuCoinTrader{
+		apiKey:     apiKey,
+		secretKey:  secretKey,
+		passphrase: passphrase,
+		marginMode: "CROSS",
+		leverage:   make(map[string]int),
+		contracts:  make(map[string]kucoinContract),
+	}
+	t.rest = newRESTExchange(restExchangeConfig{
+		Name:    "KuCoin",
+		BaseURL: kucoinFuturesBaseURL,
+		Signer:  t.sign,
+		Decoder: decodeKuCoin,
+		Symbols: restSymbolMapper{
+			toExchange:   toKuCoinSymbol,
+			fromExchange: fromKuCoinSymbol,
+		},
+	})
+	return t
+}
+
+// toKuCoinSymbol 将 BTCUSDT 转换为 KuCoin 合约代码 XBTUSDTM
+func toKuCoinSymbol(symbol string) string {
+	base := strings.TrimSuffix(strings.ToUpper(symbol), "USDT")
+	if base == "BTC" {
+		base = "XBT"
+	}
+	return base + "USDTM"
+}
+
+// fromKuCoinSymbol 将 KuCoin 合约代码 XBTUSDTM 转换回 BTCUSDT
+func fromKuCoinSymbol(symbol string) string {
+	base := strings.TrimSuffix(symbol, "USDTM")
+	if base == "XBT" {
+		base = "BTC"
+	}
+	return base + "USDT"
+}
+
+// hmacBase64 计算 base64(HMAC_SHA256(secret, message))
+func (t *KuCoinTrader) hmacBase64(message string) string {
+	mac := hmac.New(sha256.New, []byte(t.secretKey))
+	mac.Write([]byte(message))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// sign 签名钩子（API Key V2：passphrase同样需要用secret签名）
+// 签名: base64(HMAC_SHA256(timestamp + method + requestPath(含querystring) + body))
+func (t *KuCoinTrader) sign(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+	rawQuery := query.Encode()
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	header.Set("KC-API-KEY", t.apiKey)
+	header.Set("KC-API-SIGN", t.hmacBase64(timestamp+method+restRequestPath(path, rawQuery)+string(body)))
+	header.Set("KC-API-TIMESTAMP", timestamp)
+	header.Set("KC-API-PASSPHRASE", t.hmacBase64(t.passphrase))
+	header.Set("KC-API-KEY-VERSION", "2")
+	header.Set("Content-Type", "application/json")
+	return rawQuery, nil
+}
+
+// decodeKuCoin 解析响应包装，code非200000时返回错误
+func decodeKuCoin(statusCode int, body []byte) (json.RawMessage, error) {
+	var result kucoinResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+	if result.Code != kucoinSuccessCode {
+		return nil, fmt.Errorf("KuCoin API错误 %s: %s", result.Code, result.Msg)
+	}
+	return result.Data, nil
+}
+
+// request 发送签名请求，GET/DELETE参数放在querystring，POST参数以JSON放在body
+func (t *KuCoinTrader) request(method, endpoint string, params map[string]interface{}) (json.RawMessage, error) {
+	method = strings.ToUpper(method)
+	if method != "GET" && method != "DELETE" && method != "POST" {
+		return nil, fmt.Errorf("不支持的HTTP方法: %s", method)
+	}
+	query, payload := restParams(method, params)
+	return t.rest.signedCall(method, endpoint, query, payload)
+}
+
+// publicGet 请求无需签名的行情接口
+func (t *KuCoinTrader) publicGet(endpoint string, params url.Values) (json.RawMessage, error) {
+	return t.rest.publicCall("GET", endpoint, params)
+}
+
+// getContract 获取合约规格（合约乘数、张数步进、价格步进）
+func (t *KuCoinTrader) getContract(symbol string) (kucoinContract, error) {
+	kcSymbol := t.rest.exchangeSymbol(symbol)
+
+	t.mu.RLock()
+	if contract, ok := t.contracts[kcSymbol]; ok {
+		t.mu.RUnlock()
+		return contract, nil
+	}
+	t.mu.RUnlock()
+
+	data, err := t.publicGet("/api/v1/contracts/"+kcSymbol, nil)
+	if err != nil {
+		return kucoinContract{}, fmt.Errorf("获取合约信息失败: %w", err)
+	}
+
+	var info struct {
+		Multiplier float64 `json:"multiplier"`
+		LotSize    float64 `json:"lotSize"`
+		TickSize   float64 `json:"tickSize"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return kucoinContract{}, err
+	}
+	if info.Multiplier <= 0 {
+		return kucoinContract{}, fmt.Errorf("未找到合约 %s 的规格信息", kcSymbol)
+	}
+
+	contract := kucoinContract{
+		Multiplier: info.Multiplier,
+		LotSize:    info.LotSize,
+		TickSize:   info.TickSize,
+	}
+	if contract.LotSize <= 0 {
+		contract.LotSize = 1
+	}
+
+	t.mu.Lock()
+	t.contracts[kcSymbol] = contract
+	t.mu.Unlock()
+
+	return contract, nil
+}
+
+// toLots 将币的数量换算为合约张数（向下取整到lotSize）
+func (t *KuCoinTrader) toLots(symbol string, quantity float64) (int64, error) {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	lots := math.Floor(quantity/contract.Multiplier/contract.LotSize+1e-9) * contract.LotSize
+	if lots < 1 {
+		return 0, fmt.Errorf("%s 数量 %.8f 不足一张合约（每张 %v）", symbol, quantity, contract.Multiplier)
+	}
+	return int64(lots), nil
+}
+
+// formatPrice 格式化价格到tick size
+func (t *KuCoinTrader) formatPrice(symbol string, price float64) (string, error) {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(roundToTickSize(price, contract.TickSize), 'f', stepDecimals(contract.TickSize), 64), nil
+}
+
+// clientOid 生成客户端订单ID
+func (t *KuCoinTrader) clientOid() string {
+	return fmt.Sprintf("nofx%d", time.Now().UnixNano())
+}
+
+// GetBalance 获取USDT合约账户余额
+func (t *KuCoinTrader) GetBalance() (map[string]interface{}, error) {
+	data, err := t.request("GET", "/api/v1/account-overview", map[string]interface{}{
+		"currency": kucoinSettleCurrency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	var account struct {
+		AccountEquity    float64 `json:"accountEquity"`
+		UnrealisedPNL    float64 `json:"unrealisedPNL"`
+		AvailableBalance float64 `json:"availableBalance"`
+	}
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, err
+	}
+
+	// 返回与Binance相同的字段名，钱包余额不含未实现盈亏
+	return map[string]interface{}{
+		"totalWalletBalance":    account.AccountEquity - account.UnrealisedPNL,
+		"availableBalance":      account.AvailableBalance,
+		"totalUnrealizedProfit": account.UnrealisedPNL,
+	}, nil
+}
+
+// GetPositions 获取持仓信息，数量由张数换算为币的数量
+func (t *KuCoinTrader) GetPositions() ([]map[string]interface{}, error) {
+	data, err := t.request("GET", "/api/v1/positions", map[string]interface{}{
+		"currency": kucoinSettleCurrency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	var positions []struct {
+		Symbol           string  `json:"symbol"`
+		IsOpen           bool    `json:"isOpen"`
+		CurrentQty       float64 `json:"currentQty"` // 张数，空仓为负
+		AvgEntryPrice    float64 `json:"avgEntryPrice"`
+		MarkPrice        float64 `json:"markPrice"`
+		UnrealisedPnl    float64 `json:"unrealisedPnl"`
+		RealLeverage     float64 `json:"realLeverage"`
+		Leverage         float64 `json:"leverage"`
+		LiquidationPrice float64 `json:"liquidationPrice"`
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, pos := range positions {
+		if !pos.IsOpen || pos.CurrentQty == 0 {
+			continue // 跳过空仓位
+		}
+
+		symbol := t.rest.localSymbol(pos.Symbol)
+		contract, err := t.getContract(symbol)
+		if err != nil {
+			log.Printf("  ⚠ 获取 %s 合约信息失败: %v", pos.Symbol, err)
+			continue
+		}
+
+		side := "long"
+		if pos.CurrentQty < 0 {
+			side = "short"
+		}
+
+		leverage := pos.Leverage
+		if leverage == 0 {
+			leverage = pos.RealLeverage
+		}
+
+		// 返回与Binance相同的字段名
+		result = append(result, map[string]interface{}{
+			"symbol":           symbol,
+			"side":             side,
+			"positionAmt":      absFloat(pos.CurrentQty) * contract.Multiplier,
+			"entryPrice":       pos.AvgEntryPrice,
+			"markPrice":        pos.MarkPrice,
+			"unRealizedProfit": pos.UnrealisedPnl,
+			"leverage":         leverage,
+			"liquidationPrice": pos.LiquidationPrice,
+		})
+	}
+
+	return result, nil
+}
+
+// placeOrder 下市价单
+func (t *KuCoinTrader) placeOrder(symbol, side string, quantity float64, reduceOnly bool) (map[string]interface{}, error) {
+	lots, err := t.toLots(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	marginMode := t.marginMode
+	leverage := t.leverage[symbol]
+	t.mu.RUnlock()
+
+	params := map[string]interface{}{
+		"clientOid":  t.clientOid(),
+		"symbol":     t.rest.exchangeSymbol(symbol),
+		"side":       side,
+		"type":       "market",
+		"size":       lots,
+		"marginMode": marginMode,
+		"reduceOnly": reduceOnly,
+	}
+	if leverage > 0 {
+		params["leverage"] = strconv.Itoa(leverage)
+	}
+
+	data, err := t.request("POST", "/api/v1/orders", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var order struct {
+		OrderID string `json:"orderId"`
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+
+	log.Printf("  订单ID: %s (%d张)", order.OrderID, lots)
+	return map[string]interface{}{
+		"orderId": order.OrderID,
+		"symbol":  symbol,
+		"lots":    lots,
+	}, nil
+}
+
+// OpenLong 开多单
+func (t *KuCoinTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "buy", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开多仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// OpenShort 开空单
+func (t *KuCoinTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "sell", quantity, false)
+	if err != nil {
+		return nil, fmt.Errorf("开空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开空仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// CloseLong 平多单（quantity为0时平掉全部多仓）
+func (t *KuCoinTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "long")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到多仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "sell", quantity, true)
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平多仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// CloseShort 平空单（quantity为0时平掉全部空仓）
+func (t *KuCoinTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "short")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到空仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "buy", quantity, true)
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平空仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// positionQuantity 获取指定方向的持仓数量
+func (t *KuCoinTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return pos["positionAmt"].(float64), nil
+		}
+	}
+
+	sideName := "多仓"
+	if side == "short" {
+		sideName = "空仓"
+	}
+	return 0, fmt.Errorf("没有找到 %s 的%s", symbol, sideName)
+}
+
+// SetMarginMode 设置仓位模式，成功后之后的下单也会使用该模式
+// 失败时保留原模式并返回错误，避免下单时提交与交易所不一致的 marginMode
+func (t *KuCoinTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	marginMode := "CROSS"
+	if !isCrossMargin {
+		marginMode = "ISOLATED"
+	}
+
+	_, err := t.request("POST", "/api/v2/position/changeMarginMode", map[string]interface{}{
+		"symbol":     t.rest.exchangeSymbol(symbol),
+		"marginMode": marginMode,
+	})
+	if err != nil {
+		return fmt.Errorf("设置仓位模式失败: %w", err)
+	}
+
+	t.mu.Lock()
+	t.marginMode = marginMode
+	t.mu.Unlock()
+
+	log.Printf("  ✓ %s 仓位模式已设置为 %s", symbol, marginMode)
+	return nil
+}
+
+// SetLeverage 设置杠杆倍数
+// 全仓杠杆通过接口设置；逐仓杠杆随订单提交，这里只记录
+func (t *KuCoinTrader) SetLeverage(symbol string, leverage int) error {
+	t.mu.Lock()
+	t.leverage[symbol] = leverage
+	cross := t.marginMode == "CROSS"
+	t.mu.Unlock()
+
+	if cross {
+		_, err := t.request("POST", "/api/v2/changeCrossUserLeverage", map[string]interface{}{
+			"symbol":   t.rest.exchangeSymbol(symbol),
+			"leverage": strconv.Itoa(leverage),
+		})
+		if err != nil {
+			return fmt.Errorf("设置杠杆失败: %w", err)
+		}
+	}
+
+	log.Printf("  ✓ %s 杠杆已切换为 %dx", symbol, leverage)
+	return nil
+}
+
+// GetMarketPrice 获取最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *KuCoinTrader) GetMarketPrice(symbol string) (float64, error) {
+	data, err := t.publicGet("/api/v1/ticker", url.Values{
+		"symbol": {t.rest.exchangeSymbol(symbol)},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("获取价格失败: %w", err)
+	}
+
+	var ticker struct {
+		Price        string `json:"price"`
+		BestBidPrice string `json:"bestBidPrice"`
+		BestAskPrice string `json:"bestAskPrice"`
+	}
+	if err := json.Unmarshal(data, &ticker); err != nil {
+		return 0, err
+	}
+
+	last, err := strconv.ParseFloat(ticker.Price, 64)
+	if err != nil {
+		return 0, err
+	}
+	bid, _ := strconv.ParseFloat(ticker.BestBidPrice, 64)
+	ask, _ := strconv.ParseFloat(ticker.BestAskPrice, 64)
+	return pickMarketPrice(last, bid, ask), nil
+}
+
+// placeStopOrder 下标记价格触发的reduce-only条件市价单
+// stop: down=价格跌破触发, up=价格涨破触发
+func (t *KuCoinTrader) placeStopOrder(symbol, positionSide, stop string, quantity, stopPrice float64) error {
+	side := "sell"
+	if positionSide == "SHORT" {
+		side = "buy"
+	}
+
+	lots, err := t.toLots(symbol, quantity)
+	if err != nil {
+		return err
+	}
+	priceStr, err := t.formatPrice(symbol, stopPrice)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.request("POST", "/api/v1/orders", map[string]interface{}{
+		"clientOid":     t.clientOid(),
+		"symbol":        t.rest.exchangeSymbol(symbol),
+		"side":          side,
+		"type":          "market",
+		"size":          lots,
+		"stop":          stop,
+		"stopPriceType": "MP",
+		"stopPrice":     priceStr,
+		"reduceOnly":    true,
+	})
+	return err
+}
+
+// SetStopLoss 设置止损
+func (t *KuCoinTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	// 多仓止损在价格下跌时触发，空仓止损在价格上涨时触发
+	stop := "down"
+	if positionSide == "SHORT" {
+		stop = "up"
+	}
+
+	if err := t.placeStopOrder(symbol, positionSide, stop, quantity, stopPrice); err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	log.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈
+func (t *KuCoinTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	// 多仓止盈在价格上涨时触发，空仓止盈在价格下跌时触发
+	stop := "up"
+	if positionSide == "SHORT" {
+		stop = "down"
+	}
+
+	if err := t.placeStopOrder(symbol, positionSide, stop, quantity, takeProfitPrice); err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	log.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 取消该币种的所有普通挂单和条件单
+func (t *KuCoinTrader) CancelAllOrders(symbol string) error {
+	params := map[string]interface{}{
+		"symbol": t.rest.exchangeSymbol(symbol),
+	}
+	if _, err := t.request("DELETE", "/api/v1/orders", params); err != nil {
+		return fmt.Errorf("取消挂单失败: %w", err)
+	}
+	if _, err := t.request("DELETE", "/api/v1/stopOrders", params); err != nil {
+		return fmt.Errorf("取消条件单失败: %w", err)
+	}
+
+	log.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 将数量向下取整到整数张对应的币数量（实现Trader接口）
+func (t *KuCoinTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return "", err
+	}
+
+	lots := math.Floor(quantity/contract.Multiplier/contract.LotSize+1e-9) * contract.LotSize
+	return strconv.FormatFloat(lots*contract.Multiplier, 'f', stepDecimals(contract.Multiplier), 64), nil
+}
+
+// stepDecimals 计算步进值的小数位数（如 0.001 -> 3）
+func stepDecimals(step float64) int {
+	return calculatePrecision(strconv.FormatFloat(step, 'f', -1, 64))
+}
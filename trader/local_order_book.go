@@ -0,0 +1,116 @@
+package trader
+
+import (
+	"sort"
+	"sync"
+)
+
+// PendingOrder 本地记录的挂单
+type PendingOrder struct {
+	OrderID  string
+	Symbol   string
+	Side     string  // BUY/SELL
+	Price    float64 // 挂单价格
+	Quantity float64 // 未成交数量
+}
+
+// LocalOrderBook 自身挂单的内存视图，避免每次都向交易所查询挂单（并发安全）
+// 下单后 Add，部分成交后 Update 剩余数量，完全成交或撤单后 Remove
+type LocalOrderBook struct {
+	mu     sync.RWMutex
+	orders map[string]map[string]PendingOrder // symbol -> orderID -> order
+}
+
+// NewLocalOrderBook 创建本地挂单簿
+func NewLocalOrderBook() *LocalOrderBook {
+	return &LocalOrderBook{
+		orders: make(map[string]map[string]PendingOrder),
+	}
+}
+
+// Add 添加挂单
+func (b *LocalOrderBook) Add(order PendingOrder) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.orders[order.Symbol] == nil {
+		b.orders[order.Symbol] = make(map[string]PendingOrder)
+	}
+	b.orders[order.Symbol][order.OrderID] = order
+}
+
+// Update 更新挂单（如部分成交后的剩余数量），数量为0时移除
+func (b *LocalOrderBook) Update(order PendingOrder) {
+	if order.Quantity <= 0 {
+		b.Remove(order.OrderID)
+		return
+	}
+	b.Add(order)
+}
+
+// Remove 移除挂单（成交或撤单）
+func (b *LocalOrderBook) Remove(orderID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for symbol, orders := range b.orders {
+		if _, exists := orders[orderID]; exists {
+			delete(orders, orderID)
+			if len(orders) == 0 {
+				delete(b.orders, symbol)
+			}
+			return
+		}
+	}
+}
+
+// Clear 清空指定币种的所有挂单
+func (b *LocalOrderBook) Clear(symbol string) {
+	b.mu.Lock()
+	delete(b.orders, symbol)
+	b.mu.Unlock()
+}
+
+// GetBids 获取买单（价格从高到低）
+func (b *LocalOrderBook) GetBids(symbol string) []PendingOrder {
+	bids := b.ordersBySide(symbol, "BUY")
+	sort.Slice(bids, func(i, j int) bool {
+		return bids[i].Price > bids[j].Price
+	})
+	return bids
+}
+
+// GetAsks 获取卖单（价格从低到高）
+func (b *LocalOrderBook) GetAsks(symbol string) []PendingOrder {
+	asks := b.ordersBySide(symbol, "SELL")
+	sort.Slice(asks, func(i, j int) bool {
+		return asks[i].Price < asks[j].Price
+	})
+	return asks
+}
+
+// GetTotalPendingNotional 汇总指定币种挂单的名义价值（数量 × 价格）
+func (b *LocalOrderBook) GetTotalPendingNotional(symbol string) float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	total := 0.0
+	for _, order := range b.orders[symbol] {
+		total += order.Quantity * order.Price
+	}
+	return total
+}
+
+// ordersBySide 按方向筛选挂单
+func (b *LocalOrderBook) ordersBySide(symbol, side string) []PendingOrder {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var orders []PendingOrder
+	for _, order := range b.orders[symbol] {
+		if order.Side == side {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
@@ -0,0 +1,618 @@
+package trader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const mexcContractBaseURL = "https://contract.mexc.com"
+
+// MEXC 下单方向
+const (
+	mexcSideOpenLong   = 1
+	mexcSideCloseShort = 2
+	mexcSideOpenShort  = 3
+	mexcSideCloseLong  = 4
+)
+
+// MEXC 保证金模式
+const (
+	mexcOpenTypeIsolated = 1
+	mexcOpenTypeCross    = 2
+)
+
+// MEXCTrader MEXC USDT永续合约交易器
+// MEXC按张下单，每张合约对应 contractSize 个币，对外接口仍使用币的数量
+type MEXCTrader struct {
+	apiKey    string
+	secretKey string
+	rest      *restExchange
+
+	// 下单时使用的保证金模式和杠杆（MEXC随订单提交）
+	openType int
+	leverage map[string]int
+
+	// 缓存合约信息
+	contracts map[string]mexcContract
+	mu        sync.RWMutex
+}
+
+// mexcContract 合约规格
+type mexcContract struct {
+	ContractSize float64 // 每张合约对应的币数量
+	VolUnit      float64 // 张数步进
+	PriceUnit    float64 // 价格步进
+}
+
+// mexcResponse MEXC 合约通用响应结构
+type mexcResponse struct {
+	Success bool            `json:"success"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func init() {
+	Register("mexc", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用MEXC合约交易", cfg.Name)
+		return NewMEXCTrader(cfg.MEXCAPIKey, cfg.MEXCSecretKey), nil
+	})
+}
+
+// NewMEXCTrader 创建MEXC合约交易器
+func NewMEXCTrader(apiKey, secretKey string) *MEXCTrader {
+	t := &MEXCTrader{
+		apiKey:    apiKey,
+		secretKey: secretKey,
+		openType:  mexcOpenTypeCross,
+		leverage:  make(map[string]int),
+		contracts: make(map[string]mexcContract),
+	}
+	t.rest = newRESTExchange(restExchangeConfig{
+		Name:    "MEXC",
+		BaseURL: mexcContractBaseURL,
+		Signer:  t.sign,
+		Decoder: decodeMEXC,
+		Symbols: restSymbolMapper{
+			toExchange:   toMEXCSymbol,
+			fromExchange: fromMEXCSymbol,
+		},
+	})
+	return t
+}
+
+// toMEXCSymbol 将 BTCUSDT 转换为 MEXC 合约代码 BTC_USDT
+func toMEXCSymbol(symbol string) string {
+	return strings.TrimSuffix(strings.ToUpper(symbol), "USDT") + "_USDT"
+}
+
+// fromMEXCSymbol 将 MEXC 合约代码 BTC_USDT 转换回 BTCUSDT
+func fromMEXCSymbol(symbol string) string {
+	return strings.ReplaceAll(symbol, "_", "")
+}
+
+// sign 签名钩子: hex(HMAC_SHA256(secret, apiKey + reqTime + paramString))
+// GET/DELETE 的 paramString 为按key排序的 k=v&k=v（值URL编码），POST 为JSON字符串
+func (t *MEXCTrader) sign(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+	rawQuery := query.Encode() // Encode 按key排序
+	paramString := string(body)
+	if method == "GET" || method == "DELETE" {
+		paramString = rawQuery
+	}
+
+	reqTime := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(t.secretKey))
+	mac.Write([]byte(t.apiKey + reqTime + paramString))
+
+	header.Set("ApiKey", t.apiKey)
+	header.Set("Request-Time", reqTime)
+	header.Set("Signature", hex.EncodeToString(mac.Sum(nil)))
+	header.Set("Content-Type", "application/json")
+	return rawQuery, nil
+}
+
+// decodeMEXC 解析响应包装，success为false或code非0时返回错误
+func decodeMEXC(statusCode int, body []byte) (json.RawMessage, error) {
+	var result mexcResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+	if !result.Success || result.Code != 0 {
+		return nil, fmt.Errorf("MEXC API错误 %d: %s", result.Code, result.Message)
+	}
+	return result.Data, nil
+}
+
+// request 发送签名请求，GET/DELETE参数放在querystring，POST参数以JSON放在body
+func (t *MEXCTrader) request(method, endpoint string, params map[string]interface{}) (json.RawMessage, error) {
+	method = strings.ToUpper(method)
+	if method != "GET" && method != "DELETE" && method != "POST" {
+		return nil, fmt.Errorf("不支持的HTTP方法: %s", method)
+	}
+	query, payload := restParams(method, params)
+	return t.rest.signedCall(method, endpoint, query, payload)
+}
+
+// publicGet 请求无需签名的行情接口
+func (t *MEXCTrader) publicGet(endpoint string, params url.Values) (json.RawMessage, error) {
+	return t.rest.publicCall("GET", endpoint, params)
+}
+
+// getContract 获取合约规格（合约乘数、张数步进、价格步进）
+func (t *MEXCTrader) getContract(symbol string) (mexcContract, error) {
+	mexcSymbol := t.rest.exchangeSymbol(symbol)
+
+	t.mu.RLock()
+	if contract, ok := t.contracts[mexcSymbol]; ok {
+		t.mu.RUnlock()
+		return contract, nil
+	}
+	t.mu.RUnlock()
+
+	data, err := t.publicGet("/api/v1/contract/detail", url.Values{"symbol": {mexcSymbol}})
+	if err != nil {
+		return mexcContract{}, fmt.Errorf("获取合约信息失败: %w", err)
+	}
+
+	var detail struct {
+		ContractSize float64 `json:"contractSize"`
+		VolUnit      float64 `json:"volUnit"`
+		PriceUnit    float64 `json:"priceUnit"`
+	}
+	if err := json.Unmarshal(data, &detail); err != nil {
+		return mexcContract{}, err
+	}
+	if detail.ContractSize <= 0 {
+		return mexcContract{}, fmt.Errorf("未找到合约 %s 的规格信息", mexcSymbol)
+	}
+
+	contract := mexcContract{
+		ContractSize: detail.ContractSize,
+		VolUnit:      detail.VolUnit,
+		PriceUnit:    detail.PriceUnit,
+	}
+	if contract.VolUnit <= 0 {
+		contract.VolUnit = 1
+	}
+
+	t.mu.Lock()
+	t.contracts[mexcSymbol] = contract
+	t.mu.Unlock()
+
+	return contract, nil
+}
+
+// toVol 将币的数量换算为合约张数（向下取整到volUnit）
+func (t *MEXCTrader) toVol(symbol string, quantity float64) (float64, error) {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	vol := math.Floor(quantity/contract.ContractSize/contract.VolUnit+1e-9) * contract.VolUnit
+	if vol <= 0 {
+		return 0, fmt.Errorf("%s 数量 %.8f 不足一张合约（每张 %v）", symbol, quantity, contract.ContractSize)
+	}
+	return vol, nil
+}
+
+// getTicker 获取最新成交价和合理价格（标记价格）
+func (t *MEXCTrader) getTicker(symbol string) (price, fairPrice float64, err error) {
+	data, err := t.publicGet("/api/v1/contract/ticker", url.Values{"symbol": {t.rest.exchangeSymbol(symbol)}})
+	if err != nil {
+		return 0, 0, fmt.Errorf("获取行情失败: %w", err)
+	}
+
+	var ticker struct {
+		LastPrice float64 `json:"lastPrice"`
+		FairPrice float64 `json:"fairPrice"`
+		Bid1      float64 `json:"bid1"`
+		Ask1      float64 `json:"ask1"`
+	}
+	if err := json.Unmarshal(data, &ticker); err != nil {
+		return 0, 0, err
+	}
+	return pickMarketPrice(ticker.LastPrice, ticker.Bid1, ticker.Ask1), ticker.FairPrice, nil
+}
+
+// GetBalance 获取USDT合约账户余额
+func (t *MEXCTrader) GetBalance() (map[string]interface{}, error) {
+	data, err := t.request("GET", "/api/v1/private/account/asset/USDT", nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	var asset struct {
+		Equity           float64 `json:"equity"`
+		Unrealized       float64 `json:"unrealized"`
+		AvailableBalance float64 `json:"availableBalance"`
+	}
+	if err := json.Unmarshal(data, &asset); err != nil {
+		return nil, err
+	}
+
+	// 返回与Binance相同的字段名，钱包余额不含未实现盈亏
+	return map[string]interface{}{
+		"totalWalletBalance":    asset.Equity - asset.Unrealized,
+		"availableBalance":      asset.AvailableBalance,
+		"totalUnrealizedProfit": asset.Unrealized,
+	}, nil
+}
+
+// GetPositions 获取持仓信息，数量由张数换算为币的数量
+// 持仓接口不返回标记价格，使用行情接口的合理价格计算未实现盈亏
+func (t *MEXCTrader) GetPositions() ([]map[string]interface{}, error) {
+	data, err := t.request("GET", "/api/v1/private/position/open_positions", nil)
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	var positions []struct {
+		Symbol         string  `json:"symbol"`
+		PositionType   int     `json:"positionType"` // 1=多 2=空
+		HoldVol        float64 `json:"holdVol"`
+		HoldAvgPrice   float64 `json:"holdAvgPrice"`
+		LiquidatePrice float64 `json:"liquidatePrice"`
+		Leverage       float64 `json:"leverage"`
+	}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for _, pos := range positions {
+		if pos.HoldVol == 0 {
+			continue // 跳过空仓位
+		}
+
+		symbol := t.rest.localSymbol(pos.Symbol)
+		contract, err := t.getContract(symbol)
+		if err != nil {
+			log.Printf("  ⚠ 获取 %s 合约信息失败: %v", pos.Symbol, err)
+			continue
+		}
+
+		_, markPrice, err := t.getTicker(symbol)
+		if err != nil {
+			log.Printf("  ⚠ 获取 %s 标记价格失败: %v", pos.Symbol, err)
+		}
+
+		quantity := pos.HoldVol * contract.ContractSize
+		side := "long"
+		unRealizedProfit := 0.0
+		if markPrice > 0 {
+			unRealizedProfit = (markPrice - pos.HoldAvgPrice) * quantity
+		}
+		if pos.PositionType == 2 {
+			side = "short"
+			unRealizedProfit = -unRealizedProfit
+		}
+
+		// 返回与Binance相同的字段名
+		result = append(result, map[string]interface{}{
+			"symbol":           symbol,
+			"side":             side,
+			"positionAmt":      quantity,
+			"entryPrice":       pos.HoldAvgPrice,
+			"markPrice":        markPrice,
+			"unRealizedProfit": unRealizedProfit,
+			"leverage":         pos.Leverage,
+			"liquidationPrice": pos.LiquidatePrice,
+		})
+	}
+
+	return result, nil
+}
+
+// orderParams 构造下单公共参数（保证金模式和杠杆随订单提交）
+func (t *MEXCTrader) orderParams(symbol string, side int, vol float64) map[string]interface{} {
+	t.mu.RLock()
+	openType := t.openType
+	leverage := t.leverage[symbol]
+	t.mu.RUnlock()
+
+	params := map[string]interface{}{
+		"symbol":   t.rest.exchangeSymbol(symbol),
+		"side":     side,
+		"vol":      vol,
+		"openType": openType,
+	}
+	if leverage > 0 {
+		params["leverage"] = leverage
+	}
+	return params
+}
+
+// placeOrder 下市价单
+func (t *MEXCTrader) placeOrder(symbol string, side int, quantity float64) (map[string]interface{}, error) {
+	vol, err := t.toVol(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	params := t.orderParams(symbol, side, vol)
+	params["type"] = 5 // 市价单
+	params["price"] = 0
+
+	data, err := t.request("POST", "/api/v1/private/order/submit", params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 成功时data为订单ID
+	var orderID interface{}
+	if err := json.Unmarshal(data, &orderID); err != nil {
+		return nil, err
+	}
+
+	log.Printf("  订单ID: %v (%v张)", orderID, vol)
+	return map[string]interface{}{
+		"orderId": orderID,
+		"symbol":  symbol,
+		"vol":     vol,
+	}, nil
+}
+
+// OpenLong 开多单
+func (t *MEXCTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, mexcSideOpenLong, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开多仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// OpenShort 开空单
+func (t *MEXCTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, mexcSideOpenShort, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("开空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开空仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// CloseLong 平多单（quantity为0时平掉全部多仓）
+func (t *MEXCTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "long")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到多仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, mexcSideCloseLong, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平多仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// CloseShort 平空单（quantity为0时平掉全部空仓）
+func (t *MEXCTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "short")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到空仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, mexcSideCloseShort, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平空仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// positionQuantity 获取指定方向的持仓数量
+func (t *MEXCTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return pos["positionAmt"].(float64), nil
+		}
+	}
+
+	sideName := "多仓"
+	if side == "short" {
+		sideName = "空仓"
+	}
+	return 0, fmt.Errorf("没有找到 %s 的%s", symbol, sideName)
+}
+
+// SetMarginMode 设置仓位模式（MEXC的保证金模式随订单提交，这里只记录）
+func (t *MEXCTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	openType := mexcOpenTypeCross
+	modeName := "全仓"
+	if !isCrossMargin {
+		openType = mexcOpenTypeIsolated
+		modeName = "逐仓"
+	}
+
+	t.mu.Lock()
+	t.openType = openType
+	t.mu.Unlock()
+
+	log.Printf("  ✓ %s 仓位模式已设置为 %s", symbol, modeName)
+	return nil
+}
+
+// SetLeverage 设置杠杆倍数
+// 杠杆会随开仓订单提交；同时尝试修改已有持仓的杠杆，失败时仅记录警告
+func (t *MEXCTrader) SetLeverage(symbol string, leverage int) error {
+	t.mu.Lock()
+	t.leverage[symbol] = leverage
+	openType := t.openType
+	t.mu.Unlock()
+
+	for _, positionType := range []int{1, 2} {
+		_, err := t.request("POST", "/api/v1/private/position/change_leverage", map[string]interface{}{
+			"symbol":       t.rest.exchangeSymbol(symbol),
+			"leverage":     leverage,
+			"openType":     openType,
+			"positionType": positionType,
+		})
+		if err != nil {
+			log.Printf("  ⚠ 修改 %s 杠杆失败（将随订单提交）: %v", symbol, err)
+			return nil
+		}
+	}
+
+	log.Printf("  ✓ %s 杠杆已切换为 %dx", symbol, leverage)
+	return nil
+}
+
+// GetMarketPrice 获取最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *MEXCTrader) GetMarketPrice(symbol string) (float64, error) {
+	price, _, err := t.getTicker(symbol)
+	if err != nil {
+		return 0, err
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("未找到 %s 的行情", symbol)
+	}
+	return price, nil
+}
+
+// placePlanOrder 下合理价格触发的计划市价单
+// triggerType: 1=价格大于等于触发价, 2=价格小于等于触发价
+func (t *MEXCTrader) placePlanOrder(symbol, positionSide string, quantity, triggerPrice float64, triggerType int) error {
+	side := mexcSideCloseLong
+	if positionSide == "SHORT" {
+		side = mexcSideCloseShort
+	}
+
+	vol, err := t.toVol(symbol, quantity)
+	if err != nil {
+		return err
+	}
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return err
+	}
+
+	params := t.orderParams(symbol, side, vol)
+	params["triggerPrice"] = roundToTickSize(triggerPrice, contract.PriceUnit)
+	params["triggerType"] = triggerType
+	params["trend"] = 2        // 合理价格（标记价格）触发
+	params["orderType"] = 5    // 触发后市价成交
+	params["executeCycle"] = 2 // 7天有效
+
+	_, err = t.request("POST", "/api/v1/private/planorder/place", params)
+	return err
+}
+
+// SetStopLoss 设置止损
+func (t *MEXCTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	// 多仓止损在价格下跌时触发，空仓止损在价格上涨时触发
+	triggerType := 2
+	if positionSide == "SHORT" {
+		triggerType = 1
+	}
+
+	if err := t.placePlanOrder(symbol, positionSide, quantity, stopPrice, triggerType); err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	log.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈
+func (t *MEXCTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	// 多仓止盈在价格上涨时触发，空仓止盈在价格下跌时触发
+	triggerType := 1
+	if positionSide == "SHORT" {
+		triggerType = 2
+	}
+
+	if err := t.placePlanOrder(symbol, positionSide, quantity, takeProfitPrice, triggerType); err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	log.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 取消该合约的所有普通挂单和计划单
+func (t *MEXCTrader) CancelAllOrders(symbol string) error {
+	params := map[string]interface{}{
+		"symbol": t.rest.exchangeSymbol(symbol),
+	}
+	if _, err := t.request("POST", "/api/v1/private/order/cancel_all", params); err != nil {
+		return fmt.Errorf("取消挂单失败: %w", err)
+	}
+	if _, err := t.request("POST", "/api/v1/private/planorder/cancel_all", params); err != nil {
+		return fmt.Errorf("取消计划单失败: %w", err)
+	}
+
+	log.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 将数量向下取整到整数张对应的币数量（实现Trader接口）
+func (t *MEXCTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	contract, err := t.getContract(symbol)
+	if err != nil {
+		return "", err
+	}
+
+	vol := math.Floor(quantity/contract.ContractSize/contract.VolUnit+1e-9) * contract.VolUnit
+	return strconv.FormatFloat(vol*contract.ContractSize, 'f', stepDecimals(contract.ContractSize)+stepDecimals(contract.VolUnit), 64), nil
+}
@@ -0,0 +1,596 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// okxBaseURL OKX公开行情接口地址，模拟盘使用其永续合约最新价成交
+	okxBaseURL = "https://www.okx.com"
+	// paperTakerFeeRate 模拟成交手续费率（按吃单费率计算）
+	paperTakerFeeRate = 0.0005
+	// paperQuantityDecimals 模拟盘数量精度
+	paperQuantityDecimals = 6
+)
+
+// PriceSource 行情价格来源
+type PriceSource func(symbol string) (float64, error)
+
+// paperPosition 模拟持仓
+type paperPosition struct {
+	symbol     string
+	side       string // long / short
+	quantity   float64
+	entryPrice float64
+	leverage   int
+	isolated   bool
+
+	// 止损止盈（价格为0表示未设置）
+	stopLoss      float64
+	stopLossQty   float64
+	takeProfit    float64
+	takeProfitQty float64
+}
+
+// PaperTrader 模拟盘交易器
+// 市价单按OKX实时价格成交，余额、持仓和盈亏只保存在内存中，
+// 支持杠杆保证金校验、爆仓以及止损止盈触发，用于在不动用真实资金的情况下验证策略
+type PaperTrader struct {
+	priceSource PriceSource
+	feeRate     float64
+
+	walletBalance float64 // 已实现的账户余额（含已扣手续费）
+	positions     map[string]*paperPosition
+	isolated      map[string]bool
+	orderSeq      int64
+
+	mu sync.Mutex
+}
+
+func init() {
+	Register("paper", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🧪 [%s] 使用模拟盘交易（OKX实时价格），初始资金 %.2f USDT", cfg.Name, cfg.InitialBalance)
+		return NewPaperTrader(cfg.InitialBalance), nil
+	})
+}
+
+// NewPaperTrader 创建模拟盘交易器，使用OKX实时价格成交
+func NewPaperTrader(initialBalance float64) *PaperTrader {
+	return NewPaperTraderWithPriceSource(initialBalance, NewOKXPriceSource(okxBaseURL))
+}
+
+// NewPaperTraderWithPriceSource 创建使用自定义价格来源的模拟盘交易器
+func NewPaperTraderWithPriceSource(initialBalance float64, source PriceSource) *PaperTrader {
+	return &PaperTrader{
+		priceSource:   source,
+		feeRate:       paperTakerFeeRate,
+		walletBalance: initialBalance,
+		positions:     make(map[string]*paperPosition),
+		isolated:      make(map[string]bool),
+	}
+}
+
+// NewOKXPriceSource 返回读取OKX永续合约最新价的价格来源（开启 market.SetUseMidPrice 时为买一卖一中间价）
+// baseURL 一般为 https://www.okx.com，测试时可指向 tradertest 的回放服务器
+func NewOKXPriceSource(baseURL string) PriceSource {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+			IdleConnTimeout:       90 * time.Second,
+		},
+	}
+
+	return func(symbol string) (float64, error) {
+		resp, err := client.Get(baseURL + "/api/v5/market/ticker?" + url.Values{"instId": {toOKXSwapInstID(symbol)}}.Encode())
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		var result struct {
+			Code string `json:"code"`
+			Msg  string `json:"msg"`
+			Data []struct {
+				Last  string `json:"last"`
+				BidPx string `json:"bidPx"`
+				AskPx string `json:"askPx"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return 0, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		}
+		if result.Code != "0" {
+			return 0, fmt.Errorf("OKX API错误 %s: %s", result.Code, result.Msg)
+		}
+		if len(result.Data) == 0 {
+			return 0, fmt.Errorf("未找到 %s 的行情", symbol)
+		}
+		// ticker 中已包含买一卖一价，开启中间价模式时使用 (ask+bid)/2
+		last, err := strconv.ParseFloat(result.Data[0].Last, 64)
+		if err != nil {
+			return 0, err
+		}
+		bid, _ := strconv.ParseFloat(result.Data[0].BidPx, 64)
+		ask, _ := strconv.ParseFloat(result.Data[0].AskPx, 64)
+		return pickMarketPrice(last, bid, ask), nil
+	}
+}
+
+// toOKXSwapInstID 将 BTCUSDT 转换为 OKX 永续合约代码 BTC-USDT-SWAP
+func toOKXSwapInstID(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	if base := strings.TrimSuffix(symbol, "USDT"); base != symbol {
+		return base + "-USDT-SWAP"
+	}
+	return symbol
+}
+
+// paperPositionKey 持仓键
+func paperPositionKey(symbol, side string) string {
+	return symbol + "_" + side
+}
+
+// nextOrderID 生成模拟订单号，按序号递增以保证回测结果可复现（调用方需持有锁）
+func (t *PaperTrader) nextOrderID() string {
+	t.orderSeq++
+	return fmt.Sprintf("paper-%d", t.orderSeq)
+}
+
+// unrealizedPnL 计算持仓浮动盈亏
+func (p *paperPosition) unrealizedPnL(price float64) float64 {
+	if p.side == "long" {
+		return (price - p.entryPrice) * p.quantity
+	}
+	return (p.entryPrice - price) * p.quantity
+}
+
+// margin 持仓占用保证金
+func (p *paperPosition) margin() float64 {
+	return p.quantity * p.entryPrice / float64(p.leverage)
+}
+
+// liquidationPrice 估算强平价（不考虑维持保证金）
+// 逐仓按持仓保证金计算，全仓由checkTriggers按账户权益判断
+func (p *paperPosition) liquidationPrice() float64 {
+	if p.side == "long" {
+		return p.entryPrice * (1 - 1/float64(p.leverage))
+	}
+	return p.entryPrice * (1 + 1/float64(p.leverage))
+}
+
+// getPrice 获取价格并检查该币种的止损止盈和强平
+func (t *PaperTrader) getPrice(symbol string) (float64, error) {
+	price, err := t.priceSource(symbol)
+	if err != nil {
+		return 0, fmt.Errorf("获取价格失败: %w", err)
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("%s 价格无效: %v", symbol, price)
+	}
+
+	t.mu.Lock()
+	t.checkTriggers(symbol, price, price, price)
+	t.mu.Unlock()
+
+	return price, nil
+}
+
+// checkTriggers 按一根K线（实时行情时四个价格相同）触发止损止盈和强平（调用方需持有锁）
+// 同一根K线内同时触及止损和止盈时按先止损处理；跳空越过触发价时按开盘价成交；
+// 止损止盈与真实条件单一样只触发一次
+func (t *PaperTrader) checkTriggers(symbol string, open, high, low float64) {
+	for _, side := range []string{"long", "short"} {
+		pos, ok := t.positions[paperPositionKey(symbol, side)]
+		if !ok {
+			continue
+		}
+
+		// worst 该K线内对持仓最不利的价格
+		worst := low
+		if side == "short" {
+			worst = high
+		}
+
+		stopHit := pos.stopLoss > 0 &&
+			((side == "long" && low <= pos.stopLoss) || (side == "short" && high >= pos.stopLoss))
+		if stopHit {
+			fill := pos.stopLoss
+			if (side == "long" && open < fill) || (side == "short" && open > fill) {
+				fill = open
+			}
+			log.Printf("🛑 [模拟盘] %s %s 触发止损 @ %.4f", symbol, side, fill)
+			// 条件单只触发一次，成交后清除
+			quantity := pos.stopLossQty
+			pos.stopLoss, pos.stopLossQty = 0, 0
+			t.closeLocked(pos, quantity, fill)
+			if _, ok := t.positions[paperPositionKey(symbol, side)]; !ok {
+				continue
+			}
+		}
+
+		// 强平：逐仓亏损超过持仓保证金，全仓亏损超过账户余额
+		loss := -pos.unrealizedPnL(worst)
+		if (pos.isolated && loss >= pos.margin()) || (!pos.isolated && loss >= t.walletBalance) {
+			log.Printf("💥 [模拟盘] %s %s 触发强平 @ %.4f", symbol, side, worst)
+			t.closeLocked(pos, pos.quantity, worst)
+			continue
+		}
+
+		takeHit := pos.takeProfit > 0 &&
+			((side == "long" && high >= pos.takeProfit) || (side == "short" && low <= pos.takeProfit))
+		if takeHit {
+			fill := pos.takeProfit
+			if (side == "long" && open > fill) || (side == "short" && open < fill) {
+				fill = open
+			}
+			log.Printf("🎯 [模拟盘] %s %s 触发止盈 @ %.4f", symbol, side, fill)
+			quantity := pos.takeProfitQty
+			pos.takeProfit, pos.takeProfitQty = 0, 0
+			t.closeLocked(pos, quantity, fill)
+		}
+	}
+}
+
+// closeLocked 按价格平掉部分或全部持仓（调用方需持有锁）
+// quantity为0或超过持仓时平掉全部
+func (t *PaperTrader) closeLocked(pos *paperPosition, quantity, price float64) {
+	if quantity <= 0 || quantity > pos.quantity {
+		quantity = pos.quantity
+	}
+
+	pnl := pos.unrealizedPnL(price) * quantity / pos.quantity
+	fee := quantity * price * t.feeRate
+	t.walletBalance += pnl - fee
+
+	pos.quantity -= quantity
+	if pos.quantity <= 1e-12 {
+		delete(t.positions, paperPositionKey(pos.symbol, pos.side))
+	} else {
+		// 条件单数量不超过剩余持仓
+		pos.stopLossQty = math.Min(pos.stopLossQty, pos.quantity)
+		pos.takeProfitQty = math.Min(pos.takeProfitQty, pos.quantity)
+	}
+
+	log.Printf("  [模拟盘] 平仓 %s %s 数量: %.8f 价格: %.4f 盈亏: %.4f 手续费: %.4f",
+		pos.symbol, pos.side, quantity, price, pnl, fee)
+}
+
+// equityLocked 计算账户权益、已用保证金和未实现盈亏（调用方需持有锁）
+func (t *PaperTrader) equityLocked(prices map[string]float64) (equity, usedMargin, unrealized float64) {
+	for _, pos := range t.positions {
+		usedMargin += pos.margin()
+		if price, ok := prices[pos.symbol]; ok {
+			unrealized += pos.unrealizedPnL(price)
+		}
+	}
+	return t.walletBalance + unrealized, usedMargin, unrealized
+}
+
+// positionPrices 获取所有持仓币种的最新价格（同时检查触发条件）
+func (t *PaperTrader) positionPrices() (map[string]float64, error) {
+	t.mu.Lock()
+	symbols := map[string]bool{}
+	for _, pos := range t.positions {
+		symbols[pos.symbol] = true
+	}
+	t.mu.Unlock()
+
+	// 按币种顺序处理，保证触发顺序可复现
+	sorted := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		sorted = append(sorted, symbol)
+	}
+	sort.Strings(sorted)
+
+	prices := make(map[string]float64, len(symbols))
+	for _, symbol := range sorted {
+		price, err := t.getPrice(symbol)
+		if err != nil {
+			return nil, err
+		}
+		prices[symbol] = price
+	}
+	return prices, nil
+}
+
+// GetBalance 获取模拟账户余额
+func (t *PaperTrader) GetBalance() (map[string]interface{}, error) {
+	prices, err := t.positionPrices()
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	equity, usedMargin, unrealized := t.equityLocked(prices)
+
+	// 返回与Binance相同的字段名
+	return map[string]interface{}{
+		"totalWalletBalance":    t.walletBalance,
+		"availableBalance":      math.Max(equity-usedMargin, 0),
+		"totalUnrealizedProfit": unrealized,
+	}, nil
+}
+
+// GetPositions 获取模拟持仓
+func (t *PaperTrader) GetPositions() ([]map[string]interface{}, error) {
+	prices, err := t.positionPrices()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keys := make([]string, 0, len(t.positions))
+	for key := range t.positions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := []map[string]interface{}{}
+	for _, key := range keys {
+		pos := t.positions[key]
+		price := prices[pos.symbol]
+		liquidationPrice := 0.0
+		if pos.isolated {
+			liquidationPrice = pos.liquidationPrice()
+		}
+
+		// 返回与Binance相同的字段名
+		result = append(result, map[string]interface{}{
+			"symbol":           pos.symbol,
+			"side":             pos.side,
+			"positionAmt":      pos.quantity,
+			"entryPrice":       pos.entryPrice,
+			"markPrice":        price,
+			"unRealizedProfit": pos.unrealizedPnL(price),
+			"leverage":         float64(pos.leverage),
+			"liquidationPrice": liquidationPrice,
+		})
+	}
+
+	return result, nil
+}
+
+// open 按市价开仓或加仓
+func (t *PaperTrader) open(symbol, side string, quantity float64, leverage int) (map[string]interface{}, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("开仓数量必须大于0")
+	}
+
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	price, err := t.getPrice(symbol)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := t.positionPrices()
+	if err != nil {
+		return nil, err
+	}
+	prices[symbol] = price
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// 保证金校验
+	equity, usedMargin, _ := t.equityLocked(prices)
+	required := quantity * price / float64(leverage)
+	fee := quantity * price * t.feeRate
+	if available := equity - usedMargin; required+fee > available {
+		return nil, fmt.Errorf("可用保证金不足: 需要 %.2f USDT，可用 %.2f USDT", required+fee, available)
+	}
+
+	key := paperPositionKey(symbol, side)
+	pos, ok := t.positions[key]
+	if !ok {
+		pos = &paperPosition{symbol: symbol, side: side, isolated: t.isolated[symbol]}
+		t.positions[key] = pos
+	}
+	// 加仓时按数量加权计算开仓均价
+	pos.entryPrice = (pos.entryPrice*pos.quantity + price*quantity) / (pos.quantity + quantity)
+	pos.quantity += quantity
+	pos.leverage = leverage
+	t.walletBalance -= fee
+
+	return map[string]interface{}{
+		"orderId": t.nextOrderID(),
+		"symbol":  symbol,
+		"status":  "FILLED",
+		"price":   price,
+	}, nil
+}
+
+// OpenLong 开多单
+func (t *PaperTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	result, err := t.open(symbol, "long", quantity, leverage)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开多仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// OpenShort 开空单
+func (t *PaperTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	result, err := t.open(symbol, "short", quantity, leverage)
+	if err != nil {
+		return nil, fmt.Errorf("开空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开空仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// close 按市价平仓（quantity为0时平掉全部）
+func (t *PaperTrader) close(symbol, side string, quantity float64) (map[string]interface{}, error) {
+	price, err := t.getPrice(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	pos, ok := t.positions[paperPositionKey(symbol, side)]
+	if !ok {
+		t.mu.Unlock()
+		if side == "long" {
+			return nil, fmt.Errorf("没有找到 %s 的多仓", symbol)
+		}
+		return nil, fmt.Errorf("没有找到 %s 的空仓", symbol)
+	}
+	t.closeLocked(pos, quantity, price)
+	orderID := t.nextOrderID()
+	t.mu.Unlock()
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return map[string]interface{}{
+		"orderId": orderID,
+		"symbol":  symbol,
+		"status":  "FILLED",
+		"price":   price,
+	}, nil
+}
+
+// CloseLong 平多单（quantity为0时平掉全部多仓）
+func (t *PaperTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	result, err := t.close(symbol, "long", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平多仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// CloseShort 平空单（quantity为0时平掉全部空仓）
+func (t *PaperTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	result, err := t.close(symbol, "short", quantity)
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平空仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// SetLeverage 校验杠杆倍数（模拟盘杠杆随开仓记录在持仓上）
+func (t *PaperTrader) SetLeverage(symbol string, leverage int) error {
+	if leverage <= 0 {
+		return fmt.Errorf("设置杠杆失败: 杠杆倍数必须大于0")
+	}
+
+	log.Printf("  ✓ %s 杠杆已切换为 %dx", symbol, leverage)
+	return nil
+}
+
+// SetMarginMode 设置仓位模式（仅影响之后新建的持仓）
+func (t *PaperTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	t.mu.Lock()
+	t.isolated[symbol] = !isCrossMargin
+	t.mu.Unlock()
+
+	modeName := "全仓"
+	if !isCrossMargin {
+		modeName = "逐仓"
+	}
+	log.Printf("  ✓ %s 仓位模式已设置为 %s", symbol, modeName)
+	return nil
+}
+
+// GetMarketPrice 获取OKX最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *PaperTrader) GetMarketPrice(symbol string) (float64, error) {
+	return t.getPrice(symbol)
+}
+
+// findPosition 根据 LONG/SHORT 查找持仓（调用方需持有锁）
+func (t *PaperTrader) findPosition(symbol, positionSide string) (*paperPosition, error) {
+	side := strings.ToLower(positionSide)
+	pos, ok := t.positions[paperPositionKey(symbol, side)]
+	if !ok {
+		return nil, fmt.Errorf("没有找到 %s 的%s持仓", symbol, positionSide)
+	}
+	return pos, nil
+}
+
+// SetStopLoss 设置止损（价格触及时按市价平仓）
+func (t *PaperTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	t.mu.Lock()
+	pos, err := t.findPosition(symbol, positionSide)
+	if err == nil {
+		pos.stopLoss = stopPrice
+		pos.stopLossQty = quantity
+	}
+	t.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	log.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈（价格触及时按市价平仓）
+func (t *PaperTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	t.mu.Lock()
+	pos, err := t.findPosition(symbol, positionSide)
+	if err == nil {
+		pos.takeProfit = takeProfitPrice
+		pos.takeProfitQty = quantity
+	}
+	t.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	log.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 清除该币种持仓上的止损止盈
+func (t *PaperTrader) CancelAllOrders(symbol string) error {
+	t.mu.Lock()
+	for _, side := range []string{"long", "short"} {
+		if pos, ok := t.positions[paperPositionKey(symbol, side)]; ok {
+			pos.stopLoss, pos.stopLossQty = 0, 0
+			pos.takeProfit, pos.takeProfitQty = 0, 0
+		}
+	}
+	t.mu.Unlock()
+
+	log.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 将数量向下取整到模拟盘精度（实现Trader接口）
+func (t *PaperTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	step := math.Pow10(-paperQuantityDecimals)
+	return strconv.FormatFloat(floorToStep(quantity, step), 'f', paperQuantityDecimals, 64), nil
+}
@@ -0,0 +1,137 @@
+package trader
+
+import "testing"
+
+// newTestPaperTrader 创建价格可由测试控制的模拟盘交易器
+func newTestPaperTrader(balance float64, price *float64) *PaperTrader {
+	return NewPaperTraderWithPriceSource(balance, func(symbol string) (float64, error) {
+		return *price, nil
+	})
+}
+
+// paperPositionAmt 返回指定方向的持仓数量（无持仓时为0）
+func paperPositionAmt(t *testing.T, paper *PaperTrader, side string) float64 {
+	t.Helper()
+	positions, err := paper.GetPositions()
+	if err != nil {
+		t.Fatalf("GetPositions 返回错误: %v", err)
+	}
+	for _, pos := range positions {
+		if pos["side"] == side {
+			return pos["positionAmt"].(float64)
+		}
+	}
+	return 0
+}
+
+func TestPaperTraderPartialTriggersFireOnce(t *testing.T) {
+	tests := []struct {
+		name   string
+		side   string
+		set    func(p *PaperTrader) error
+		hit    float64
+		remain float64
+	}{
+		{"多仓半仓止损", "long", func(p *PaperTrader) error { return p.SetStopLoss("BTCUSDT", "LONG", 5, 95) }, 94, 5},
+		{"多仓半仓止盈", "long", func(p *PaperTrader) error { return p.SetTakeProfit("BTCUSDT", "LONG", 5, 105) }, 106, 5},
+		{"空仓半仓止损", "short", func(p *PaperTrader) error { return p.SetStopLoss("BTCUSDT", "SHORT", 5, 105) }, 106, 5},
+		{"空仓半仓止盈", "short", func(p *PaperTrader) error { return p.SetTakeProfit("BTCUSDT", "SHORT", 5, 95) }, 94, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price := 100.0
+			paper := newTestPaperTrader(10000, &price)
+
+			var err error
+			if tt.side == "long" {
+				_, err = paper.OpenLong("BTCUSDT", 10, 10)
+			} else {
+				_, err = paper.OpenShort("BTCUSDT", 10, 10)
+			}
+			if err != nil {
+				t.Fatalf("开仓返回错误: %v", err)
+			}
+			if err := tt.set(paper); err != nil {
+				t.Fatalf("设置条件单返回错误: %v", err)
+			}
+
+			price = tt.hit
+			if got := paperPositionAmt(t, paper, tt.side); !almostEqual(got, tt.remain) {
+				t.Errorf("触发后持仓 = %v, want %v", got, tt.remain)
+			}
+			// 再次轮询价格不应重复触发
+			if got := paperPositionAmt(t, paper, tt.side); !almostEqual(got, tt.remain) {
+				t.Errorf("再次轮询后持仓 = %v, want %v", got, tt.remain)
+			}
+		})
+	}
+}
+
+func TestPaperTraderFees(t *testing.T) {
+	price := 100.0
+	paper := newTestPaperTrader(10000, &price)
+
+	if _, err := paper.OpenLong("BTCUSDT", 10, 10); err != nil {
+		t.Fatalf("OpenLong 返回错误: %v", err)
+	}
+	if err := paper.SetStopLoss("BTCUSDT", "LONG", 5, 95); err != nil {
+		t.Fatalf("SetStopLoss 返回错误: %v", err)
+	}
+
+	// 跳空到94，止损按94成交
+	price = 94
+	balance, err := paper.GetBalance()
+	if err != nil {
+		t.Fatalf("GetBalance 返回错误: %v", err)
+	}
+
+	// 10000 - 开仓手续费0.5 + 止损亏损(94-100)×5 - 平仓手续费5×94×0.0005
+	want := 10000 - 0.5 - 30 - 0.235
+	if got := balance["totalWalletBalance"].(float64); !almostEqual(got, want) {
+		t.Errorf("totalWalletBalance = %v, want %v", got, want)
+	}
+	// 剩余5个多仓浮亏30
+	if got := balance["totalUnrealizedProfit"].(float64); !almostEqual(got, -30) {
+		t.Errorf("totalUnrealizedProfit = %v, want -30", got)
+	}
+}
+
+func TestPaperTraderLiquidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		isolated   bool
+		balance    float64
+		price      float64
+		liquidated bool
+	}{
+		// 逐仓10倍：保证金10，亏损达到10时强平
+		{"逐仓未到强平", true, 10000, 91, false},
+		{"逐仓强平", true, 10000, 89, true},
+		// 全仓：亏损达到账户余额时强平
+		{"全仓未到强平", false, 10000, 89, false},
+		{"全仓强平", false, 50, 49, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price := 100.0
+			paper := newTestPaperTrader(tt.balance, &price)
+			if err := paper.SetMarginMode("BTCUSDT", !tt.isolated); err != nil {
+				t.Fatalf("SetMarginMode 返回错误: %v", err)
+			}
+			if _, err := paper.OpenLong("BTCUSDT", 1, 10); err != nil {
+				t.Fatalf("OpenLong 返回错误: %v", err)
+			}
+
+			price = tt.price
+			got := paperPositionAmt(t, paper, "long")
+			if tt.liquidated && got != 0 {
+				t.Errorf("持仓 = %v, want 已强平", got)
+			}
+			if !tt.liquidated && !almostEqual(got, 1) {
+				t.Errorf("持仓 = %v, want 1", got)
+			}
+		})
+	}
+}
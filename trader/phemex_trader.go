@@ -0,0 +1,621 @@
+package trader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	phemexMainnetURL     = "https://api.phemex.com"
+	phemexTestnetURL     = "https://testnet-api.phemex.com"
+	phemexSettleCurrency = "USDT"
+	// phemexExpirySeconds 请求签名有效期
+	phemexExpirySeconds = 60
+)
+
+// PhemexTrader Phemex USDT本位永续合约交易器（双向持仓模式）
+// USDT合约接口的价格、数量、金额使用带后缀的十进制字符串（Rp=价格, Rq=数量, Rv=金额, Rr=比率），
+// 与旧合约的 Ep/Ev 缩放整数不同；字符串与float64的互转在内部完成，对外接口仍使用float64
+type PhemexTrader struct {
+	apiKey    string
+	secretKey string
+	rest      *restExchange
+
+	// 是否使用全仓（Phemex以负数杠杆表示全仓）
+	cross bool
+	// 已切换为双向持仓模式的合约
+	hedged map[string]bool
+
+	// 缓存合约精度信息
+	products map[string]SymbolPrecision
+	mu       sync.RWMutex
+}
+
+// phemexResponse Phemex 交易接口通用响应结构
+type phemexResponse struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+func init() {
+	Register("phemex", func(cfg AutoTraderConfig) (Trader, error) {
+		log.Printf("🏦 [%s] 使用Phemex合约交易", cfg.Name)
+		return NewPhemexTrader(cfg.PhemexAPIKey, cfg.PhemexSecretKey, cfg.PhemexTestnet), nil
+	})
+}
+
+// NewPhemexTrader 创建Phemex合约交易器
+// testnet为true时连接 testnet-api.phemex.com
+func NewPhemexTrader(apiKey, secretKey string, testnet bool) *PhemexTrader {
+	baseURL := phemexMainnetURL
+	if testnet {
+		baseURL = phemexTestnetURL
+	}
+
+	t := &PhemexTrader{
+		apiKey:    apiKey,
+		secretKey: secretKey,
+		cross:     true,
+		hedged:    make(map[string]bool),
+		products:  make(map[string]SymbolPrecision),
+	}
+	t.rest = newRESTExchange(restExchangeConfig{
+		Name:    "Phemex",
+		BaseURL: baseURL,
+		Signer:  t.sign,
+		Decoder: decodePhemex,
+	})
+	return t
+}
+
+// phemexFloat 解析Phemex的十进制字符串字段，空字符串视为0
+func phemexFloat(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
+
+// sign 签名钩子
+// 签名: hex(HMAC_SHA256(secret, path + queryString + expiry + body))，queryString不含'?'
+func (t *PhemexTrader) sign(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+	queryString := query.Encode()
+	expiry := strconv.FormatInt(time.Now().Unix()+phemexExpirySeconds, 10)
+	mac := hmac.New(sha256.New, []byte(t.secretKey))
+	mac.Write([]byte(path + queryString + expiry + string(body)))
+
+	header.Set("x-phemex-access-token", t.apiKey)
+	header.Set("x-phemex-request-expiry", expiry)
+	header.Set("x-phemex-request-signature", hex.EncodeToString(mac.Sum(nil)))
+	header.Set("Content-Type", "application/json")
+	return queryString, nil
+}
+
+// decodePhemex 校验状态码和code，返回完整响应（行情接口的数据在result字段中）
+func decodePhemex(statusCode int, body []byte) (json.RawMessage, error) {
+	var result phemexResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+	if result.Code != 0 {
+		return nil, fmt.Errorf("Phemex API错误 %d: %s", result.Code, result.Msg)
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+	return body, nil
+}
+
+// request 发送签名请求，返回data字段
+func (t *PhemexTrader) request(method, path string, query url.Values, payload map[string]interface{}) (json.RawMessage, error) {
+	var body interface{}
+	if payload != nil {
+		body = payload
+	}
+	data, err := t.rest.signedCall(method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result phemexResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	return result.Data, nil
+}
+
+// publicGet 请求无需签名的接口，返回原始响应
+func (t *PhemexTrader) publicGet(path string, params url.Values) ([]byte, error) {
+	return t.rest.publicCall("GET", path, params)
+}
+
+// getPrecision 获取合约精度（qtyStepSize / tickSize），首次调用时缓存全部USDT合约
+func (t *PhemexTrader) getPrecision(symbol string) (SymbolPrecision, error) {
+	t.mu.RLock()
+	if prec, ok := t.products[symbol]; ok {
+		t.mu.RUnlock()
+		return prec, nil
+	}
+	t.mu.RUnlock()
+
+	body, err := t.publicGet("/public/products", nil)
+	if err != nil {
+		return SymbolPrecision{}, fmt.Errorf("获取合约信息失败: %w", err)
+	}
+
+	var result struct {
+		Data struct {
+			PerpProductsV2 []struct {
+				Symbol      string `json:"symbol"`
+				QtyStepSize string `json:"qtyStepSize"`
+				TickSize    string `json:"tickSize"`
+			} `json:"perpProductsV2"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return SymbolPrecision{}, err
+	}
+
+	t.mu.Lock()
+	for _, p := range result.Data.PerpProductsV2 {
+		t.products[p.Symbol] = SymbolPrecision{
+			PricePrecision:    calculatePrecision(p.TickSize),
+			QuantityPrecision: calculatePrecision(p.QtyStepSize),
+			TickSize:          phemexFloat(p.TickSize),
+			StepSize:          phemexFloat(p.QtyStepSize),
+		}
+	}
+	prec, ok := t.products[symbol]
+	t.mu.Unlock()
+
+	if !ok {
+		return SymbolPrecision{}, fmt.Errorf("未找到合约 %s 的精度信息", symbol)
+	}
+	return prec, nil
+}
+
+// formatPrice 格式化价格到tickSize
+func (t *PhemexTrader) formatPrice(symbol string, price float64) (string, error) {
+	prec, err := t.getPrecision(symbol)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(roundToTickSize(price, prec.TickSize), 'f', prec.PricePrecision, 64), nil
+}
+
+// getAccountPositions 获取USDT账户和持仓（含未实现盈亏）
+func (t *PhemexTrader) getAccountPositions() (account map[string]string, positions []map[string]interface{}, err error) {
+	data, err := t.request("GET", "/g-accounts/positions", url.Values{"currency": {phemexSettleCurrency}}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result struct {
+		Account struct {
+			AccountBalanceRv   string `json:"accountBalanceRv"`
+			TotalUsedBalanceRv string `json:"totalUsedBalanceRv"`
+		} `json:"account"`
+		Positions []struct {
+			Symbol             string `json:"symbol"`
+			PosSide            string `json:"posSide"` // Long/Short/Merged
+			Side               string `json:"side"`    // Buy/Sell
+			SizeRq             string `json:"sizeRq"`
+			AvgEntryPriceRp    string `json:"avgEntryPriceRp"`
+			MarkPriceRp        string `json:"markPriceRp"`
+			UnRealisedPnlRv    string `json:"unRealisedPnlRv"`
+			LeverageRr         string `json:"leverageRr"`
+			PositionMarginRv   string `json:"positionMarginRv"`
+			LiquidationPriceRp string `json:"liquidationPriceRp"`
+		} `json:"positions"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, nil, err
+	}
+
+	account = map[string]string{
+		"balance": result.Account.AccountBalanceRv,
+		"used":    result.Account.TotalUsedBalanceRv,
+	}
+
+	for _, pos := range result.Positions {
+		size := phemexFloat(pos.SizeRq)
+		if size == 0 {
+			continue // 跳过空仓位
+		}
+
+		side := "long"
+		if pos.PosSide == "Short" || (pos.PosSide == "Merged" && pos.Side == "Sell") {
+			side = "short"
+		}
+
+		// 负数杠杆表示全仓，0表示全仓最大杠杆，此时按名义价值÷持仓保证金计算实际杠杆
+		markPrice := phemexFloat(pos.MarkPriceRp)
+		leverage := absFloat(phemexFloat(pos.LeverageRr))
+		if leverage == 0 {
+			leverage = 1
+			if margin := phemexFloat(pos.PositionMarginRv); margin > 0 {
+				leverage = math.Max(1, math.Round(absFloat(size)*markPrice/margin))
+			}
+		}
+
+		// 返回与Binance相同的字段名
+		positions = append(positions, map[string]interface{}{
+			"symbol":           pos.Symbol,
+			"side":             side,
+			"positionAmt":      absFloat(size),
+			"entryPrice":       phemexFloat(pos.AvgEntryPriceRp),
+			"markPrice":        markPrice,
+			"unRealizedProfit": phemexFloat(pos.UnRealisedPnlRv),
+			"leverage":         leverage,
+			"liquidationPrice": phemexFloat(pos.LiquidationPriceRp),
+		})
+	}
+
+	return account, positions, nil
+}
+
+// GetBalance 获取USDT合约账户余额
+func (t *PhemexTrader) GetBalance() (map[string]interface{}, error) {
+	account, positions, err := t.getAccountPositions()
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	unrealizedPnL := 0.0
+	for _, pos := range positions {
+		unrealizedPnL += pos["unRealizedProfit"].(float64)
+	}
+
+	balance := phemexFloat(account["balance"])
+
+	// 返回与Binance相同的字段名
+	return map[string]interface{}{
+		"totalWalletBalance":    balance,
+		"availableBalance":      balance - phemexFloat(account["used"]),
+		"totalUnrealizedProfit": unrealizedPnL,
+	}, nil
+}
+
+// GetPositions 获取持仓信息
+func (t *PhemexTrader) GetPositions() ([]map[string]interface{}, error) {
+	_, positions, err := t.getAccountPositions()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+	if positions == nil {
+		positions = []map[string]interface{}{}
+	}
+	return positions, nil
+}
+
+// ensureHedged 将合约切换为双向持仓模式
+// 只在切换成功后缓存，失败时（网络、鉴权错误等）记录日志，下次下单时重试
+func (t *PhemexTrader) ensureHedged(symbol string) {
+	t.mu.RLock()
+	done := t.hedged[symbol]
+	t.mu.RUnlock()
+	if done {
+		return
+	}
+
+	_, err := t.request("PUT", "/g-positions/switch-pos-mode-sync", url.Values{
+		"symbol":        {symbol},
+		"targetPosMode": {"Hedged"},
+	}, nil)
+	if err != nil {
+		log.Printf("  ⚠ 切换 %s 双向持仓模式失败: %v", symbol, err)
+		return
+	}
+
+	t.mu.Lock()
+	t.hedged[symbol] = true
+	t.mu.Unlock()
+}
+
+// placeOrder 提交订单（/g-orders），posSide为 Long/Short
+func (t *PhemexTrader) placeOrder(symbol, side, posSide string, quantity float64, extra map[string]interface{}) (map[string]interface{}, error) {
+	quantityStr, err := t.FormatQuantity(symbol, quantity)
+	if err != nil {
+		return nil, err
+	}
+	if phemexFloat(quantityStr) <= 0 {
+		return nil, fmt.Errorf("%s 数量 %.8f 低于最小下单量", symbol, quantity)
+	}
+
+	params := map[string]interface{}{
+		"clOrdID":     fmt.Sprintf("nofx%d", time.Now().UnixNano()),
+		"symbol":      symbol,
+		"side":        side,
+		"posSide":     posSide,
+		"orderQtyRq":  quantityStr,
+		"ordType":     "Market",
+		"timeInForce": "ImmediateOrCancel",
+	}
+	for k, v := range extra {
+		params[k] = v
+	}
+
+	data, err := t.request("POST", "/g-orders", nil, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var order struct {
+		OrderID   string `json:"orderID"`
+		OrdStatus string `json:"ordStatus"`
+	}
+	if err := json.Unmarshal(data, &order); err != nil {
+		return nil, err
+	}
+
+	log.Printf("  订单ID: %s", order.OrderID)
+	return map[string]interface{}{
+		"orderId": order.OrderID,
+		"symbol":  symbol,
+		"status":  order.OrdStatus,
+	}, nil
+}
+
+// OpenLong 开多单
+func (t *PhemexTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	t.ensureHedged(symbol)
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "Buy", "Long", quantity, nil)
+	if err != nil {
+		return nil, fmt.Errorf("开多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开多仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// OpenShort 开空单
+func (t *PhemexTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	// 先取消该币种的所有委托单（清理旧的止损止盈单）
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消旧委托单失败（可能没有委托单）: %v", err)
+	}
+
+	t.ensureHedged(symbol)
+	if err := t.SetLeverage(symbol, leverage); err != nil {
+		return nil, err
+	}
+
+	result, err := t.placeOrder(symbol, "Sell", "Short", quantity, nil)
+	if err != nil {
+		return nil, fmt.Errorf("开空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 开空仓成功: %s 数量: %.8f", symbol, quantity)
+	return result, nil
+}
+
+// CloseLong 平多单（quantity为0时平掉全部多仓）
+func (t *PhemexTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "long")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到多仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "Sell", "Long", quantity, map[string]interface{}{"reduceOnly": true})
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平多仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// CloseShort 平空单（quantity为0时平掉全部空仓）
+func (t *PhemexTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	if quantity == 0 {
+		var err error
+		quantity, err = t.positionQuantity(symbol, "short")
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("  📊 获取到空仓数量: %.8f", quantity)
+	}
+
+	result, err := t.placeOrder(symbol, "Buy", "Short", quantity, map[string]interface{}{"reduceOnly": true})
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+
+	log.Printf("✓ 平空仓成功: %s 数量: %.8f", symbol, quantity)
+
+	// 平仓后取消该币种的所有挂单(止损止盈单)
+	if err := t.CancelAllOrders(symbol); err != nil {
+		log.Printf("  ⚠ 取消挂单失败: %v", err)
+	}
+
+	return result, nil
+}
+
+// positionQuantity 获取指定方向的持仓数量
+func (t *PhemexTrader) positionQuantity(symbol, side string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			return pos["positionAmt"].(float64), nil
+		}
+	}
+
+	sideName := "多仓"
+	if side == "short" {
+		sideName = "空仓"
+	}
+	return 0, fmt.Errorf("没有找到 %s 的%s", symbol, sideName)
+}
+
+// SetMarginMode 设置仓位模式
+// Phemex通过杠杆符号区分全仓（负数）和逐仓（正数），这里只记录，下次设置杠杆时生效
+func (t *PhemexTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	t.mu.Lock()
+	t.cross = isCrossMargin
+	t.mu.Unlock()
+
+	modeName := "全仓"
+	if !isCrossMargin {
+		modeName = "逐仓"
+	}
+	log.Printf("  ✓ %s 仓位模式已设置为 %s", symbol, modeName)
+	return nil
+}
+
+// SetLeverage 设置杠杆倍数（双向持仓时多空同时设置）
+func (t *PhemexTrader) SetLeverage(symbol string, leverage int) error {
+	t.mu.RLock()
+	cross := t.cross
+	t.mu.RUnlock()
+
+	leverageRr := strconv.Itoa(leverage)
+	if cross {
+		leverageRr = "-" + leverageRr
+	}
+
+	_, err := t.request("PUT", "/g-positions/leverage", url.Values{
+		"symbol":          {symbol},
+		"longLeverageRr":  {leverageRr},
+		"shortLeverageRr": {leverageRr},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("设置杠杆失败: %w", err)
+	}
+
+	log.Printf("  ✓ %s 杠杆已切换为 %dx", symbol, leverage)
+	return nil
+}
+
+// GetMarketPrice 获取最新成交价（开启 market.SetUseMidPrice 时为买一卖一中间价）
+func (t *PhemexTrader) GetMarketPrice(symbol string) (float64, error) {
+	body, err := t.publicGet("/md/v3/ticker/24hr", url.Values{"symbol": {symbol}})
+	if err != nil {
+		return 0, fmt.Errorf("获取价格失败: %w", err)
+	}
+
+	var result struct {
+		Error  interface{} `json:"error"`
+		Result struct {
+			CloseRp string `json:"closeRp"`
+			BidRp   string `json:"bidRp"`
+			AskRp   string `json:"askRp"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, err
+	}
+	if result.Error != nil {
+		return 0, fmt.Errorf("获取价格失败: %v", result.Error)
+	}
+
+	price := phemexFloat(result.Result.CloseRp)
+	if price <= 0 {
+		return 0, fmt.Errorf("未找到 %s 的行情", symbol)
+	}
+	return pickMarketPrice(price, phemexFloat(result.Result.BidRp), phemexFloat(result.Result.AskRp)), nil
+}
+
+// placeTriggerOrder 下标记价格触发的平仓条件市价单
+// ordType: Stop(止损) / MarketIfTouched(止盈)
+func (t *PhemexTrader) placeTriggerOrder(symbol, positionSide, ordType string, quantity, triggerPrice float64) error {
+	side, posSide := "Sell", "Long"
+	if positionSide == "SHORT" {
+		side, posSide = "Buy", "Short"
+	}
+
+	priceStr, err := t.formatPrice(symbol, triggerPrice)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.placeOrder(symbol, side, posSide, quantity, map[string]interface{}{
+		"ordType":        ordType,
+		"stopPxRp":       priceStr,
+		"triggerType":    "ByMarkPrice",
+		"reduceOnly":     true,
+		"closeOnTrigger": true,
+		"timeInForce":    "GoodTillCancel",
+	})
+	return err
+}
+
+// SetStopLoss 设置止损
+func (t *PhemexTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, "Stop", quantity, stopPrice); err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+
+	log.Printf("  止损价设置: %.4f", stopPrice)
+	return nil
+}
+
+// SetTakeProfit 设置止盈
+func (t *PhemexTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	if err := t.placeTriggerOrder(symbol, positionSide, "MarketIfTouched", quantity, takeProfitPrice); err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+
+	log.Printf("  止盈价设置: %.4f", takeProfitPrice)
+	return nil
+}
+
+// CancelAllOrders 取消该合约的所有普通挂单和未触发的条件单
+func (t *PhemexTrader) CancelAllOrders(symbol string) error {
+	for _, untriggered := range []string{"false", "true"} {
+		_, err := t.request("DELETE", "/g-orders/all", url.Values{
+			"symbol":      {symbol},
+			"untriggered": {untriggered},
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("取消挂单失败: %w", err)
+		}
+	}
+
+	log.Printf("  ✓ 已取消 %s 的所有挂单", symbol)
+	return nil
+}
+
+// FormatQuantity 将数量向下取整到qtyStepSize（实现Trader接口）
+func (t *PhemexTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	prec, err := t.getPrecision(symbol)
+	if err != nil {
+		return "", err
+	}
+
+	if prec.StepSize > 0 {
+		quantity = math.Floor(quantity/prec.StepSize+1e-9) * prec.StepSize
+	}
+	return strconv.FormatFloat(quantity, 'f', prec.QuantityPrecision, 64), nil
+}
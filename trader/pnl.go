@@ -0,0 +1,148 @@
+package trader
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GetCurrentPnL 按当前市场价格重新计算指定币种的未实现盈亏
+// 交易所返回的未实现盈亏可能存在延迟，这里使用最新价格独立计算：
+// 多仓 = (当前价 - 开仓价) × 数量，空仓取反
+// 没有持仓时返回 0, nil
+func GetCurrentPnL(t Trader, symbol string) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	var matched []map[string]interface{}
+	for _, pos := range positions {
+		if pos["symbol"] == symbol {
+			matched = append(matched, pos)
+		}
+	}
+
+	if len(matched) == 0 {
+		return 0, nil
+	}
+
+	markPrice, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return 0, fmt.Errorf("获取 %s 价格失败: %w", symbol, err)
+	}
+
+	totalPnL := 0.0
+	for _, pos := range matched {
+		totalPnL += calculatePositionPnL(pos, markPrice)
+	}
+
+	return totalPnL, nil
+}
+
+// GetTotalUnrealizedPnL 按当前市场价格汇总所有持仓的未实现盈亏
+// 没有持仓时返回 0, nil
+func GetTotalUnrealizedPnL(t Trader) (float64, error) {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return 0, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	// 同一币种只查询一次价格
+	prices := make(map[string]float64)
+	totalPnL := 0.0
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		if symbol == "" {
+			continue
+		}
+
+		markPrice, ok := prices[symbol]
+		if !ok {
+			markPrice, err = t.GetMarketPrice(symbol)
+			if err != nil {
+				return 0, fmt.Errorf("获取 %s 价格失败: %w", symbol, err)
+			}
+			prices[symbol] = markPrice
+		}
+
+		totalPnL += calculatePositionPnL(pos, markPrice)
+	}
+
+	return totalPnL, nil
+}
+
+// calculatePositionPnL 计算单个持仓在给定价格下的未实现盈亏
+func calculatePositionPnL(pos map[string]interface{}, markPrice float64) float64 {
+	entryPrice, _ := pos["entryPrice"].(float64)
+	quantity, _ := pos["positionAmt"].(float64)
+	quantity = absFloat(quantity) // 币安空仓数量为负数，统一取绝对值
+
+	pnl := (markPrice - entryPrice) * quantity
+	if pos["side"] == "short" {
+		pnl = -pnl
+	}
+	return pnl
+}
+
+// PnLAttribution 持仓盈亏归因
+type PnLAttribution struct {
+	PriceMovePnL float64 // 价格变动盈亏
+	FundingPnL   float64 // 资金费（正数为净收取）
+	FeePnL       float64 // 手续费（负数，按开仓吃单费率估算）
+	TotalPnL     float64 // 合计
+	FundingPct   float64 // 资金费占合计绝对值的百分比
+	FeePct       float64 // 手续费占合计绝对值的百分比
+}
+
+// GetPnLAttribution 将持仓盈亏拆分为价格变动、资金费和手续费三部分
+// Trader接口不提供成交记录，手续费按开仓名义价值 × 吃单费率估算
+func GetPnLAttribution(t Trader, symbol, positionSide string, openTime time.Time) (*PnLAttribution, error) {
+	side := strings.ToLower(positionSide)
+	if side != "long" && side != "short" {
+		return nil, fmt.Errorf("无效的持仓方向: %s", positionSide)
+	}
+
+	positions, err := t.GetPositions()
+	if err != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	var position map[string]interface{}
+	for _, pos := range positions {
+		if pos["symbol"] == symbol && pos["side"] == side {
+			position = pos
+			break
+		}
+	}
+	if position == nil {
+		return nil, fmt.Errorf("没有 %s 的%s持仓", symbol, side)
+	}
+
+	markPrice, err := t.GetMarketPrice(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("获取 %s 价格失败: %w", symbol, err)
+	}
+
+	funding, err := GetCumulativeFundingCost(t, symbol, side, openTime)
+	if err != nil {
+		return nil, err
+	}
+
+	entryPrice, _ := position["entryPrice"].(float64)
+	quantity, _ := position["positionAmt"].(float64)
+
+	attribution := &PnLAttribution{
+		PriceMovePnL: calculatePositionPnL(position, markPrice),
+		FundingPnL:   funding.Net,
+		FeePnL:       -absFloat(quantity) * entryPrice * estimatedTakerFeePct / 100,
+	}
+	attribution.TotalPnL = attribution.PriceMovePnL + attribution.FundingPnL + attribution.FeePnL
+
+	if total := absFloat(attribution.TotalPnL); total > 0 {
+		attribution.FundingPct = attribution.FundingPnL / total * 100
+		attribution.FeePct = attribution.FeePnL / total * 100
+	}
+
+	return attribution, nil
+}
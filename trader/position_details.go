@@ -0,0 +1,74 @@
+package trader
+
+import (
+	"fmt"
+	"nofx/market"
+)
+
+// defaultMaintenanceMarginRate 估算强平价使用的维持保证金率（USDT合约最低档位）
+const defaultMaintenanceMarginRate = 0.004
+
+// PositionDetails 开仓前的仓位明细
+// USDT本位合约1张即1个币，数量直接对应合约面值
+type PositionDetails struct {
+	Symbol                string
+	Quantity              float64 // 开仓数量（币）
+	Leverage              int
+	MarkPrice             float64 // 当前标记价格
+	NotionalUSDT          float64 // 名义价值 = 数量 × 标记价格
+	RequiredMargin        float64 // 所需保证金 = 名义价值 / 杠杆
+	LongLiquidationPrice  float64 // 以标记价格开多时的估算强平价（逐仓）
+	ShortLiquidationPrice float64 // 以标记价格开空时的估算强平价（逐仓）
+	FundingRate           float64 // 当前预估资金费率
+	FundingCostPerDay     float64 // 按当前费率估算的每日资金费（正数为多头支付）
+}
+
+// ComputePositionDetails 计算指定数量和杠杆下的名义价值、保证金、强平价和每日资金费
+func ComputePositionDetails(symbol string, quantity float64, leverage int) (*PositionDetails, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("开仓数量必须大于0: %.8f", quantity)
+	}
+	if leverage <= 0 {
+		return nil, fmt.Errorf("杠杆倍数必须大于0: %d", leverage)
+	}
+
+	info, err := market.GetFundingInfo(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("获取标记价格失败: %w", err)
+	}
+
+	fundingPerYear, err := market.GetFundingPerYear(symbol)
+	if err != nil {
+		return nil, fmt.Errorf("获取资金费结算频率失败: %w", err)
+	}
+
+	notional := quantity * info.MarkPrice
+	details := &PositionDetails{
+		Symbol:         info.Symbol,
+		Quantity:       quantity,
+		Leverage:       leverage,
+		MarkPrice:      info.MarkPrice,
+		NotionalUSDT:   notional,
+		RequiredMargin: notional / float64(leverage),
+		FundingRate:    info.FundingRate,
+		// 每日资金费 = 费率 × 名义价值 × 每日结算次数
+		FundingCostPerDay: info.FundingRate * notional * float64(fundingPerYear) / 365,
+	}
+	details.LongLiquidationPrice = ComputeLiquidationPrice(info.MarkPrice, leverage, "long", defaultMaintenanceMarginRate)
+	details.ShortLiquidationPrice = ComputeLiquidationPrice(info.MarkPrice, leverage, "short", defaultMaintenanceMarginRate)
+
+	return details, nil
+}
+
+// ComputeLiquidationPrice 估算逐仓模式下的强平价格
+// 多头：开仓价 × (1 - 1/杠杆 + 维持保证金率)；空头：开仓价 × (1 + 1/杠杆 - 维持保证金率)
+func ComputeLiquidationPrice(entryPrice float64, leverage int, side string, maintenanceMarginRate float64) float64 {
+	if entryPrice <= 0 || leverage <= 0 {
+		return 0
+	}
+
+	if side == "short" {
+		return entryPrice * (1 + 1/float64(leverage) - maintenanceMarginRate)
+	}
+	return entryPrice * (1 - 1/float64(leverage) + maintenanceMarginRate)
+}
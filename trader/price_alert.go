@@ -0,0 +1,190 @@
+package trader
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// AlertDirection 价格提醒的触发方向
+type AlertDirection string
+
+const (
+	CrossAbove AlertDirection = "cross_above" // 价格由下向上穿越阈值
+	CrossBelow AlertDirection = "cross_below" // 价格由上向下穿越阈值
+)
+
+// Alert 价格提醒
+type Alert struct {
+	ID             string
+	Symbol         string
+	Threshold      float64
+	Direction      AlertDirection
+	TriggeredPrice float64   // 最近一次触发时的价格
+	TriggeredAt    time.Time // 最近一次触发时间
+}
+
+// priceAlert 内部提醒状态
+type priceAlert struct {
+	alert     Alert
+	handler   func(Alert)
+	lastPrice float64 // 上一次轮询的价格，0表示尚未取得
+}
+
+// PriceAlertManager 轮询市场价格并在价格穿越阈值时回调（边沿触发：每次穿越只触发一次）
+type PriceAlertManager struct {
+	trader    Trader
+	mu        sync.Mutex
+	alerts    map[string]*priceAlert
+	nextID    int
+	isRunning bool
+	stopCh    chan struct{}
+}
+
+// NewPriceAlertManager 创建价格提醒管理器
+func NewPriceAlertManager(t Trader) *PriceAlertManager {
+	return &PriceAlertManager{
+		trader: t,
+		alerts: make(map[string]*priceAlert),
+	}
+}
+
+// AddAlert 添加价格提醒，返回提醒ID
+// 添加后的第一次轮询只记录价格，之后价格从阈值一侧穿越到另一侧时触发handler
+func (m *PriceAlertManager) AddAlert(symbol string, threshold float64, direction AlertDirection, handler func(Alert)) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	id := fmt.Sprintf("alert-%d", m.nextID)
+	m.alerts[id] = &priceAlert{
+		alert: Alert{
+			ID:        id,
+			Symbol:    symbol,
+			Threshold: threshold,
+			Direction: direction,
+		},
+		handler: handler,
+	}
+	return id
+}
+
+// RemoveAlert 移除价格提醒
+func (m *PriceAlertManager) RemoveAlert(alertID string) {
+	m.mu.Lock()
+	delete(m.alerts, alertID)
+	m.mu.Unlock()
+}
+
+// GetActiveAlerts 获取所有生效中的价格提醒
+func (m *PriceAlertManager) GetActiveAlerts() []Alert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	alerts := make([]Alert, 0, len(m.alerts))
+	for _, a := range m.alerts {
+		alerts = append(alerts, a.alert)
+	}
+	return alerts
+}
+
+// Monitor 启动后台轮询，每隔pollInterval获取一次所有监控币种的价格
+func (m *PriceAlertManager) Monitor(pollInterval time.Duration) {
+	m.mu.Lock()
+	if m.isRunning {
+		m.mu.Unlock()
+		return
+	}
+	m.isRunning = true
+	m.stopCh = make(chan struct{})
+	stopCh := m.stopCh
+	m.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				m.checkAlerts()
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止后台轮询
+func (m *PriceAlertManager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.isRunning {
+		return
+	}
+	m.isRunning = false
+	close(m.stopCh)
+}
+
+// checkAlerts 获取价格并检查所有提醒，handler在锁外调用
+func (m *PriceAlertManager) checkAlerts() {
+	m.mu.Lock()
+	symbols := make(map[string]bool)
+	for _, a := range m.alerts {
+		symbols[a.alert.Symbol] = true
+	}
+	m.mu.Unlock()
+
+	prices := make(map[string]float64)
+	for symbol := range symbols {
+		price, err := m.trader.GetMarketPrice(symbol)
+		if err != nil || price <= 0 {
+			log.Printf("⚠️  价格提醒获取 %s 价格失败: %v", symbol, err)
+			continue
+		}
+		prices[symbol] = price
+	}
+
+	type firedAlert struct {
+		alert   Alert
+		handler func(Alert)
+	}
+	var fired []firedAlert
+
+	m.mu.Lock()
+	for _, a := range m.alerts {
+		price, ok := prices[a.alert.Symbol]
+		if !ok {
+			continue
+		}
+
+		prev := a.lastPrice
+		a.lastPrice = price
+		if prev == 0 {
+			continue
+		}
+
+		crossed := false
+		switch a.alert.Direction {
+		case CrossAbove:
+			crossed = prev < a.alert.Threshold && price >= a.alert.Threshold
+		case CrossBelow:
+			crossed = prev > a.alert.Threshold && price <= a.alert.Threshold
+		}
+		if crossed {
+			a.alert.TriggeredPrice = price
+			a.alert.TriggeredAt = time.Now()
+			fired = append(fired, firedAlert{alert: a.alert, handler: a.handler})
+		}
+	}
+	m.mu.Unlock()
+
+	for _, f := range fired {
+		log.Printf("🔔 价格提醒触发: %s %s %.4f (当前 %.4f)", f.alert.Symbol, f.alert.Direction, f.alert.Threshold, f.alert.TriggeredPrice)
+		if f.handler != nil {
+			f.handler(f.alert)
+		}
+	}
+}
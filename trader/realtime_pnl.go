@@ -0,0 +1,351 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"nofx/market"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// realtimePnLStaleAfter 标记价格超过该时间未更新视为过期（正常每秒推送一次）
+const realtimePnLStaleAfter = 10 * time.Second
+
+// RealTimePosition 实时盈亏计算使用的本地持仓
+type RealTimePosition struct {
+	Symbol     string
+	Side       string // long/short
+	Quantity   float64
+	EntryPrice float64
+
+	// ContractValue 每单位数量对应的币数量，按张下单的交易所（KuCoin、HTX、BitMEX等）为合约面值，为0时按1计算
+	ContractValue float64
+}
+
+// PnLUpdate 盈亏变化通知
+type PnLUpdate struct {
+	Symbol    string
+	Side      string
+	MarkPrice float64
+	PnL       float64
+	Change    float64 // 相对上次通知的变化
+}
+
+// pnlListener 盈亏变化监听器
+type pnlListener struct {
+	threshold    float64
+	handler      func(PnLUpdate)
+	lastNotified map[string]float64 // symbol_side -> 上次通知时的盈亏
+}
+
+// RealTimePnL 基于WebSocket标记价格推送在内存中计算持仓盈亏，查询时无需网络请求
+type RealTimePnL struct {
+	mu         sync.RWMutex
+	positions  map[string]RealTimePosition // symbol_side -> 持仓
+	markPrices map[string]float64          // symbol -> 标记价格
+	markTimes  map[string]time.Time        // symbol -> 标记价格更新时间
+	listeners  []*pnlListener
+	client     *market.CombinedStreamsClient
+	subscribed map[string]bool
+}
+
+// NewRealTimePnL 创建实时盈亏计算器
+func NewRealTimePnL() *RealTimePnL {
+	return &RealTimePnL{
+		positions:  make(map[string]RealTimePosition),
+		markPrices: make(map[string]float64),
+		markTimes:  make(map[string]time.Time),
+		subscribed: make(map[string]bool),
+	}
+}
+
+// SeedFromTrader 使用交易器当前持仓初始化本地持仓
+func (r *RealTimePnL) SeedFromTrader(t Trader) error {
+	positions, err := t.GetPositions()
+	if err != nil {
+		return fmt.Errorf("获取持仓失败: %w", err)
+	}
+
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		side, _ := pos["side"].(string)
+		quantity, _ := pos["positionAmt"].(float64)
+		entryPrice, _ := pos["entryPrice"].(float64)
+		markPrice, _ := pos["markPrice"].(float64)
+
+		r.UpdatePosition(RealTimePosition{
+			Symbol:     symbol,
+			Side:       side,
+			Quantity:   absFloat(quantity),
+			EntryPrice: entryPrice,
+		})
+		if markPrice > 0 {
+			r.mu.Lock()
+			r.setMarkPriceLocked(symbol, markPrice)
+			r.mu.Unlock()
+		}
+	}
+	return nil
+}
+
+// UpdatePosition 更新本地持仓；已启动时自动订阅新币种
+// 数量为0时移除持仓，该币种没有其他持仓时注销处理器并取消订阅标记价格
+func (r *RealTimePnL) UpdatePosition(p RealTimePosition) {
+	r.mu.Lock()
+	key := p.Symbol + "_" + p.Side
+	if p.Quantity <= 0 {
+		delete(r.positions, key)
+		r.mu.Unlock()
+		r.unsubscribe(p.Symbol)
+		return
+	}
+	r.positions[key] = p
+	started := r.client != nil
+	r.mu.Unlock()
+
+	if started {
+		if err := r.subscribe([]string{p.Symbol}); err != nil {
+			log.Printf("⚠️  订阅 %s 标记价格失败: %v", p.Symbol, err)
+		}
+	}
+}
+
+// Start 连接WebSocket并订阅所有持仓币种的标记价格，ctx取消时自动停止（等同于调用 Stop）
+func (r *RealTimePnL) Start(ctx context.Context) error {
+	client := market.NewCombinedStreamsClient(50)
+	if err := client.Connect(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.client = client
+	symbols := r.heldSymbolsLocked()
+	r.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		r.stopClient(client)
+	}()
+
+	return r.subscribe(symbols)
+}
+
+// Stop 关闭WebSocket连接
+func (r *RealTimePnL) Stop() {
+	r.mu.RLock()
+	client := r.client
+	r.mu.RUnlock()
+
+	r.stopClient(client)
+}
+
+// stopClient 关闭指定的WebSocket连接，已停止或已重新启动时忽略
+func (r *RealTimePnL) stopClient(client *market.CombinedStreamsClient) {
+	r.mu.Lock()
+	if client == nil || r.client != client {
+		r.mu.Unlock()
+		return
+	}
+	r.client = nil
+	r.subscribed = make(map[string]bool)
+	r.mu.Unlock()
+
+	client.Close()
+}
+
+// GetPnL 获取指定持仓的未实现盈亏，没有持仓、尚无标记价格或标记价格已过期时返回false，
+// 调用方应改用交易器的 GetPositions 查询
+func (r *RealTimePnL) GetPnL(symbol, side string) (float64, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pos, exists := r.positions[symbol+"_"+side]
+	if !exists {
+		return 0, false
+	}
+	markPrice, err := r.markPriceLocked(symbol)
+	if err != nil {
+		return 0, false
+	}
+	return positionPnL(pos, markPrice), true
+}
+
+// GetTotalPnL 汇总所有持仓盈亏，没有标记价格或标记价格已过期（如WebSocket断线）的持仓不计入，
+// 调用方可通过 StaleSymbols 判断结果是否完整
+func (r *RealTimePnL) GetTotalPnL() float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	total := 0.0
+	for _, pos := range r.positions {
+		if markPrice, err := r.markPriceLocked(pos.Symbol); err == nil {
+			total += positionPnL(pos, markPrice)
+		}
+	}
+	return total
+}
+
+// StaleSymbols 返回没有标记价格或标记价格已过期的持仓币种，为空时 GetTotalPnL 的结果完整
+func (r *RealTimePnL) StaleSymbols() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var stale []string
+	for _, symbol := range r.heldSymbolsLocked() {
+		if _, err := r.markPriceLocked(symbol); err != nil {
+			stale = append(stale, symbol)
+		}
+	}
+	return stale
+}
+
+// setMarkPriceLocked 记录标记价格和更新时间（调用方需持有写锁）
+func (r *RealTimePnL) setMarkPriceLocked(symbol string, markPrice float64) {
+	r.markPrices[symbol] = markPrice
+	r.markTimes[symbol] = time.Now()
+}
+
+// markPriceLocked 获取未过期的标记价格（调用方需持有锁）
+func (r *RealTimePnL) markPriceLocked(symbol string) (float64, error) {
+	markPrice, ok := r.markPrices[symbol]
+	if !ok {
+		return 0, fmt.Errorf("%s 尚无标记价格", symbol)
+	}
+	if age := time.Since(r.markTimes[symbol]); age > realtimePnLStaleAfter {
+		return 0, fmt.Errorf("%s 标记价格已过期（%v未更新）", symbol, age.Truncate(time.Second))
+	}
+	return markPrice, nil
+}
+
+// OnPnLChange 注册盈亏变化回调，单个持仓盈亏相对上次通知变化超过threshold时触发
+// 回调在WebSocket读取协程中执行，耗时操作应自行转到其他协程
+func (r *RealTimePnL) OnPnLChange(threshold float64, handler func(PnLUpdate)) {
+	r.mu.Lock()
+	r.listeners = append(r.listeners, &pnlListener{
+		threshold:    threshold,
+		handler:      handler,
+		lastNotified: make(map[string]float64),
+	})
+	r.mu.Unlock()
+}
+
+// subscribe 订阅尚未订阅的币种并启动消息处理
+// 订阅成功后才标记为已订阅，失败的币种在下次 UpdatePosition 时会重新订阅
+func (r *RealTimePnL) subscribe(symbols []string) error {
+	r.mu.RLock()
+	client := r.client
+	var newSymbols []string
+	for _, symbol := range symbols {
+		if !r.subscribed[symbol] {
+			newSymbols = append(newSymbols, symbol)
+		}
+	}
+	r.mu.RUnlock()
+
+	if client == nil || len(newSymbols) == 0 {
+		return nil
+	}
+
+	if err := client.BatchSubscribeMarkPrice(newSymbols); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client != client {
+		return nil // 订阅期间已停止或重新启动
+	}
+	for _, symbol := range newSymbols {
+		// 并发订阅同一币种时只注册一次
+		if r.subscribed[symbol] {
+			continue
+		}
+		r.subscribed[symbol] = true
+		market.RegisterHandler(client.Registry(), market.MarkPriceStream(symbol), r.onMarkPrice)
+	}
+	return nil
+}
+
+// unsubscribe 币种没有持仓时注销处理器并取消订阅标记价格
+func (r *RealTimePnL) unsubscribe(symbol string) {
+	r.mu.Lock()
+	client := r.client
+	if client == nil || !r.subscribed[symbol] {
+		r.mu.Unlock()
+		return
+	}
+	for _, pos := range r.positions {
+		if pos.Symbol == symbol {
+			r.mu.Unlock()
+			return // 另一方向仍有持仓
+		}
+	}
+	delete(r.subscribed, symbol)
+	client.Registry().UnregisterHandler(market.MarkPriceStream(symbol))
+	r.mu.Unlock()
+
+	if err := client.BatchUnsubscribeMarkPrice([]string{symbol}); err != nil {
+		log.Printf("⚠️  取消订阅 %s 标记价格失败: %v", symbol, err)
+	}
+}
+
+// onMarkPrice 处理标记价格推送，更新价格并通知监听器
+func (r *RealTimePnL) onMarkPrice(msg market.MarkPriceWSData) {
+	markPrice, err := strconv.ParseFloat(msg.MarkPrice, 64)
+	if err != nil || markPrice <= 0 {
+		return
+	}
+
+	var updates []func()
+	r.mu.Lock()
+	r.setMarkPriceLocked(msg.Symbol, markPrice)
+	for key, pos := range r.positions {
+		if pos.Symbol != msg.Symbol {
+			continue
+		}
+		pnl := positionPnL(pos, markPrice)
+		for _, l := range r.listeners {
+			change := pnl - l.lastNotified[key]
+			if absFloat(change) < l.threshold {
+				continue
+			}
+			l.lastNotified[key] = pnl
+			update := PnLUpdate{Symbol: pos.Symbol, Side: pos.Side, MarkPrice: markPrice, PnL: pnl, Change: change}
+			handler := l.handler
+			updates = append(updates, func() { handler(update) })
+		}
+	}
+	r.mu.Unlock()
+
+	for _, notify := range updates {
+		notify()
+	}
+}
+
+// heldSymbolsLocked 获取持仓币种（调用方需持有锁）
+func (r *RealTimePnL) heldSymbolsLocked() []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, pos := range r.positions {
+		if !seen[pos.Symbol] {
+			seen[pos.Symbol] = true
+			symbols = append(symbols, pos.Symbol)
+		}
+	}
+	return symbols
+}
+
+// positionPnL 计算本地持仓在给定标记价格下的盈亏
+func positionPnL(pos RealTimePosition, markPrice float64) float64 {
+	contractValue := pos.ContractValue
+	if contractValue <= 0 {
+		contractValue = 1
+	}
+	pnl := (markPrice - pos.EntryPrice) * pos.Quantity * contractValue
+	if pos.Side == "short" {
+		pnl = -pnl
+	}
+	return pnl
+}
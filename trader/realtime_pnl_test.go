@@ -0,0 +1,105 @@
+package trader
+
+import (
+	"nofx/market"
+	"testing"
+	"time"
+)
+
+// positionsStub 只实现 GetPositions 的测试交易器
+type positionsStub struct {
+	Trader
+	positions []map[string]interface{}
+}
+
+func (s *positionsStub) GetPositions() ([]map[string]interface{}, error) {
+	return s.positions, nil
+}
+
+func TestRealTimePnLStaleMarkPrice(t *testing.T) {
+	r := NewRealTimePnL()
+	err := r.SeedFromTrader(&positionsStub{positions: []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "positionAmt": 0.5, "entryPrice": 60000.0, "markPrice": 61000.0},
+		{"symbol": "ETHUSDT", "side": "short", "positionAmt": -2.0, "entryPrice": 3000.0, "markPrice": 2900.0},
+	}})
+	if err != nil {
+		t.Fatalf("SeedFromTrader 返回错误: %v", err)
+	}
+
+	if pnl, ok := r.GetPnL("BTCUSDT", "long"); !ok || !almostEqual(pnl, 500) {
+		t.Errorf("GetPnL(BTCUSDT long) = %v, %v, want 500", pnl, ok)
+	}
+	if total := r.GetTotalPnL(); !almostEqual(total, 700) {
+		t.Errorf("GetTotalPnL = %v, want 700", total)
+	}
+	if stale := r.StaleSymbols(); len(stale) != 0 {
+		t.Errorf("StaleSymbols = %v, want 空", stale)
+	}
+
+	// 模拟断线：ETH 的标记价格长时间未更新
+	r.mu.Lock()
+	r.markTimes["ETHUSDT"] = time.Now().Add(-2 * realtimePnLStaleAfter)
+	r.mu.Unlock()
+
+	if _, ok := r.GetPnL("ETHUSDT", "short"); ok {
+		t.Error("标记价格过期时 GetPnL 应返回false")
+	}
+	if _, ok := r.GetPnL("BTCUSDT", "long"); !ok {
+		t.Error("未过期的标记价格不应受影响")
+	}
+	if total := r.GetTotalPnL(); !almostEqual(total, 500) {
+		t.Errorf("GetTotalPnL = %v, want 500（不计入过期的ETH）", total)
+	}
+	if stale := r.StaleSymbols(); len(stale) != 1 || stale[0] != "ETHUSDT" {
+		t.Errorf("StaleSymbols = %v, want [ETHUSDT]", stale)
+	}
+}
+
+func TestRealTimePnLContractValue(t *testing.T) {
+	r := NewRealTimePnL()
+	// 按张计价：每张0.001个币
+	r.UpdatePosition(RealTimePosition{Symbol: "BTCUSDT", Side: "short", Quantity: 100, EntryPrice: 60000, ContractValue: 0.001})
+	r.mu.Lock()
+	r.setMarkPriceLocked("BTCUSDT", 59000)
+	r.mu.Unlock()
+
+	if pnl, ok := r.GetPnL("BTCUSDT", "short"); !ok || !almostEqual(pnl, 100) {
+		t.Errorf("GetPnL = %v, %v, want 100", pnl, ok)
+	}
+}
+
+func TestRealTimePnLClosePositionUnregisters(t *testing.T) {
+	r := NewRealTimePnL()
+	r.client = market.NewCombinedStreamsClient(50)
+	r.positions["BTCUSDT_long"] = RealTimePosition{Symbol: "BTCUSDT", Side: "long", Quantity: 1, EntryPrice: 60000}
+	r.positions["BTCUSDT_short"] = RealTimePosition{Symbol: "BTCUSDT", Side: "short", Quantity: 1, EntryPrice: 60000}
+	r.subscribed["BTCUSDT"] = true
+	market.RegisterHandler(r.client.Registry(), market.MarkPriceStream("BTCUSDT"), r.onMarkPrice)
+
+	// 另一方向仍有持仓时保持订阅
+	r.UpdatePosition(RealTimePosition{Symbol: "BTCUSDT", Side: "long"})
+	if !r.subscribed["BTCUSDT"] || len(r.client.Registry().ListRegisteredChannels()) != 1 {
+		t.Fatal("仍有空仓时不应取消订阅")
+	}
+
+	r.UpdatePosition(RealTimePosition{Symbol: "BTCUSDT", Side: "short"})
+	if r.subscribed["BTCUSDT"] {
+		t.Error("全部平仓后应取消订阅标记")
+	}
+	if channels := r.client.Registry().ListRegisteredChannels(); len(channels) != 0 {
+		t.Errorf("全部平仓后仍注册了处理器: %v", channels)
+	}
+}
+
+func TestRealTimePnLSubscribeFailureNotMarked(t *testing.T) {
+	r := NewRealTimePnL()
+	// 未连接的客户端订阅必然失败
+	r.client = market.NewCombinedStreamsClient(50)
+
+	if err := r.subscribe([]string{"BTCUSDT"}); err == nil {
+		t.Fatal("未连接时订阅应返回错误")
+	}
+	if r.subscribed["BTCUSDT"] {
+		t.Error("订阅失败的币种不应标记为已订阅，否则不会重试")
+	}
+}
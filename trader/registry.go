@@ -0,0 +1,55 @@
+package trader
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory 根据配置创建交易器
+type Factory func(cfg AutoTraderConfig) (Trader, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register 注册交易所对应的交易器工厂，各交易器在自己文件的 init() 中调用
+// 重复注册同一名称视为编程错误，直接panic
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("trader: Register factory为nil: " + name)
+	}
+	if _, exists := factories[name]; exists {
+		panic("trader: 重复注册交易所: " + name)
+	}
+	factories[name] = factory
+}
+
+// New 按交易所名称创建交易器（如 "binance"、"bybit"）
+func New(name string, cfg AutoTraderConfig) (Trader, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("不支持的交易平台: %s", name)
+	}
+	return factory(cfg)
+}
+
+// Registered 返回已注册的交易所名称（按字母排序）
+func Registered() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
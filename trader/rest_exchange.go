@@ -0,0 +1,297 @@
+package trader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"nofx/market"
+	"strconv"
+	"time"
+)
+
+// restSigner 签名钩子
+// 可以向header写入认证信息，返回最终使用的querystring（不含'?'），便于需要把签名追加在参数末尾的交易所
+type restSigner func(method, path string, query url.Values, body []byte, header http.Header) (string, error)
+
+// restDecoder 响应解码钩子：校验交易所的响应包装（code/msg等），返回业务数据
+type restDecoder func(statusCode int, body []byte) (json.RawMessage, error)
+
+// restSymbolMapper 交易对转换：BTCUSDT <-> 交易所合约代码
+type restSymbolMapper struct {
+	toExchange   func(symbol string) string
+	fromExchange func(symbol string) string
+}
+
+// restExchangeConfig 创建restExchange的配置
+type restExchangeConfig struct {
+	Name      string            // 交易所名称，用于日志和错误信息
+	BaseURL   string            // 接口地址
+	Endpoints map[string]string // 接口表：逻辑名称 -> 路径
+	Signer    restSigner        // 签名钩子，私有接口使用
+	Decoder   restDecoder       // 响应解码钩子
+	Symbols   restSymbolMapper  // 交易对转换，为空时原样使用
+
+	MaxRetries int           // 最大重试次数，默认2
+	RetryDelay time.Duration // 首次重试等待时间，之后翻倍，默认500ms
+	Retryable  restRetryable // 判断失败的请求能否重试，为空时使用 defaultRetryable
+
+	Client *http.Client // HTTP客户端，为空时使用30秒超时的默认客户端
+	Logger *log.Logger  // 重试日志，为空时使用标准库默认logger
+}
+
+// restRetryable 重试判断钩子，statusCode为0表示网络错误
+type restRetryable func(method, endpoint string, statusCode int) bool
+
+// defaultRetryable 默认重试规则：GET在网络错误、429和5xx时重试；其他方法只在429时重试，避免重复下单
+func defaultRetryable(method, endpoint string, statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests ||
+		(method == "GET" && (statusCode == 0 || statusCode >= 500))
+}
+
+// restExchange 通用REST交易所客户端
+// 统一处理HTTP客户端、接口表、签名、响应解码、重试和数量/价格格式化，
+// 具体交易所只需提供签名和解码钩子以及Trader接口的业务逻辑
+type restExchange struct {
+	name      string
+	baseURL   string
+	endpoints map[string]string
+	signer    restSigner
+	decoder   restDecoder
+	symbols   restSymbolMapper
+	client    *http.Client
+
+	maxRetries int
+	retryDelay time.Duration
+	retryable  restRetryable
+	logger     *log.Logger
+}
+
+// newRESTExchange 创建通用REST交易所客户端
+func newRESTExchange(cfg restExchangeConfig) *restExchange {
+	ex := &restExchange{
+		name:       cfg.Name,
+		baseURL:    cfg.BaseURL,
+		endpoints:  cfg.Endpoints,
+		signer:     cfg.Signer,
+		decoder:    cfg.Decoder,
+		symbols:    cfg.Symbols,
+		maxRetries: cfg.MaxRetries,
+		retryDelay: cfg.RetryDelay,
+		retryable:  cfg.Retryable,
+		client:     cfg.Client,
+		logger:     cfg.Logger,
+	}
+	if ex.client == nil {
+		ex.client = &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				TLSHandshakeTimeout:   10 * time.Second,
+				ResponseHeaderTimeout: 10 * time.Second,
+				IdleConnTimeout:       90 * time.Second,
+			},
+		}
+	}
+	if ex.logger == nil {
+		ex.logger = log.Default()
+	}
+	if ex.maxRetries <= 0 {
+		ex.maxRetries = 2
+	}
+	if ex.retryDelay <= 0 {
+		ex.retryDelay = 500 * time.Millisecond
+	}
+	if ex.retryable == nil {
+		ex.retryable = defaultRetryable
+	}
+	if ex.endpoints == nil {
+		ex.endpoints = map[string]string{}
+	}
+	return ex
+}
+
+// path 根据接口表解析路径，未登记的名称按原样作为路径
+func (ex *restExchange) path(endpoint string) string {
+	if p, ok := ex.endpoints[endpoint]; ok {
+		return p
+	}
+	return endpoint
+}
+
+// exchangeSymbol 将 BTCUSDT 转换为交易所合约代码
+func (ex *restExchange) exchangeSymbol(symbol string) string {
+	if ex.symbols.toExchange == nil {
+		return symbol
+	}
+	return ex.symbols.toExchange(symbol)
+}
+
+// localSymbol 将交易所合约代码转换回 BTCUSDT
+func (ex *restExchange) localSymbol(symbol string) string {
+	if ex.symbols.fromExchange == nil {
+		return symbol
+	}
+	return ex.symbols.fromExchange(symbol)
+}
+
+// publicCall 调用无需签名的接口
+func (ex *restExchange) publicCall(method, endpoint string, query url.Values) (json.RawMessage, error) {
+	return ex.call(method, endpoint, query, nil, false)
+}
+
+// signedCall 调用需要签名的私有接口，payload为url.Values时按表单提交，其他类型按JSON提交
+func (ex *restExchange) signedCall(method, endpoint string, query url.Values, payload interface{}) (json.RawMessage, error) {
+	return ex.call(method, endpoint, query, payload, true)
+}
+
+// call 发送请求并解码响应
+// 是否重试由 retryable 钩子决定（默认见 defaultRetryable）。
+// 签名、构造请求等本地错误重试也不会成功，直接返回
+func (ex *restExchange) call(method, endpoint string, query url.Values, payload interface{}, signed bool) (json.RawMessage, error) {
+	var body []byte
+	switch p := payload.(type) {
+	case nil:
+	case url.Values:
+		// 表单body，Content-Type由签名钩子设置
+		body = []byte(p.Encode())
+	default:
+		bs, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("序列化请求参数失败: %w", err)
+		}
+		body = bs
+	}
+
+	var lastErr error
+	delay := ex.retryDelay
+	for attempt := 0; attempt <= ex.maxRetries; attempt++ {
+		if attempt > 0 {
+			ex.logger.Printf("  ⚠ %s 请求失败，%v后重试(%d/%d): %v", ex.name, delay, attempt, ex.maxRetries, lastErr)
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		// 每次尝试重新签名（签名中通常包含时间戳）
+		req, err := ex.newRequest(method, endpoint, query, body, signed)
+		if err != nil {
+			return nil, err
+		}
+
+		data, statusCode, err := ex.send(req)
+		if err == nil {
+			return data, nil
+		}
+		lastErr = err
+
+		if !ex.retryable(method, endpoint, statusCode) {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+// newRequest 构造（并签名）请求
+func (ex *restExchange) newRequest(method, endpoint string, query url.Values, body []byte, signed bool) (*http.Request, error) {
+	path := ex.path(endpoint)
+
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = append([]string(nil), v...)
+	}
+	header := http.Header{}
+	rawQuery := q.Encode()
+	if signed && ex.signer != nil {
+		var err error
+		rawQuery, err = ex.signer(method, path, q, body, header)
+		if err != nil {
+			return nil, fmt.Errorf("%s 签名失败: %w", ex.name, err)
+		}
+	}
+
+	fullURL := ex.baseURL + path
+	if rawQuery != "" {
+		fullURL += "?" + rawQuery
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, fullURL, reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s 构造请求失败: %w", ex.name, err)
+	}
+	req.Header = header
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// send 执行请求并解码响应，statusCode为0表示网络错误（未得到响应）
+func (ex *restExchange) send(req *http.Request) (json.RawMessage, int, error) {
+	resp, err := ex.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if ex.decoder == nil {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, resp.StatusCode, fmt.Errorf("%s HTTP %d: %s", ex.name, resp.StatusCode, string(respBody))
+		}
+		return respBody, resp.StatusCode, nil
+	}
+
+	data, err := ex.decoder(resp.StatusCode, respBody)
+	return data, resp.StatusCode, err
+}
+
+// restParams 按HTTP方法拆分参数：GET/DELETE放在querystring，其他方法作为JSON body
+// 供保留 request(method, endpoint, params) 调用方式的交易器使用
+func restParams(method string, params map[string]interface{}) (url.Values, interface{}) {
+	if method != "GET" && method != "DELETE" {
+		return nil, params
+	}
+	q := url.Values{}
+	for k, v := range params {
+		q.Set(k, fmt.Sprintf("%v", v))
+	}
+	return q, nil
+}
+
+// restRequestPath 拼接路径和querystring，签名时使用
+func restRequestPath(path, rawQuery string) string {
+	if rawQuery == "" {
+		return path
+	}
+	return path + "?" + rawQuery
+}
+
+// pickMarketPrice 按 market.UseMidPrice 选择行情价格：开启时返回买一卖一中间价，
+// 买一卖一无效时退回最新价
+func pickMarketPrice(last, bid, ask float64) float64 {
+	if market.UseMidPrice() && bid > 0 && ask > 0 {
+		return (bid + ask) / 2
+	}
+	return last
+}
+
+// floorToStep 将数值向下取整到步进
+func floorToStep(value, step float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	return math.Floor(value/step+1e-9) * step
+}
+
+// formatStep 按步进的小数位格式化数值（不做取整）
+func formatStep(value, step float64) string {
+	return strconv.FormatFloat(value, 'f', stepDecimals(step), 64)
+}
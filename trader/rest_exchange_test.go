@@ -0,0 +1,231 @@
+package trader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"nofx/market"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testEnvelopeDecoder 解码 {"code":0,"msg":"","data":...} 形式的响应
+func testEnvelopeDecoder(statusCode int, body []byte) (json.RawMessage, error) {
+	var env struct {
+		Code int             `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, fmt.Errorf("HTTP %d: %s", statusCode, string(body))
+	}
+	if env.Code != 0 {
+		return nil, fmt.Errorf("API错误 %d: %s", env.Code, env.Msg)
+	}
+	return env.Data, nil
+}
+
+func newTestRESTExchange(baseURL string, signer restSigner) *restExchange {
+	return newRESTExchange(restExchangeConfig{
+		Name:       "Test",
+		BaseURL:    baseURL,
+		Endpoints:  map[string]string{"ticker": "/api/ticker", "order": "/api/order"},
+		Signer:     signer,
+		Decoder:    testEnvelopeDecoder,
+		MaxRetries: 2,
+		RetryDelay: time.Millisecond,
+	})
+}
+
+func TestRESTExchangeSignedCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/order" {
+			t.Errorf("path = %s, want /api/order", r.URL.Path)
+		}
+		if got := r.Header.Get("X-API-KEY"); got != "key" {
+			t.Errorf("X-API-KEY = %q, want key", got)
+		}
+		if got := r.URL.Query().Get("signature"); got != "POST|/api/order|symbol=BTCUSDT|{\"qty\":1}" {
+			t.Errorf("signature = %q", got)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"qty":1}` {
+			t.Errorf("body = %s", body)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type = %q", got)
+		}
+		w.Write([]byte(`{"code":0,"data":{"orderId":"123"}}`))
+	}))
+	defer server.Close()
+
+	signer := func(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+		header.Set("X-API-KEY", "key")
+		query.Set("signature", method+"|"+path+"|"+query.Encode()+"|"+string(body))
+		return query.Encode(), nil
+	}
+	ex := newTestRESTExchange(server.URL, signer)
+
+	data, err := ex.signedCall("POST", "order", url.Values{"symbol": {"BTCUSDT"}}, map[string]interface{}{"qty": 1})
+	if err != nil {
+		t.Fatalf("signedCall 返回错误: %v", err)
+	}
+	if string(data) != `{"orderId":"123"}` {
+		t.Errorf("data = %s", data)
+	}
+}
+
+func TestRESTExchangeRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		statuses  []int // 依次返回的状态码，之后返回200
+		wantErr   bool
+		wantCalls int32
+	}{
+		{"GET 502后重试成功", "GET", []int{502}, false, 2},
+		{"GET 连续5xx超过重试次数", "GET", []int{500, 502, 503}, true, 3},
+		{"POST 502不重试", "POST", []int{502}, true, 1},
+		{"POST 429重试", "POST", []int{429}, false, 2},
+		{"GET 400不重试", "GET", []int{400}, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+				if int(n) <= len(tt.statuses) {
+					w.WriteHeader(tt.statuses[n-1])
+					w.Write([]byte(`{"code":500,"msg":"busy"}`))
+					return
+				}
+				w.Write([]byte(`{"code":0,"data":"ok"}`))
+			}))
+			defer server.Close()
+
+			ex := newTestRESTExchange(server.URL, nil)
+			_, err := ex.call(tt.method, "ticker", nil, nil, false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("请求次数 = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestBybitOptions(t *testing.T) {
+	client := &http.Client{}
+	bybit := NewBybitTrader("key", "secret", WithTestnet(), WithHTTPClient(client), WithRetryPolicy(5, time.Second))
+	if bybit.rest.baseURL != bybitTestnetURL {
+		t.Errorf("baseURL = %s, want %s", bybit.rest.baseURL, bybitTestnetURL)
+	}
+	if bybit.rest.client != client {
+		t.Error("未使用自定义HTTP客户端")
+	}
+	if bybit.rest.maxRetries != 5 || bybit.rest.retryDelay != time.Second {
+		t.Errorf("重试策略 = %d/%v, want 5/1s", bybit.rest.maxRetries, bybit.rest.retryDelay)
+	}
+
+	if def := NewBybitTrader("key", "secret"); def.rest.baseURL != bybitMainnetURL || def.rest.maxRetries != 2 {
+		t.Errorf("默认配置 = %s/%d, want 主网/2", def.rest.baseURL, def.rest.maxRetries)
+	}
+}
+
+func TestDeribitRetryable(t *testing.T) {
+	tests := []struct {
+		endpoint   string
+		statusCode int
+		want       bool
+	}{
+		{"public/ticker", 502, true},
+		{"private/get_positions", 0, true},
+		{"private/buy", 502, false}, // 下单走GET，网络错误和5xx不能重试
+		{"private/cancel_all_by_instrument", 0, false},
+		{"private/sell", 429, true},
+	}
+	for _, tt := range tests {
+		if got := deribitRetryable("GET", tt.endpoint, tt.statusCode); got != tt.want {
+			t.Errorf("deribitRetryable(%s, %d) = %v, want %v", tt.endpoint, tt.statusCode, got, tt.want)
+		}
+	}
+}
+
+func TestRESTExchangeSignerErrorNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	signCalls := 0
+	signer := func(method, path string, query url.Values, body []byte, header http.Header) (string, error) {
+		signCalls++
+		return "", errors.New("bad key")
+	}
+	ex := newTestRESTExchange(server.URL, signer)
+
+	_, err := ex.signedCall("GET", "ticker", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "签名失败") {
+		t.Fatalf("err = %v, want 签名失败", err)
+	}
+	if signCalls != 1 {
+		t.Errorf("签名次数 = %d, want 1", signCalls)
+	}
+	if calls != 0 {
+		t.Errorf("签名失败时不应发出请求，实际 %d 次", calls)
+	}
+}
+
+func TestRESTExchangeEnvelopeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":100400,"msg":"invalid symbol"}`))
+	}))
+	defer server.Close()
+
+	ex := newTestRESTExchange(server.URL, nil)
+	_, err := ex.publicCall("GET", "ticker", url.Values{"symbol": {"XXX"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid symbol") {
+		t.Errorf("err = %v, want 包含 invalid symbol", err)
+	}
+}
+
+func TestFloorAndFormatStep(t *testing.T) {
+	tests := []struct {
+		value, step float64
+		want        string
+	}{
+		{1.23456, 0.001, "1.234"},
+		{0.3, 0.1, "0.3"}, // 浮点误差不应向下多取一个步进
+		{17, 5, "15"},
+		{2.7, 0.5, "2.5"},
+	}
+	for _, tt := range tests {
+		if got := formatStep(floorToStep(tt.value, tt.step), tt.step); got != tt.want {
+			t.Errorf("floorToStep(%v, %v) = %s, want %s", tt.value, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestPickMarketPrice(t *testing.T) {
+	if got := pickMarketPrice(101, 99, 100); got != 101 {
+		t.Errorf("未开启中间价时 = %v, want 最新价101", got)
+	}
+
+	market.SetUseMidPrice(true)
+	defer market.SetUseMidPrice(false)
+	if got := pickMarketPrice(101, 99, 100); got != 99.5 {
+		t.Errorf("中间价 = %v, want 99.5", got)
+	}
+	if got := pickMarketPrice(101, 0, 100); got != 101 {
+		t.Errorf("买一卖一无效时 = %v, want 退回最新价101", got)
+	}
+}
@@ -0,0 +1,405 @@
+package trader
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RouterConfig 多交易所路由配置
+type RouterConfig struct {
+	// Default 未单独配置的币种使用的交易所名称
+	Default string
+	// Routes 币种 -> (交易所名称 -> 权重)；多个交易所时按权重拆分下单数量
+	// 例如 {"BTCUSDT": {"binance": 0.5, "bybit": 0.5}, "SOLUSDT": {"bybit": 1}}
+	Routes map[string]map[string]float64
+}
+
+// routeLeg 一个币种路由到的单个交易所及其拆分比例
+type routeLeg struct {
+	name   string
+	trader Trader
+	weight float64 // 已归一化，同一币种所有leg之和为1
+}
+
+// RouterTrader 聚合多个交易器：按币种把订单路由到一个或多个交易所（开仓按权重拆单，
+// 平仓和止损止盈按各交易所实际持仓拆分），余额和持仓返回所有交易所的合并视图。
+// 路由需要多个交易所的凭证，而单个交易员配置只对应一个交易所，因此没有注册到工厂表，
+// 只能在代码中用 NewRouterTrader 组合已创建的交易器
+type RouterTrader struct {
+	traders map[string]Trader
+	names   []string // 交易所名称（排序后），保证遍历顺序稳定
+	routes  map[string][]routeLeg
+	deflt   []routeLeg
+}
+
+// NewRouterTrader 创建路由交易器，traders 为 交易所名称 -> 交易器
+func NewRouterTrader(traders map[string]Trader, cfg RouterConfig) (*RouterTrader, error) {
+	if len(traders) == 0 {
+		return nil, fmt.Errorf("路由交易器至少需要一个交易所")
+	}
+
+	r := &RouterTrader{
+		traders: traders,
+		routes:  make(map[string][]routeLeg),
+	}
+	for name := range traders {
+		r.names = append(r.names, name)
+	}
+	sort.Strings(r.names)
+
+	// 默认路由：未指定时只有一个交易所则使用它
+	defaultName := cfg.Default
+	if defaultName == "" && len(traders) == 1 {
+		defaultName = r.names[0]
+	}
+	if defaultName != "" {
+		legs, err := r.buildLegs(map[string]float64{defaultName: 1})
+		if err != nil {
+			return nil, fmt.Errorf("默认路由配置错误: %w", err)
+		}
+		r.deflt = legs
+	}
+
+	for symbol, weights := range cfg.Routes {
+		legs, err := r.buildLegs(weights)
+		if err != nil {
+			return nil, fmt.Errorf("%s 路由配置错误: %w", symbol, err)
+		}
+		r.routes[symbol] = legs
+	}
+
+	return r, nil
+}
+
+// buildLegs 校验交易所名称并归一化权重
+func (r *RouterTrader) buildLegs(weights map[string]float64) ([]routeLeg, error) {
+	total := 0.0
+	for name, w := range weights {
+		if _, ok := r.traders[name]; !ok {
+			return nil, fmt.Errorf("未知的交易所: %s", name)
+		}
+		if w < 0 {
+			return nil, fmt.Errorf("%s 权重不能为负数", name)
+		}
+		total += w
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("权重之和必须大于0")
+	}
+
+	legs := []routeLeg{}
+	for _, name := range r.names {
+		if w := weights[name]; w > 0 {
+			legs = append(legs, routeLeg{name: name, trader: r.traders[name], weight: w / total})
+		}
+	}
+	return legs, nil
+}
+
+// legsFor 获取币种的路由
+func (r *RouterTrader) legsFor(symbol string) ([]routeLeg, error) {
+	if legs, ok := r.routes[symbol]; ok {
+		return legs, nil
+	}
+	if r.deflt != nil {
+		return r.deflt, nil
+	}
+	return nil, fmt.Errorf("%s 没有配置路由，且未设置默认交易所", symbol)
+}
+
+// heldLegs 返回该币种该方向实际有持仓的交易所，权重为各交易所持仓占总持仓的比例
+// 平仓和止损止盈按实际持仓拆分，避免开仓时各交易所取整不同或部分失败导致超量平仓
+func (r *RouterTrader) heldLegs(symbol, side string) ([]routeLeg, error) {
+	legs, err := r.legsFor(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	held := []routeLeg{}
+	total := 0.0
+	for _, leg := range legs {
+		positions, err := leg.trader.GetPositions()
+		if err != nil {
+			return nil, fmt.Errorf("[%s] 获取持仓失败: %w", leg.name, err)
+		}
+		for _, pos := range positions {
+			if pos["symbol"] != symbol || pos["side"] != side {
+				continue
+			}
+			amt, _ := pos["positionAmt"].(float64)
+			if amt = absFloat(amt); amt > 0 {
+				held = append(held, routeLeg{name: leg.name, trader: leg.trader, weight: amt})
+				total += amt
+			}
+		}
+	}
+
+	if total <= 0 {
+		if side == "long" {
+			return nil, fmt.Errorf("没有找到 %s 的多仓", symbol)
+		}
+		return nil, fmt.Errorf("没有找到 %s 的空仓", symbol)
+	}
+	for i := range held {
+		held[i].weight /= total
+	}
+	return held, nil
+}
+
+// splitOrder 按legs的权重拆分数量并在各交易所执行，quantity为0（全部平仓）时原样传给每个交易所
+func (r *RouterTrader) splitOrder(symbol string, legs []routeLeg, quantity float64, action string,
+	fn func(t Trader, quantity float64) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	results := []map[string]interface{}{}
+	for _, leg := range legs {
+		legQty := quantity * leg.weight
+		result, err := fn(leg.trader, legQty)
+		if err != nil {
+			if len(results) > 0 {
+				// 部分交易所已成交，返回错误让上层知晓仓位不完整
+				return nil, fmt.Errorf("[%s] %s失败（已有 %d 个交易所成交）: %w", leg.name, action, len(results), err)
+			}
+			return nil, fmt.Errorf("[%s] %s失败: %w", leg.name, action, err)
+		}
+		if result == nil {
+			result = map[string]interface{}{}
+		}
+		result["exchange"] = leg.name
+		results = append(results, result)
+	}
+
+	if len(results) == 1 {
+		return results[0], nil
+	}
+
+	log.Printf("  🔀 %s %s 已拆分到 %d 个交易所", symbol, action, len(results))
+	return map[string]interface{}{
+		"orderId": results[0]["orderId"],
+		"symbol":  symbol,
+		"legs":    results,
+	}, nil
+}
+
+// forEachLeg 在币种路由到的每个交易所上执行操作
+func (r *RouterTrader) forEachLeg(symbol string, fn func(leg routeLeg) error) error {
+	legs, err := r.legsFor(symbol)
+	if err != nil {
+		return err
+	}
+	for _, leg := range legs {
+		if err := fn(leg); err != nil {
+			return fmt.Errorf("[%s] %w", leg.name, err)
+		}
+	}
+	return nil
+}
+
+// GetBalance 汇总所有交易所的余额
+func (r *RouterTrader) GetBalance() (map[string]interface{}, error) {
+	keys := []string{"totalWalletBalance", "availableBalance", "totalUnrealizedProfit"}
+	total := map[string]float64{}
+
+	for _, name := range r.names {
+		balance, err := r.traders[name].GetBalance()
+		if err != nil {
+			return nil, fmt.Errorf("[%s] 获取余额失败: %w", name, err)
+		}
+		for _, key := range keys {
+			if v, ok := balance[key].(float64); ok {
+				total[key] += v
+			}
+		}
+	}
+
+	result := map[string]interface{}{}
+	for _, key := range keys {
+		result[key] = total[key]
+	}
+	return result, nil
+}
+
+// GetPositions 合并所有交易所的持仓：同币种同方向的仓位合并为一条，开仓价按数量加权
+// 合并后的 positionAmt 为正数
+func (r *RouterTrader) GetPositions() ([]map[string]interface{}, error) {
+	merged := map[string]map[string]interface{}{}
+	order := []string{}
+
+	for _, name := range r.names {
+		positions, err := r.traders[name].GetPositions()
+		if err != nil {
+			return nil, fmt.Errorf("[%s] 获取持仓失败: %w", name, err)
+		}
+
+		for _, pos := range positions {
+			symbol, _ := pos["symbol"].(string)
+			side, _ := pos["side"].(string)
+			key := symbol + "_" + side
+
+			existing, ok := merged[key]
+			if !ok {
+				copied := make(map[string]interface{}, len(pos)+1)
+				for k, v := range pos {
+					copied[k] = v
+				}
+				// Binance空仓数量为负，其他交易所为正，统一返回绝对值
+				amt, _ := pos["positionAmt"].(float64)
+				copied["positionAmt"] = absFloat(amt)
+				copied["exchanges"] = []string{name}
+				merged[key] = copied
+				order = append(order, key)
+				continue
+			}
+
+			oldAmt, _ := existing["positionAmt"].(float64)
+			oldEntry, _ := existing["entryPrice"].(float64)
+			addAmt, _ := pos["positionAmt"].(float64)
+			addEntry, _ := pos["entryPrice"].(float64)
+			addAmt = absFloat(addAmt)
+			oldPnL, _ := existing["unRealizedProfit"].(float64)
+			addPnL, _ := pos["unRealizedProfit"].(float64)
+
+			totalAmt := oldAmt + addAmt
+			if totalAmt > 0 {
+				existing["entryPrice"] = (oldEntry*oldAmt + addEntry*addAmt) / totalAmt
+			}
+			existing["positionAmt"] = totalAmt
+			existing["unRealizedProfit"] = oldPnL + addPnL
+			existing["exchanges"] = append(existing["exchanges"].([]string), name)
+		}
+	}
+
+	result := make([]map[string]interface{}, 0, len(order))
+	for _, key := range order {
+		result = append(result, merged[key])
+	}
+	return result, nil
+}
+
+// OpenLong 按路由权重开多仓
+func (r *RouterTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	legs, err := r.legsFor(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return r.splitOrder(symbol, legs, quantity, "开多仓", func(t Trader, q float64) (map[string]interface{}, error) {
+		return t.OpenLong(symbol, q, leverage)
+	})
+}
+
+// OpenShort 按路由权重开空仓
+func (r *RouterTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	legs, err := r.legsFor(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return r.splitOrder(symbol, legs, quantity, "开空仓", func(t Trader, q float64) (map[string]interface{}, error) {
+		return t.OpenShort(symbol, q, leverage)
+	})
+}
+
+// CloseLong 按各交易所实际多仓比例平仓（quantity为0时各交易所全部平仓）
+func (r *RouterTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	legs, err := r.heldLegs(symbol, "long")
+	if err != nil {
+		return nil, fmt.Errorf("平多仓失败: %w", err)
+	}
+	return r.splitOrder(symbol, legs, quantity, "平多仓", func(t Trader, q float64) (map[string]interface{}, error) {
+		return t.CloseLong(symbol, q)
+	})
+}
+
+// CloseShort 按各交易所实际空仓比例平仓（quantity为0时各交易所全部平仓）
+func (r *RouterTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	legs, err := r.heldLegs(symbol, "short")
+	if err != nil {
+		return nil, fmt.Errorf("平空仓失败: %w", err)
+	}
+	return r.splitOrder(symbol, legs, quantity, "平空仓", func(t Trader, q float64) (map[string]interface{}, error) {
+		return t.CloseShort(symbol, q)
+	})
+}
+
+// SetLeverage 在币种路由到的所有交易所设置杠杆
+func (r *RouterTrader) SetLeverage(symbol string, leverage int) error {
+	return r.forEachLeg(symbol, func(leg routeLeg) error {
+		return leg.trader.SetLeverage(symbol, leverage)
+	})
+}
+
+// SetMarginMode 在币种路由到的所有交易所设置仓位模式
+func (r *RouterTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	return r.forEachLeg(symbol, func(leg routeLeg) error {
+		return leg.trader.SetMarginMode(symbol, isCrossMargin)
+	})
+}
+
+// GetMarketPrice 使用路由中第一个交易所的价格
+func (r *RouterTrader) GetMarketPrice(symbol string) (float64, error) {
+	legs, err := r.legsFor(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return legs[0].trader.GetMarketPrice(symbol)
+}
+
+// SetStopLoss 按各交易所实际持仓比例拆分数量，在有持仓的交易所设置止损
+func (r *RouterTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	legs, err := r.heldLegs(symbol, strings.ToLower(positionSide))
+	if err != nil {
+		return fmt.Errorf("设置止损失败: %w", err)
+	}
+	for _, leg := range legs {
+		if err := leg.trader.SetStopLoss(symbol, positionSide, quantity*leg.weight, stopPrice); err != nil {
+			return fmt.Errorf("[%s] %w", leg.name, err)
+		}
+	}
+	return nil
+}
+
+// SetTakeProfit 按各交易所实际持仓比例拆分数量，在有持仓的交易所设置止盈
+func (r *RouterTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	legs, err := r.heldLegs(symbol, strings.ToLower(positionSide))
+	if err != nil {
+		return fmt.Errorf("设置止盈失败: %w", err)
+	}
+	for _, leg := range legs {
+		if err := leg.trader.SetTakeProfit(symbol, positionSide, quantity*leg.weight, takeProfitPrice); err != nil {
+			return fmt.Errorf("[%s] %w", leg.name, err)
+		}
+	}
+	return nil
+}
+
+// CancelAllOrders 取消币种路由到的所有交易所的挂单
+func (r *RouterTrader) CancelAllOrders(symbol string) error {
+	return r.forEachLeg(symbol, func(leg routeLeg) error {
+		return leg.trader.CancelAllOrders(symbol)
+	})
+}
+
+// FormatQuantity 返回拆单后各交易所实际可下单数量之和（实现Trader接口）
+func (r *RouterTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	legs, err := r.legsFor(symbol)
+	if err != nil {
+		return "", err
+	}
+	if len(legs) == 1 {
+		return legs[0].trader.FormatQuantity(symbol, quantity)
+	}
+
+	total := 0.0
+	for _, leg := range legs {
+		formatted, err := leg.trader.FormatQuantity(symbol, quantity*leg.weight)
+		if err != nil {
+			return "", fmt.Errorf("[%s] %w", leg.name, err)
+		}
+		v, err := strconv.ParseFloat(formatted, 64)
+		if err != nil {
+			return "", fmt.Errorf("[%s] 解析数量失败: %w", leg.name, err)
+		}
+		total += v
+	}
+	return strconv.FormatFloat(total, 'f', -1, 64), nil
+}
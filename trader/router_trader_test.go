@@ -0,0 +1,150 @@
+package trader_test
+
+import (
+	"reflect"
+	"testing"
+
+	"nofx/trader"
+	"nofx/trader/tradertest"
+)
+
+// newTestRouter 创建由 binance 和 bybit 两个 MockTrader 组成的路由交易器
+func newTestRouter(t *testing.T, routes map[string]map[string]float64) (*trader.RouterTrader, *tradertest.MockTrader, *tradertest.MockTrader) {
+	t.Helper()
+	binance := tradertest.NewMockTrader(1000)
+	bybit := tradertest.NewMockTrader(1000)
+
+	router, err := trader.NewRouterTrader(map[string]trader.Trader{
+		"binance": binance,
+		"bybit":   bybit,
+	}, trader.RouterConfig{Default: "binance", Routes: routes})
+	if err != nil {
+		t.Fatalf("NewRouterTrader 返回错误: %v", err)
+	}
+	return router, binance, bybit
+}
+
+// quantityArg 返回指定方法唯一一次调用的数量参数（参数下标为 idx）
+func quantityArg(t *testing.T, m *tradertest.MockTrader, method string, idx int) float64 {
+	t.Helper()
+	calls := m.CallsTo(method)
+	if len(calls) != 1 {
+		t.Fatalf("%s 调用次数 = %d, want 1", method, len(calls))
+	}
+	return calls[0].Args[idx].(float64)
+}
+
+func TestRouterTraderWeightedOpen(t *testing.T) {
+	router, binance, bybit := newTestRouter(t, map[string]map[string]float64{
+		"BTCUSDT": {"binance": 3, "bybit": 1},
+	})
+
+	result, err := router.OpenLong("BTCUSDT", 4, 10)
+	if err != nil {
+		t.Fatalf("OpenLong 返回错误: %v", err)
+	}
+	if legs, _ := result["legs"].([]map[string]interface{}); len(legs) != 2 {
+		t.Errorf("legs = %v, want 2个交易所", result["legs"])
+	}
+	if got := quantityArg(t, binance, "OpenLong", 1); got != 3 {
+		t.Errorf("binance 开仓数量 = %v, want 3", got)
+	}
+	if got := quantityArg(t, bybit, "OpenLong", 1); got != 1 {
+		t.Errorf("bybit 开仓数量 = %v, want 1", got)
+	}
+
+	// 未配置路由的币种走默认交易所
+	if _, err := router.OpenShort("ETHUSDT", 2, 5); err != nil {
+		t.Fatalf("OpenShort 返回错误: %v", err)
+	}
+	if got := quantityArg(t, binance, "OpenShort", 1); got != 2 {
+		t.Errorf("默认路由开仓数量 = %v, want 2", got)
+	}
+	if n := len(bybit.CallsTo("OpenShort")); n != 0 {
+		t.Errorf("bybit OpenShort 调用次数 = %d, want 0", n)
+	}
+}
+
+func TestRouterTraderCloseByHeldSize(t *testing.T) {
+	// 按1:1开仓，但各交易所实际成交不均：binance 3，bybit 1
+	router, binance, bybit := newTestRouter(t, map[string]map[string]float64{
+		"BTCUSDT": {"binance": 1, "bybit": 1},
+	})
+	binance.Positions = []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "positionAmt": 3.0, "entryPrice": 100.0},
+	}
+	bybit.Positions = []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "long", "positionAmt": 1.0, "entryPrice": 100.0},
+	}
+
+	if _, err := router.CloseLong("BTCUSDT", 2); err != nil {
+		t.Fatalf("CloseLong 返回错误: %v", err)
+	}
+	if got := quantityArg(t, binance, "CloseLong", 1); got != 1.5 {
+		t.Errorf("binance 平仓数量 = %v, want 1.5", got)
+	}
+	if got := quantityArg(t, bybit, "CloseLong", 1); got != 0.5 {
+		t.Errorf("bybit 平仓数量 = %v, want 0.5", got)
+	}
+
+	if err := router.SetStopLoss("BTCUSDT", "LONG", 4, 90); err != nil {
+		t.Fatalf("SetStopLoss 返回错误: %v", err)
+	}
+	if got := quantityArg(t, binance, "SetStopLoss", 2); got != 3 {
+		t.Errorf("binance 止损数量 = %v, want 3", got)
+	}
+	if got := quantityArg(t, bybit, "SetStopLoss", 2); got != 1 {
+		t.Errorf("bybit 止损数量 = %v, want 1", got)
+	}
+
+	// 只有一个交易所有持仓时只在该交易所平仓
+	bybit.Positions = []map[string]interface{}{}
+	binance.Reset()
+	bybit.Reset()
+	if _, err := router.CloseLong("BTCUSDT", 0); err != nil {
+		t.Fatalf("CloseLong 返回错误: %v", err)
+	}
+	if got := quantityArg(t, binance, "CloseLong", 1); got != 0 {
+		t.Errorf("全部平仓数量 = %v, want 0", got)
+	}
+	if n := len(bybit.CallsTo("CloseLong")); n != 0 {
+		t.Errorf("无持仓的 bybit CloseLong 调用次数 = %d, want 0", n)
+	}
+}
+
+func TestRouterTraderMergePositions(t *testing.T) {
+	router, binance, bybit := newTestRouter(t, nil)
+	// Binance 空仓数量为负数
+	binance.Positions = []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "short", "positionAmt": -2.0, "entryPrice": 100.0, "unRealizedProfit": 4.0},
+		{"symbol": "ETHUSDT", "side": "short", "positionAmt": -1.0, "entryPrice": 3000.0, "unRealizedProfit": 0.0},
+	}
+	bybit.Positions = []map[string]interface{}{
+		{"symbol": "BTCUSDT", "side": "short", "positionAmt": 2.0, "entryPrice": 110.0, "unRealizedProfit": -1.0},
+	}
+
+	positions, err := router.GetPositions()
+	if err != nil {
+		t.Fatalf("GetPositions 返回错误: %v", err)
+	}
+	if len(positions) != 2 {
+		t.Fatalf("持仓条数 = %d, want 2", len(positions))
+	}
+
+	btc := positions[0]
+	if btc["positionAmt"] != 4.0 || btc["entryPrice"] != 105.0 || btc["unRealizedProfit"] != 3.0 {
+		t.Errorf("BTCUSDT 合并持仓 = %v, want 数量4 均价105 盈亏3", btc)
+	}
+	if !reflect.DeepEqual(btc["exchanges"], []string{"binance", "bybit"}) {
+		t.Errorf("exchanges = %v", btc["exchanges"])
+	}
+
+	// 只在Binance上的空仓同样返回正数
+	if eth := positions[1]; eth["positionAmt"] != 1.0 {
+		t.Errorf("ETHUSDT positionAmt = %v, want 1", eth["positionAmt"])
+	}
+	// 不应修改底层交易器返回的持仓
+	if binance.Positions[0]["positionAmt"] != -2.0 {
+		t.Errorf("底层持仓被修改: %v", binance.Positions[0])
+	}
+}
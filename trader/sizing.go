@@ -0,0 +1,138 @@
+package trader
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidRiskReward 入场价、止损价和止盈价的相对位置与持仓方向不符
+var ErrInvalidRiskReward = errors.New("止损止盈价格与持仓方向不匹配")
+
+// ComputeKellyFraction 计算凯利公式最优仓位比例
+// f* = (胜率 / 亏损赔率) - ((1 - 胜率) / 盈利赔率)
+// winRate: 胜率(0-1)；winPayoffRatio: 盈利时的收益倍数；lossPayoffRatio: 亏损时的损失倍数
+// 参数无效时返回0，结果小于0时说明没有正期望，同样返回0
+func ComputeKellyFraction(winRate, winPayoffRatio, lossPayoffRatio float64) float64 {
+	if winRate <= 0 || winRate > 1 || winPayoffRatio <= 0 || lossPayoffRatio <= 0 {
+		return 0
+	}
+
+	fraction := (winRate / lossPayoffRatio) - ((1 - winRate) / winPayoffRatio)
+	if fraction < 0 {
+		return 0
+	}
+	return fraction
+}
+
+// ComputeHalfKelly 计算半凯利仓位比例（更保守的仓位管理）
+func ComputeHalfKelly(winRate, winPayoffRatio, lossPayoffRatio float64) float64 {
+	return ComputeKellyFraction(winRate, winPayoffRatio, lossPayoffRatio) / 2
+}
+
+// ApplyKellyToBalance 将仓位比例应用到可用余额，返回可承担风险的USDT金额
+func ApplyKellyToBalance(t Trader, fraction float64) (float64, error) {
+	if fraction < 0 {
+		return 0, fmt.Errorf("仓位比例不能为负数: %.4f", fraction)
+	}
+
+	balance, err := t.GetBalance()
+	if err != nil {
+		return 0, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	availableBalance, ok := balance["availableBalance"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("账户余额缺少 availableBalance 字段")
+	}
+
+	return availableBalance * fraction, nil
+}
+
+// MarginImpact 下单对可用余额的影响
+type MarginImpact struct {
+	RequiredMargin       float64 // 该订单所需保证金
+	NewAvailableBalance  float64 // 下单后的可用余额
+	MarginUtilizationPct float64 // 下单后已用保证金占钱包余额的百分比
+	IsAffordable         bool    // 可用余额是否足够
+}
+
+// SimulateOrderMarginImpact 估算订单占用的保证金及下单后的可用余额（不实际下单）
+// side: "long"/"short"（仅用于校验）；orderType: "market" 使用当前市价，"limit" 使用传入的price
+func SimulateOrderMarginImpact(t Trader, symbol, side string, qty float64, leverage int, orderType string, price float64) (*MarginImpact, error) {
+	if side != "long" && side != "short" {
+		return nil, fmt.Errorf("无效的持仓方向: %s", side)
+	}
+	if qty <= 0 {
+		return nil, fmt.Errorf("下单数量必须大于0: %.8f", qty)
+	}
+	if leverage <= 0 {
+		return nil, fmt.Errorf("杠杆倍数必须大于0: %d", leverage)
+	}
+
+	switch orderType {
+	case "market":
+		marketPrice, err := t.GetMarketPrice(symbol)
+		if err != nil {
+			return nil, fmt.Errorf("获取市场价格失败: %w", err)
+		}
+		price = marketPrice
+	case "limit":
+		if price <= 0 {
+			return nil, fmt.Errorf("限价单价格必须大于0: %.8f", price)
+		}
+	default:
+		return nil, fmt.Errorf("不支持的订单类型: %s", orderType)
+	}
+
+	balance, err := t.GetBalance()
+	if err != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", err)
+	}
+
+	availableBalance, ok := balance["availableBalance"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("账户余额缺少 availableBalance 字段")
+	}
+	walletBalance, _ := balance["totalWalletBalance"].(float64)
+
+	// USDT本位合约1张即1个币，名义价值 = 数量 × 价格
+	requiredMargin := qty * price / float64(leverage)
+	impact := &MarginImpact{
+		RequiredMargin:      requiredMargin,
+		NewAvailableBalance: availableBalance - requiredMargin,
+		IsAffordable:        requiredMargin <= availableBalance,
+	}
+	if walletBalance > 0 {
+		impact.MarginUtilizationPct = (walletBalance - impact.NewAvailableBalance) / walletBalance * 100
+	}
+
+	return impact, nil
+}
+
+// ComputeRiskRewardRatio 计算盈亏比 |止盈 - 入场| / |入场 - 止损|
+// 多头要求 止盈 > 入场 > 止损，空头要求 止损 > 入场 > 止盈，否则返回 ErrInvalidRiskReward
+func ComputeRiskRewardRatio(entryPrice, stopPrice, takeProfitPrice float64, side string) (float64, error) {
+	switch side {
+	case "long":
+		if !(takeProfitPrice > entryPrice && entryPrice > stopPrice) {
+			return 0, ErrInvalidRiskReward
+		}
+	case "short":
+		if !(stopPrice > entryPrice && entryPrice > takeProfitPrice) {
+			return 0, ErrInvalidRiskReward
+		}
+	default:
+		return 0, fmt.Errorf("无效的持仓方向: %s", side)
+	}
+
+	return absFloat(takeProfitPrice-entryPrice) / absFloat(entryPrice-stopPrice), nil
+}
+
+// ComputeBreakEvenRate 计算给定盈亏比下保本所需的最低胜率(0-1)：1 / (1 + 盈亏比)
+// 例如盈亏比为2时，胜率需高于33.3%才能盈利
+func ComputeBreakEvenRate(riskRewardRatio float64) (float64, error) {
+	if riskRewardRatio <= 0 {
+		return 0, fmt.Errorf("盈亏比必须大于0: %.4f", riskRewardRatio)
+	}
+	return 1 / (1 + riskRewardRatio), nil
+}
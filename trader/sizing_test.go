@@ -0,0 +1,135 @@
+package trader
+
+import (
+	"math"
+	"testing"
+)
+
+// balanceStub 只实现 GetBalance 的测试交易器
+type balanceStub struct {
+	Trader
+	balance map[string]interface{}
+}
+
+func (s *balanceStub) GetBalance() (map[string]interface{}, error) {
+	return s.balance, nil
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestComputeKellyFraction(t *testing.T) {
+	tests := []struct {
+		name                string
+		winRate, win, loss  float64
+		wantKelly, wantHalf float64
+	}{
+		{"60%胜率 2:1赔率", 0.6, 2, 1, 0.4, 0.2},
+		{"55%胜率 1.5:1赔率", 0.55, 1.5, 1, 0.25, 0.125},
+		{"亏损赔率0.5", 0.5, 1, 0.5, 0.5, 0.25},
+		{"无优势", 0.5, 1, 1, 0, 0},
+		{"负期望截断为0", 0.4, 1, 1, 0, 0},
+		{"胜率为0", 0, 2, 1, 0, 0},
+		{"胜率为1", 1, 2, 1, 1, 0.5},
+		{"胜率大于1无效", 1.2, 2, 1, 0, 0},
+		{"盈利赔率为0无效", 0.6, 0, 1, 0, 0},
+		{"亏损赔率为负无效", 0.6, 2, -1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeKellyFraction(tt.winRate, tt.win, tt.loss); !almostEqual(got, tt.wantKelly) {
+				t.Errorf("ComputeKellyFraction(%v, %v, %v) = %v, want %v", tt.winRate, tt.win, tt.loss, got, tt.wantKelly)
+			}
+			if got := ComputeHalfKelly(tt.winRate, tt.win, tt.loss); !almostEqual(got, tt.wantHalf) {
+				t.Errorf("ComputeHalfKelly(%v, %v, %v) = %v, want %v", tt.winRate, tt.win, tt.loss, got, tt.wantHalf)
+			}
+		})
+	}
+}
+
+func TestApplyKellyToBalance(t *testing.T) {
+	stub := &balanceStub{balance: map[string]interface{}{"availableBalance": 1000.0}}
+
+	got, err := ApplyKellyToBalance(stub, 0.25)
+	if err != nil {
+		t.Fatalf("ApplyKellyToBalance 返回错误: %v", err)
+	}
+	if !almostEqual(got, 250) {
+		t.Errorf("ApplyKellyToBalance(0.25) = %v, want 250", got)
+	}
+
+	if _, err := ApplyKellyToBalance(stub, -0.1); err == nil {
+		t.Error("负的仓位比例应返回错误")
+	}
+
+	missing := &balanceStub{balance: map[string]interface{}{}}
+	if _, err := ApplyKellyToBalance(missing, 0.1); err == nil {
+		t.Error("缺少 availableBalance 时应返回错误")
+	}
+}
+
+func TestComputeRiskRewardRatio(t *testing.T) {
+	tests := []struct {
+		name                    string
+		entry, stop, takeProfit float64
+		side                    string
+		want                    float64
+		wantErr                 error
+	}{
+		{"多头 2R", 100, 95, 110, "long", 2, nil},
+		{"多头 1.5R", 50000, 49000, 51500, "long", 1.5, nil},
+		{"空头 3R", 100, 102, 94, "short", 3, nil},
+		{"空头 0.5R", 2000, 2100, 1950, "short", 0.5, nil},
+		{"多头止损高于入场", 100, 105, 110, "long", 0, ErrInvalidRiskReward},
+		{"多头止盈低于入场", 100, 95, 90, "long", 0, ErrInvalidRiskReward},
+		{"空头价格方向反了", 100, 95, 110, "short", 0, ErrInvalidRiskReward},
+		{"止损等于入场", 100, 100, 110, "long", 0, ErrInvalidRiskReward},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComputeRiskRewardRatio(tt.entry, tt.stop, tt.takeProfit, tt.side)
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !almostEqual(got, tt.want) {
+				t.Errorf("ComputeRiskRewardRatio = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ComputeRiskRewardRatio(100, 95, 110, "both"); err == nil {
+		t.Error("无效的持仓方向应返回错误")
+	}
+}
+
+func TestComputeBreakEvenRate(t *testing.T) {
+	tests := []struct {
+		rr   float64
+		want float64
+	}{
+		{1, 0.5},
+		{2, 1.0 / 3},
+		{3, 0.25},
+		{0.5, 2.0 / 3},
+		{4, 0.2},
+	}
+
+	for _, tt := range tests {
+		got, err := ComputeBreakEvenRate(tt.rr)
+		if err != nil {
+			t.Fatalf("ComputeBreakEvenRate(%v) 返回错误: %v", tt.rr, err)
+		}
+		if !almostEqual(got, tt.want) {
+			t.Errorf("ComputeBreakEvenRate(%v) = %v, want %v", tt.rr, got, tt.want)
+		}
+	}
+
+	for _, rr := range []float64{0, -1} {
+		if _, err := ComputeBreakEvenRate(rr); err == nil {
+			t.Errorf("ComputeBreakEvenRate(%v) 应返回错误", rr)
+		}
+	}
+}
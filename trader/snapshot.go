@@ -0,0 +1,150 @@
+package trader
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"nofx/market"
+	"sync"
+	"time"
+)
+
+// AccountSnapshot 某一时刻的完整账户状态
+// Trader接口不提供挂单查询，因此快照只包含余额、持仓和持仓币种的资金费率
+type AccountSnapshot struct {
+	SnapshotTime time.Time                `json:"snapshot_time"`
+	Balance      map[string]interface{}   `json:"balance"`
+	Positions    []map[string]interface{} `json:"positions"`
+	FundingRates map[string]float64       `json:"funding_rates"` // 持仓币种 -> 当前资金费率
+}
+
+// SnapshotDiff 两个快照之间的变化
+type SnapshotDiff struct {
+	Elapsed                time.Duration      `json:"elapsed"`
+	WalletBalanceChange    float64            `json:"wallet_balance_change"`
+	AvailableBalanceChange float64            `json:"available_balance_change"`
+	UnrealizedPnLChange    float64            `json:"unrealized_pnl_change"`
+	OpenedPositions        []string           `json:"opened_positions"` // symbol_side
+	ClosedPositions        []string           `json:"closed_positions"` // symbol_side
+	QuantityChanges        map[string]float64 `json:"quantity_changes"` // symbol_side -> 数量变化
+}
+
+// GetAccountSnapshot 并发获取余额和持仓，生成账户快照
+func GetAccountSnapshot(t Trader) (*AccountSnapshot, error) {
+	var wg sync.WaitGroup
+	var balance map[string]interface{}
+	var positions []map[string]interface{}
+	var balanceErr, positionsErr error
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		balance, balanceErr = t.GetBalance()
+	}()
+	go func() {
+		defer wg.Done()
+		positions, positionsErr = t.GetPositions()
+	}()
+	wg.Wait()
+
+	if balanceErr != nil {
+		return nil, fmt.Errorf("获取账户余额失败: %w", balanceErr)
+	}
+	if positionsErr != nil {
+		return nil, fmt.Errorf("获取持仓失败: %w", positionsErr)
+	}
+
+	snap := &AccountSnapshot{
+		SnapshotTime: time.Now(),
+		Balance:      balance,
+		Positions:    positions,
+		FundingRates: make(map[string]float64),
+	}
+
+	// 获取持仓币种的资金费率（失败不影响快照）
+	for _, pos := range positions {
+		symbol, _ := pos["symbol"].(string)
+		if symbol == "" {
+			continue
+		}
+		if _, ok := snap.FundingRates[symbol]; ok {
+			continue
+		}
+		info, err := market.GetFundingInfo(symbol)
+		if err != nil {
+			log.Printf("⚠️  获取 %s 资金费率失败: %v", symbol, err)
+			continue
+		}
+		snap.FundingRates[symbol] = info.FundingRate
+	}
+
+	return snap, nil
+}
+
+// SerializeSnapshot 将快照序列化为JSON
+func SerializeSnapshot(snap *AccountSnapshot) ([]byte, error) {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil, fmt.Errorf("序列化账户快照失败: %w", err)
+	}
+	return data, nil
+}
+
+// DeserializeSnapshot 从JSON反序列化快照
+func DeserializeSnapshot(data []byte) (*AccountSnapshot, error) {
+	var snap AccountSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("解析账户快照失败: %w", err)
+	}
+	return &snap, nil
+}
+
+// DiffSnapshots 比较两个快照，返回余额和持仓的变化
+func DiffSnapshots(before, after *AccountSnapshot) *SnapshotDiff {
+	diff := &SnapshotDiff{
+		Elapsed:                after.SnapshotTime.Sub(before.SnapshotTime),
+		WalletBalanceChange:    balanceField(after, "totalWalletBalance") - balanceField(before, "totalWalletBalance"),
+		AvailableBalanceChange: balanceField(after, "availableBalance") - balanceField(before, "availableBalance"),
+		UnrealizedPnLChange:    balanceField(after, "totalUnrealizedProfit") - balanceField(before, "totalUnrealizedProfit"),
+		QuantityChanges:        make(map[string]float64),
+	}
+
+	beforeQty := snapshotQuantities(before)
+	afterQty := snapshotQuantities(after)
+
+	for key, qty := range afterQty {
+		prev, existed := beforeQty[key]
+		if !existed {
+			diff.OpenedPositions = append(diff.OpenedPositions, key)
+		}
+		if qty != prev {
+			diff.QuantityChanges[key] = qty - prev
+		}
+	}
+	for key, prev := range beforeQty {
+		if _, exists := afterQty[key]; !exists {
+			diff.ClosedPositions = append(diff.ClosedPositions, key)
+			diff.QuantityChanges[key] = -prev
+		}
+	}
+
+	return diff
+}
+
+// balanceField 读取快照余额字段
+func balanceField(snap *AccountSnapshot, key string) float64 {
+	value, _ := snap.Balance[key].(float64)
+	return value
+}
+
+// snapshotQuantities 以 symbol_side 为key汇总持仓数量
+func snapshotQuantities(snap *AccountSnapshot) map[string]float64 {
+	quantities := make(map[string]float64)
+	for _, pos := range snap.Positions {
+		symbol, _ := pos["symbol"].(string)
+		side, _ := pos["side"].(string)
+		qty, _ := pos["positionAmt"].(float64)
+		quantities[symbol+"_"+side] = absFloat(qty)
+	}
+	return quantities
+}
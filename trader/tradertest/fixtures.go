@@ -0,0 +1,233 @@
+package tradertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DefaultIgnoredParams 匹配请求时忽略的参数（时间戳和签名每次请求都不同）
+var DefaultIgnoredParams = []string{"timestamp", "signature", "sign", "recvWindow", "nonce"}
+
+// Fixture 一条录制的请求/响应
+type Fixture struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query"` // 去掉忽略参数后按key排序的querystring
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// Cassette 一组按录制顺序保存的响应
+type Cassette struct {
+	Fixtures []Fixture `json:"fixtures"`
+}
+
+// LoadCassette 从JSON文件读取录制的响应
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取录制文件失败: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("解析录制文件失败: %w", err)
+	}
+	return &c, nil
+}
+
+// Save 保存为JSON文件
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("保存录制文件失败: %w", err)
+	}
+	return nil
+}
+
+// normalizeQuery 去掉忽略的参数并按key排序
+func normalizeQuery(query url.Values, ignored []string) string {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	for _, k := range ignored {
+		q.Del(k)
+	}
+	return q.Encode() // Encode 按key排序
+}
+
+// fixtureKey 匹配键
+func fixtureKey(method, path, query string) string {
+	return method + " " + path + "?" + query
+}
+
+// Recorder 录制服务器：把请求转发到真实交易所并保存响应
+// 连接器的baseURL指向 Recorder.URL 后正常调用，结束时用 Cassette().Save 保存
+type Recorder struct {
+	*httptest.Server
+
+	upstream *url.URL
+	ignored  []string
+	client   *http.Client
+	cassette Cassette
+	mu       sync.Mutex
+}
+
+// NewRecorder 创建录制服务器，upstream 为真实接口地址（如 https://www.okx.com）
+// ignoredParams 为空时使用 DefaultIgnoredParams
+func NewRecorder(upstream string, ignoredParams ...string) (*Recorder, error) {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return nil, fmt.Errorf("解析upstream地址失败: %w", err)
+	}
+	if len(ignoredParams) == 0 {
+		ignoredParams = DefaultIgnoredParams
+	}
+
+	r := &Recorder{upstream: u, ignored: ignoredParams, client: &http.Client{}}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.handle))
+	return r, nil
+}
+
+// handle 转发请求（保留认证头）并记录响应
+func (r *Recorder) handle(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	target := *r.upstream
+	target.Path = strings.TrimSuffix(target.Path, "/") + req.URL.Path
+	target.RawQuery = req.URL.RawQuery
+
+	out, err := http.NewRequest(req.Method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	out.Header = req.Header.Clone()
+
+	resp, err := r.client.Do(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	// 非JSON响应按字符串保存，保证录制文件仍是合法JSON
+	raw := json.RawMessage(respBody)
+	if !json.Valid(respBody) {
+		raw, _ = json.Marshal(string(respBody))
+	}
+
+	r.mu.Lock()
+	r.cassette.Fixtures = append(r.cassette.Fixtures, Fixture{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  normalizeQuery(req.URL.Query(), r.ignored),
+		Status: resp.StatusCode,
+		Body:   raw,
+	})
+	r.mu.Unlock()
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	w.Write(respBody)
+}
+
+// Cassette 返回目前录制的响应
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Fixtures: append([]Fixture(nil), r.cassette.Fixtures...)}
+}
+
+// Replayer 回放服务器：按 方法+路径+参数 匹配录制的响应，不访问网络
+// 同一请求录制了多次时按录制顺序依次返回，用完后重复返回最后一次
+type Replayer struct {
+	*httptest.Server
+
+	ignored   []string
+	queues    map[string][]Fixture
+	last      map[string]Fixture
+	unmatched []string
+	mu        sync.Mutex
+}
+
+// NewReplayer 创建回放服务器，ignoredParams 为空时使用 DefaultIgnoredParams
+func NewReplayer(cassette *Cassette, ignoredParams ...string) *Replayer {
+	if len(ignoredParams) == 0 {
+		ignoredParams = DefaultIgnoredParams
+	}
+
+	r := &Replayer{
+		ignored: ignoredParams,
+		queues:  make(map[string][]Fixture),
+		last:    make(map[string]Fixture),
+	}
+	for _, f := range cassette.Fixtures {
+		key := fixtureKey(f.Method, f.Path, f.Query)
+		r.queues[key] = append(r.queues[key], f)
+	}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.handle))
+	return r
+}
+
+// handle 返回匹配的录制响应，没有匹配时返回404并记录
+func (r *Replayer) handle(w http.ResponseWriter, req *http.Request) {
+	key := fixtureKey(req.Method, req.URL.Path, normalizeQuery(req.URL.Query(), r.ignored))
+
+	r.mu.Lock()
+	f, ok := r.last[key]
+	if queue := r.queues[key]; len(queue) > 0 {
+		f, ok = queue[0], true
+		r.queues[key] = queue[1:]
+		r.last[key] = f
+	}
+	if !ok {
+		r.unmatched = append(r.unmatched, key)
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "tradertest: 没有匹配的录制响应: "+key, http.StatusNotFound)
+		return
+	}
+
+	body := []byte(f.Body)
+	var s string
+	if json.Unmarshal(f.Body, &s) == nil {
+		body = []byte(s) // 录制时的非JSON响应
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(f.Status)
+	w.Write(body)
+}
+
+// Unmatched 返回没有匹配到录制响应的请求（按请求顺序）
+func (r *Replayer) Unmatched() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.unmatched...)
+}
+
+// Pending 返回尚未被请求过的录制响应数量，用于检查测试是否走完了预期的调用
+func (r *Replayer) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, queue := range r.queues {
+		n += len(queue)
+	}
+	return n
+}
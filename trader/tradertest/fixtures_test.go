@@ -0,0 +1,139 @@
+package tradertest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"nofx/market"
+	"nofx/trader"
+)
+
+// newOKXUpstream 模拟OKX行情接口，每次请求最新价加1
+func newOKXUpstream() *httptest.Server {
+	var seq int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v5/market/ticker" {
+			http.NotFound(w, r)
+			return
+		}
+		n := atomic.AddInt32(&seq, 1)
+		fmt.Fprintf(w, `{"code":"0","msg":"","data":[{"instId":%q,"last":"%d"}]}`, r.URL.Query().Get("instId"), 50000+n)
+	}))
+}
+
+func TestRecordAndReplayOKXPriceSource(t *testing.T) {
+	upstream := newOKXUpstream()
+	defer upstream.Close()
+
+	// 录制：通过 Recorder 访问上游两次
+	rec, err := NewRecorder(upstream.URL)
+	if err != nil {
+		t.Fatalf("NewRecorder 返回错误: %v", err)
+	}
+	source := trader.NewOKXPriceSource(rec.URL)
+	for _, want := range []float64{50001, 50002} {
+		price, err := source("BTCUSDT")
+		if err != nil {
+			t.Fatalf("录制时获取价格失败: %v", err)
+		}
+		if price != want {
+			t.Errorf("录制时价格 = %v, want %v", price, want)
+		}
+	}
+	rec.Close()
+
+	path := filepath.Join(t.TempDir(), "okx_ticker.json")
+	if err := rec.Cassette().Save(path); err != nil {
+		t.Fatalf("保存录制文件失败: %v", err)
+	}
+
+	// 回放：上游关闭后按录制顺序返回，用完后重复最后一次
+	upstream.Close()
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("读取录制文件失败: %v", err)
+	}
+	if len(cassette.Fixtures) != 2 {
+		t.Fatalf("录制条数 = %d, want 2", len(cassette.Fixtures))
+	}
+
+	replayer := NewReplayer(cassette)
+	defer replayer.Close()
+	source = trader.NewOKXPriceSource(replayer.URL)
+
+	for _, want := range []float64{50001, 50002, 50002} {
+		price, err := source("BTCUSDT")
+		if err != nil {
+			t.Fatalf("回放时获取价格失败: %v", err)
+		}
+		if price != want {
+			t.Errorf("回放价格 = %v, want %v", price, want)
+		}
+	}
+	if n := replayer.Pending(); n != 0 {
+		t.Errorf("Pending = %d, want 0", n)
+	}
+
+	// 没有录制的交易对返回404并记录
+	if _, err := source("ETHUSDT"); err == nil {
+		t.Error("未录制的请求应返回错误")
+	}
+	if unmatched := replayer.Unmatched(); len(unmatched) != 1 {
+		t.Errorf("Unmatched = %v, want 1条", unmatched)
+	}
+}
+
+func TestReplayPaperTrader(t *testing.T) {
+	cassette := &Cassette{Fixtures: []Fixture{{
+		Method: "GET",
+		Path:   "/api/v5/market/ticker",
+		Query:  "instId=BTC-USDT-SWAP",
+		Status: http.StatusOK,
+		Body:   []byte(`{"code":"0","msg":"","data":[{"last":"60000"}]}`),
+	}}}
+	replayer := NewReplayer(cassette)
+	defer replayer.Close()
+
+	paper := trader.NewPaperTraderWithPriceSource(10000, trader.NewOKXPriceSource(replayer.URL))
+	if _, err := paper.OpenLong("BTCUSDT", 0.1, 5); err != nil {
+		t.Fatalf("OpenLong 返回错误: %v", err)
+	}
+
+	positions, err := paper.GetPositions()
+	if err != nil {
+		t.Fatalf("GetPositions 返回错误: %v", err)
+	}
+	if len(positions) != 1 || positions[0]["entryPrice"] != 60000.0 {
+		t.Errorf("持仓 = %v, want 以60000开仓的多仓", positions)
+	}
+	if unmatched := replayer.Unmatched(); len(unmatched) != 0 {
+		t.Errorf("Unmatched = %v", unmatched)
+	}
+}
+
+func TestOKXPriceSourceMidPrice(t *testing.T) {
+	cassette := &Cassette{Fixtures: []Fixture{{
+		Method: "GET",
+		Path:   "/api/v5/market/ticker",
+		Query:  "instId=BTC-USDT-SWAP",
+		Status: http.StatusOK,
+		Body:   []byte(`{"code":"0","msg":"","data":[{"last":"60005","bidPx":"59990","askPx":"60000"}]}`),
+	}}}
+	replayer := NewReplayer(cassette)
+	defer replayer.Close()
+	source := trader.NewOKXPriceSource(replayer.URL)
+
+	if price, err := source("BTCUSDT"); err != nil || price != 60005 {
+		t.Errorf("最新价 = %v, %v, want 60005", price, err)
+	}
+
+	market.SetUseMidPrice(true)
+	defer market.SetUseMidPrice(false)
+	if price, err := source("BTCUSDT"); err != nil || price != 59995 {
+		t.Errorf("中间价 = %v, %v, want 59995", price, err)
+	}
+}
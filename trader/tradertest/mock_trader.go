@@ -0,0 +1,213 @@
+// Package tradertest 提供交易器相关的测试工具：
+// MockTrader 用于在不连接交易所的情况下测试策略代码，
+// Recorder/Replayer 用于录制和回放交易所HTTP响应（如OKX），对连接器做集成测试
+package tradertest
+
+import (
+	"fmt"
+	"nofx/trader"
+	"strconv"
+	"sync"
+)
+
+// Call 一次方法调用记录
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// MockTrader 可编程的 trader.Trader 实现
+// 返回值取自公开字段，Errors 可按方法名注入错误，所有调用按顺序记录在 Calls 中
+type MockTrader struct {
+	Balance   map[string]interface{}
+	Positions []map[string]interface{}
+	Prices    map[string]float64
+	Errors    map[string]error // 方法名 -> 返回的错误，如 "OpenLong"
+
+	// QuantityDecimals FormatQuantity 使用的小数位数
+	QuantityDecimals int
+
+	calls    []Call
+	orderSeq int
+	mu       sync.Mutex
+}
+
+var _ trader.Trader = (*MockTrader)(nil)
+
+// NewMockTrader 创建MockTrader，余额为 balance USDT，没有持仓
+func NewMockTrader(balance float64) *MockTrader {
+	return &MockTrader{
+		Balance: map[string]interface{}{
+			"totalWalletBalance":    balance,
+			"availableBalance":      balance,
+			"totalUnrealizedProfit": 0.0,
+		},
+		Positions:        []map[string]interface{}{},
+		Prices:           map[string]float64{},
+		Errors:           map[string]error{},
+		QuantityDecimals: 3,
+	}
+}
+
+// record 记录调用并返回注入的错误（调用方需持有锁）
+func (m *MockTrader) record(method string, args ...interface{}) error {
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+	return m.Errors[method]
+}
+
+// orderResult 生成下单结果（调用方需持有锁）
+func (m *MockTrader) orderResult(symbol string) map[string]interface{} {
+	m.orderSeq++
+	return map[string]interface{}{
+		"orderId": fmt.Sprintf("mock-%d", m.orderSeq),
+		"symbol":  symbol,
+		"status":  "FILLED",
+	}
+}
+
+// Calls 返回所有调用记录
+func (m *MockTrader) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo 返回指定方法的调用记录
+func (m *MockTrader) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := []Call{}
+	for _, c := range m.calls {
+		if c.Method == method {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Reset 清空调用记录
+func (m *MockTrader) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+// GetBalance 返回 Balance
+func (m *MockTrader) GetBalance() (map[string]interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("GetBalance"); err != nil {
+		return nil, err
+	}
+	return m.Balance, nil
+}
+
+// GetPositions 返回 Positions
+func (m *MockTrader) GetPositions() ([]map[string]interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("GetPositions"); err != nil {
+		return nil, err
+	}
+	return m.Positions, nil
+}
+
+// OpenLong 记录开多仓
+func (m *MockTrader) OpenLong(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("OpenLong", symbol, quantity, leverage); err != nil {
+		return nil, err
+	}
+	return m.orderResult(symbol), nil
+}
+
+// OpenShort 记录开空仓
+func (m *MockTrader) OpenShort(symbol string, quantity float64, leverage int) (map[string]interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("OpenShort", symbol, quantity, leverage); err != nil {
+		return nil, err
+	}
+	return m.orderResult(symbol), nil
+}
+
+// CloseLong 记录平多仓
+func (m *MockTrader) CloseLong(symbol string, quantity float64) (map[string]interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("CloseLong", symbol, quantity); err != nil {
+		return nil, err
+	}
+	return m.orderResult(symbol), nil
+}
+
+// CloseShort 记录平空仓
+func (m *MockTrader) CloseShort(symbol string, quantity float64) (map[string]interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("CloseShort", symbol, quantity); err != nil {
+		return nil, err
+	}
+	return m.orderResult(symbol), nil
+}
+
+// SetLeverage 记录设置杠杆
+func (m *MockTrader) SetLeverage(symbol string, leverage int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("SetLeverage", symbol, leverage)
+}
+
+// SetMarginMode 记录设置仓位模式
+func (m *MockTrader) SetMarginMode(symbol string, isCrossMargin bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("SetMarginMode", symbol, isCrossMargin)
+}
+
+// GetMarketPrice 返回 Prices 中的价格
+func (m *MockTrader) GetMarketPrice(symbol string) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("GetMarketPrice", symbol); err != nil {
+		return 0, err
+	}
+	price, ok := m.Prices[symbol]
+	if !ok {
+		return 0, fmt.Errorf("未找到 %s 的行情", symbol)
+	}
+	return price, nil
+}
+
+// SetStopLoss 记录设置止损
+func (m *MockTrader) SetStopLoss(symbol string, positionSide string, quantity, stopPrice float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("SetStopLoss", symbol, positionSide, quantity, stopPrice)
+}
+
+// SetTakeProfit 记录设置止盈
+func (m *MockTrader) SetTakeProfit(symbol string, positionSide string, quantity, takeProfitPrice float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("SetTakeProfit", symbol, positionSide, quantity, takeProfitPrice)
+}
+
+// CancelAllOrders 记录取消挂单
+func (m *MockTrader) CancelAllOrders(symbol string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.record("CancelAllOrders", symbol)
+}
+
+// FormatQuantity 按 QuantityDecimals 格式化数量
+func (m *MockTrader) FormatQuantity(symbol string, quantity float64) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.record("FormatQuantity", symbol, quantity); err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(quantity, 'f', m.QuantityDecimals, 64), nil
+}
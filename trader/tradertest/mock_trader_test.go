@@ -0,0 +1,57 @@
+package tradertest
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMockTraderRecordsCalls(t *testing.T) {
+	m := NewMockTrader(1000)
+	m.Prices["BTCUSDT"] = 50000
+
+	if _, err := m.OpenLong("BTCUSDT", 0.01, 10); err != nil {
+		t.Fatalf("OpenLong 返回错误: %v", err)
+	}
+	if err := m.SetStopLoss("BTCUSDT", "LONG", 0.01, 49000); err != nil {
+		t.Fatalf("SetStopLoss 返回错误: %v", err)
+	}
+	if price, err := m.GetMarketPrice("BTCUSDT"); err != nil || price != 50000 {
+		t.Errorf("GetMarketPrice = %v, %v, want 50000", price, err)
+	}
+	if _, err := m.GetMarketPrice("ETHUSDT"); err == nil {
+		t.Error("没有设置价格的交易对应返回错误")
+	}
+
+	if n := len(m.Calls()); n != 4 {
+		t.Errorf("调用次数 = %d, want 4", n)
+	}
+	opens := m.CallsTo("OpenLong")
+	if len(opens) != 1 {
+		t.Fatalf("OpenLong 调用次数 = %d, want 1", len(opens))
+	}
+	if args := opens[0].Args; args[0] != "BTCUSDT" || args[1] != 0.01 || args[2] != 10 {
+		t.Errorf("OpenLong 参数 = %v", args)
+	}
+
+	m.Reset()
+	if n := len(m.Calls()); n != 0 {
+		t.Errorf("Reset 后调用次数 = %d, want 0", n)
+	}
+}
+
+func TestMockTraderInjectedError(t *testing.T) {
+	m := NewMockTrader(1000)
+	injected := errors.New("余额不足")
+	m.Errors["OpenShort"] = injected
+
+	if _, err := m.OpenShort("BTCUSDT", 0.01, 5); !errors.Is(err, injected) {
+		t.Errorf("err = %v, want %v", err, injected)
+	}
+	// 注入错误时仍然记录调用
+	if n := len(m.CallsTo("OpenShort")); n != 1 {
+		t.Errorf("OpenShort 调用次数 = %d, want 1", n)
+	}
+	if _, err := m.OpenLong("BTCUSDT", 0.01, 5); err != nil {
+		t.Errorf("未注入错误的方法不应失败: %v", err)
+	}
+}
@@ -118,6 +118,8 @@ export interface Exchange {
   asterUser?: string
   asterSigner?: string
   asterPrivateKey?: string
+  // Bitget 等交易所的API口令
+  passphrase?: string
 }
 
 export interface CreateTraderRequest {
@@ -161,6 +163,8 @@ export interface UpdateExchangeConfigRequest {
       aster_user?: string
       aster_signer?: string
       aster_private_key?: string
+      // Bitget 等交易所的API口令
+      passphrase?: string
     }
   }
 }